@@ -0,0 +1,243 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package alienclient provides a client for the alien consensus RPC API.
+package alienclient
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/TTCECO/gttc"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/ethclient"
+	"github.com/TTCECO/gttc/event"
+	"github.com/TTCECO/gttc/params"
+	"github.com/TTCECO/gttc/rpc"
+)
+
+// Client defines typed wrappers for the alien consensus RPC API.
+type Client struct {
+	c  *rpc.Client
+	ec *ethclient.Client
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connects a client to the given URL, aborting if the context is
+// canceled before the connection is established.
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client that uses the given RPC client.
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c: c, ec: ethclient.NewClient(c)}
+}
+
+// Close closes the underlying RPC connection.
+func (ac *Client) Close() {
+	ac.c.Close()
+}
+
+// Candidate is a candidate for the signer queue along with its stake.
+type Candidate struct {
+	Address common.Address `json:"address"`
+	State   uint64         `json:"state"` // 0 - adding procedure, 1 - normal, 2 - removing procedure
+	Stake   *big.Int       `json:"stake"`
+}
+
+// Loop is the start of a new loop of the signer queue.
+type Loop struct {
+	Number    uint64           `json:"number"`    // Number of the first head seen in the loop
+	Hash      common.Hash      `json:"hash"`      // Hash of the first head seen in the loop
+	StartTime uint64           `json:"startTime"` // Start time of the loop
+	Signers   []common.Address `json:"signers"`   // Signer queue of the loop
+}
+
+// Snapshot returns the alien snapshot at the given block. If number is nil, the
+// snapshot at the latest known block is returned.
+func (ac *Client) Snapshot(ctx context.Context, number *big.Int) (*alien.Snapshot, error) {
+	snap := new(alien.Snapshot)
+	var err error
+	if number == nil {
+		err = ac.c.CallContext(ctx, snap, "alien_getSnapshot", nil)
+	} else {
+		err = ac.c.CallContext(ctx, snap, "alien_getSnapshotAtNumber", number.Uint64())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// SnapshotAtHash returns the alien snapshot at the given block.
+func (ac *Client) SnapshotAtHash(ctx context.Context, hash common.Hash) (*alien.Snapshot, error) {
+	snap := new(alien.Snapshot)
+	if err := ac.c.CallContext(ctx, snap, "alien_getSnapshotAtHash", hash); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// SignerQueue returns the signer queue of the loop in progress at the given
+// block, in sealing order.
+func (ac *Client) SignerQueue(ctx context.Context, number *big.Int) ([]common.Address, error) {
+	snap, err := ac.Snapshot(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return signerQueue(snap), nil
+}
+
+// Candidates returns the candidates at the given block, by descending stake.
+func (ac *Client) Candidates(ctx context.Context, number *big.Int) ([]*Candidate, error) {
+	snap, err := ac.Snapshot(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]*Candidate, 0, len(snap.Candidates))
+	for addr, state := range snap.Candidates {
+		stake := new(big.Int)
+		if tally, ok := snap.Tally[addr]; ok {
+			stake.Set(tally)
+		}
+		candidates = append(candidates, &Candidate{Address: addr, State: state, Stake: stake})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if cmp := candidates[i].Stake.Cmp(candidates[j].Stake); cmp != 0 {
+			return cmp > 0
+		}
+		return bytes.Compare(candidates[i].Address[:], candidates[j].Address[:]) < 0
+	})
+	return candidates, nil
+}
+
+// Proposals returns the open proposals at the given block, by received block.
+func (ac *Client) Proposals(ctx context.Context, number *big.Int) ([]*alien.Proposal, error) {
+	snap, err := ac.Snapshot(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	proposals := make([]*alien.Proposal, 0, len(snap.Proposals))
+	for _, proposal := range snap.Proposals {
+		proposals = append(proposals, proposal)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		if cmp := proposals[i].ReceivedNumber.Cmp(proposals[j].ReceivedNumber); cmp != 0 {
+			return cmp < 0
+		}
+		return bytes.Compare(proposals[i].Hash[:], proposals[j].Hash[:]) < 0
+	})
+	return proposals, nil
+}
+
+// SideChainRecords returns the confirmation records of the side chains at the
+// given block, by side chain hash.
+func (ac *Client) SideChainRecords(ctx context.Context, number *big.Int) (map[common.Hash]*alien.SCRecord, error) {
+	snap, err := ac.Snapshot(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if snap.SCRecordMap == nil {
+		return make(map[common.Hash]*alien.SCRecord), nil
+	}
+	return snap.SCRecordMap, nil
+}
+
+// HeaderExtra returns the consensus content of the extra data of the given
+// block. If number is nil, the latest known header is decoded. The chain config
+// selects the encoding of the extra data, which changes across forks.
+func (ac *Client) HeaderExtra(ctx context.Context, config *params.AlienConfig, number *big.Int) (*alien.HeaderExtra, error) {
+	header, err := ac.ec.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeHeaderExtra(config, header)
+}
+
+// DecodeHeaderExtra decodes the consensus content of the extra data of a header
+// fetched over RPC.
+func DecodeHeaderExtra(config *params.AlienConfig, header *types.Header) (*alien.HeaderExtra, error) {
+	return alien.DecodeHeaderExtra(config, header)
+}
+
+// SubscribeNewLoop subscribes to notifications about new loops of the signer
+// queue. A loop is delivered when the first head carrying its start time is
+// imported, the loop in progress when subscribing is not delivered.
+func (ac *Client) SubscribeNewLoop(ctx context.Context, ch chan<- *Loop) (ethereum.Subscription, error) {
+	heads := make(chan *types.Header, 16)
+	sub, err := ac.ec.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+
+		var last uint64
+		for {
+			select {
+			case head := <-heads:
+				snap, err := ac.SnapshotAtHash(context.Background(), head.Hash())
+				if err != nil {
+					return err
+				}
+				if last == 0 || snap.LoopStartTime == last {
+					last = snap.LoopStartTime
+					continue
+				}
+				last = snap.LoopStartTime
+				loop := &Loop{
+					Number:    head.Number.Uint64(),
+					Hash:      head.Hash(),
+					StartTime: snap.LoopStartTime,
+					Signers:   signerQueue(snap),
+				}
+				select {
+				case ch <- loop:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// signerQueue flattens the signer queue of a snapshot.
+func signerQueue(snap *alien.Snapshot) []common.Address {
+	signers := make([]common.Address, 0, len(snap.Signers))
+	for _, signer := range snap.Signers {
+		signers = append(signers, *signer)
+	}
+	return signers
+}
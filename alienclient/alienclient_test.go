@@ -0,0 +1,405 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alienclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/core/vm"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+	"github.com/TTCECO/gttc/rpc"
+)
+
+const testGenesisTime = 1546300800 // Far in the past, so blocks are sealed without waiting
+
+var (
+	testBalance  = new(big.Int).Mul(big.NewInt(1e+6), big.NewInt(1e+18))
+	testGasPrice = big.NewInt(1e+9)
+)
+
+// testBackend is an in-process alien chain sealed by a few local signers.
+type testBackend struct {
+	t      *testing.T
+	config *params.ChainConfig
+	chain  *core.BlockChain
+	engine *alien.Alien
+	keys   map[common.Address]*ecdsa.PrivateKey
+	server *rpc.Server
+}
+
+func newTestBackend(t *testing.T, signers int) *testBackend {
+	alienConfig := &params.AlienConfig{
+		Period:           1,
+		Epoch:            30000,
+		MaxSignerCount:   uint64(signers),
+		MinVoterBalance:  big.NewInt(100),
+		GenesisTimestamp: testGenesisTime,
+		LightConfig:      &params.AlienLightConfig{Alloc: make(map[common.UnprefixedAddress]params.GenesisAccount)},
+	}
+	alloc := make(core.GenesisAlloc)
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	for i := 0; i < signers; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		alloc[addr] = core.GenesisAccount{Balance: testBalance}
+		alienConfig.SelfVoteSigners = append(alienConfig.SelfVoteSigners, common.UnprefixedAddress(addr))
+		alienConfig.LightConfig.Alloc[common.UnprefixedAddress(addr)] = params.GenesisAccount{Balance: testBalance.String()}
+	}
+	config := *params.AllAlienProtocolChanges
+	config.Alien = alienConfig
+
+	db := ethdb.NewMemDatabase()
+	genesis := (&core.Genesis{
+		Config:    &config,
+		Timestamp: testGenesisTime,
+		ExtraData: make([]byte, 32+65),
+		GasLimit:  params.GenesisGasLimit,
+		Alloc:     alloc,
+	}).MustCommit(db)
+
+	engine := alien.New(alienConfig, db)
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if err := engine.ApplyGenesis(chain, genesis.Hash()); err != nil {
+		t.Fatalf("failed to apply genesis: %v", err)
+	}
+	server := rpc.NewServer()
+	for _, api := range engine.APIs(chain) {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := server.RegisterName("eth", &TestEthAPI{chain: chain}); err != nil {
+		t.Fatal(err)
+	}
+	return &testBackend{t: t, config: &config, chain: chain, engine: engine, keys: keys, server: server}
+}
+
+// signTx signs a tx with the key of the given signer.
+func (b *testBackend) signTx(from common.Address, tx *types.Transaction) *types.Transaction {
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(b.config.ChainId), b.keys[from])
+	if err != nil {
+		b.t.Fatalf("failed to sign tx: %v", err)
+	}
+	return signed
+}
+
+// nonce returns the nonce of the given account at the head of the chain.
+func (b *testBackend) nonce(addr common.Address) uint64 {
+	statedb, err := b.chain.State()
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return statedb.GetNonce(addr)
+}
+
+// seal seals and imports a block with the given txs in the next slot.
+func (b *testBackend) seal(txs ...*types.Transaction) *types.Block {
+	parent := b.chain.CurrentBlock()
+	snap, err := b.engine.Snapshot(b.chain, parent.Header())
+	if err != nil {
+		b.t.Fatalf("failed to retrieve snapshot: %v", err)
+	}
+	period := b.config.Alien.Period
+	slot := parent.Time().Uint64() + period
+	signer := *snap.Signers[(slot-snap.LoopStartTime)/period%uint64(len(snap.Signers))]
+	key := b.keys[signer]
+	b.engine.Authorize(signer, func(account accounts.Account, hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	}, nil)
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+		Time:       new(big.Int).SetUint64(slot),
+		Coinbase:   signer,
+		Extra:      make([]byte, 32),
+		Difficulty: big.NewInt(1),
+	}
+	statedb, err := b.chain.StateAt(parent.Root())
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	var receipts []*types.Receipt
+	for i, tx := range txs {
+		statedb.Prepare(tx.Hash(), common.Hash{}, i)
+		receipt, _, err := core.ApplyTransaction(b.config, b.chain, &header.Coinbase, gp, statedb, header, tx, &header.GasUsed, vm.Config{})
+		if err != nil {
+			b.t.Fatalf("failed to apply tx %x: %v", tx.Hash(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	block, err := b.engine.Finalize(b.chain, header, statedb, txs, nil, receipts)
+	if err != nil {
+		b.t.Fatalf("failed to finalize block: %v", err)
+	}
+	if block, err = b.engine.Seal(b.chain, block, make(chan struct{})); err != nil {
+		b.t.Fatalf("failed to seal block: %v", err)
+	}
+	if _, err := b.chain.InsertChain(types.Blocks{block}); err != nil {
+		b.t.Fatalf("failed to import block: %v", err)
+	}
+	return block
+}
+
+// TestEthAPI serves the few eth methods the client relies on.
+type TestEthAPI struct {
+	chain *core.BlockChain
+}
+
+func (api *TestEthAPI) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber {
+		return api.chain.CurrentHeader(), nil
+	}
+	return api.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (api *TestEthAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := api.chain.SubscribeChainHeadEvent(heads)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-heads:
+				notifier.Notify(rpcSub.ID, ev.Block.Header())
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+func TestSnapshotAccessors(t *testing.T) {
+	backend := newTestBackend(t, 3)
+	for i := 0; i < 2; i++ {
+		backend.seal()
+	}
+	client := NewClient(rpc.DialInProc(backend.server))
+	defer client.Close()
+	ctx := context.Background()
+
+	snap, err := client.Snapshot(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve snapshot: %v", err)
+	}
+	if snap.Number != 2 {
+		t.Errorf("snapshot number mismatch: have %d, want 2", snap.Number)
+	}
+	if snap, err = client.Snapshot(ctx, big.NewInt(1)); err != nil || snap.Number != 1 {
+		t.Errorf("snapshot at 1 mismatch: have %v, %v", snap, err)
+	}
+	if snap, err = client.SnapshotAtHash(ctx, backend.chain.CurrentHeader().Hash()); err != nil || snap.Number != 2 {
+		t.Errorf("snapshot at hash mismatch: have %v, %v", snap, err)
+	}
+	queue, err := client.SignerQueue(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve signer queue: %v", err)
+	}
+	if len(queue) != 3 {
+		t.Errorf("signer queue length mismatch: have %d, want 3", len(queue))
+	}
+	candidates, err := client.Candidates(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve candidates: %v", err)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("candidate count mismatch: have %d, want 3", len(candidates))
+	}
+	for _, candidate := range candidates {
+		if _, ok := backend.keys[candidate.Address]; !ok {
+			t.Errorf("unexpected candidate %x", candidate.Address)
+		}
+		if candidate.Stake.Sign() <= 0 {
+			t.Errorf("candidate %x has no stake", candidate.Address)
+		}
+	}
+	records, err := client.SideChainRecords(ctx, nil)
+	if err != nil || len(records) != 0 {
+		t.Errorf("side chain records mismatch: have %v, %v", records, err)
+	}
+}
+
+func TestCustomTxs(t *testing.T) {
+	backend := newTestBackend(t, 3)
+	backend.seal()
+
+	client := NewClient(rpc.DialInProc(backend.server))
+	defer client.Close()
+	ctx := context.Background()
+
+	queue, err := client.SignerQueue(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposer, declarer, target := queue[0], queue[1], common.HexToAddress("0x1337")
+
+	// Submit a proposal and check it shows up in the header and the snapshot
+	tx, err := NewProposalTx(backend.nonce(proposer), proposer, &alien.Proposal{ProposalType: 1, TargetAddress: target, ValidationLoopCnt: 4}, 100000, testGasPrice)
+	if err != nil {
+		t.Fatalf("failed to build proposal: %v", err)
+	}
+	block := backend.seal(backend.signTx(proposer, tx))
+
+	extra, err := client.HeaderExtra(ctx, backend.config.Alien, block.Number())
+	if err != nil {
+		t.Fatalf("failed to decode header extra: %v", err)
+	}
+	if len(extra.CurrentBlockProposals) != 1 {
+		t.Fatalf("header proposal count mismatch: have %d, want 1", len(extra.CurrentBlockProposals))
+	}
+	proposals, err := client.Proposals(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve proposals: %v", err)
+	}
+	if len(proposals) != 1 {
+		t.Fatalf("proposal count mismatch: have %d, want 1", len(proposals))
+	}
+	if p := proposals[0]; p.Proposer != proposer || p.TargetAddress != target || p.ValidationLoopCnt != 4 {
+		t.Errorf("proposal mismatch: have %+v", p)
+	}
+	// Declare on the proposal and vote for another candidate
+	declare := backend.signTx(declarer, NewDeclareTx(backend.nonce(declarer), declarer, proposals[0].Hash, true, 100000, testGasPrice))
+	vote := backend.signTx(proposer, NewVoteTx(backend.nonce(proposer), declarer, 100000, testGasPrice))
+	block = backend.seal(declare, vote)
+
+	if extra, err = client.HeaderExtra(ctx, backend.config.Alien, nil); err != nil {
+		t.Fatalf("failed to decode header extra: %v", err)
+	}
+	if len(extra.CurrentBlockDeclares) != 1 || !extra.CurrentBlockDeclares[0].Decision {
+		t.Errorf("header declares mismatch: have %+v", extra.CurrentBlockDeclares)
+	}
+	if len(extra.CurrentBlockVotes) != 1 || extra.CurrentBlockVotes[0].Candidate != declarer {
+		t.Errorf("header votes mismatch: have %+v", extra.CurrentBlockVotes)
+	}
+	if proposals, err = client.Proposals(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if declares := proposals[0].Declares; len(declares) != 1 || declares[0].Declarer != declarer {
+		t.Errorf("proposal declares mismatch: have %+v", declares)
+	}
+	snap, err := client.Snapshot(ctx, block.Number())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vote := snap.Votes[proposer]; vote == nil || vote.Candidate != declarer {
+		t.Errorf("vote mismatch: have %+v", vote)
+	}
+}
+
+func TestProposalData(t *testing.T) {
+	tests := []*alien.Proposal{
+		{ProposalType: 1, TargetAddress: common.HexToAddress("0x01"), ValidationLoopCnt: 4},
+		{ProposalType: 2, TargetAddress: common.HexToAddress("0x02")},
+		{ProposalType: 3, MinerRewardPerThousand: 618},
+		{ProposalType: 4, SCHash: common.HexToHash("0x04"), SCBlockCountPerPeriod: 2, SCBlockRewardPerPeriod: 50},
+		{ProposalType: 5, SCHash: common.HexToHash("0x05")},
+		{ProposalType: 6, MinVoterBalance: 100},
+		{ProposalType: 7, ProposalDeposit: 20000},
+		{ProposalType: 8, SCHash: common.HexToHash("0x08"), TargetAddress: common.HexToAddress("0x08"), SCRentFee: 100, SCRentRate: 2, SCRentLength: 850000},
+	}
+	proposer := common.HexToAddress("0xff")
+	for i, want := range tests {
+		data, err := ProposalData(want)
+		if err != nil {
+			t.Fatalf("test %d: failed to encode proposal: %v", i, err)
+		}
+		decoded, err := alien.DecodeCustomTx(data, proposer, &proposer, new(big.Int))
+		if err != nil {
+			t.Fatalf("test %d: failed to decode proposal: %v", i, err)
+		}
+		have := decoded.Proposal
+		if have.ProposalType != want.ProposalType || have.TargetAddress != want.TargetAddress || have.SCHash != want.SCHash ||
+			have.MinerRewardPerThousand != want.MinerRewardPerThousand && want.ProposalType == 3 ||
+			have.MinVoterBalance != want.MinVoterBalance && want.ProposalType == 6 ||
+			have.ProposalDeposit != want.ProposalDeposit && want.ProposalType == 7 ||
+			have.SCRentFee != want.SCRentFee || have.SCRentLength != want.SCRentLength && want.ProposalType == 8 {
+			t.Errorf("test %d: proposal mismatch: have %+v, want %+v", i, have, want)
+		}
+		if want.ValidationLoopCnt != 0 && have.ValidationLoopCnt != want.ValidationLoopCnt {
+			t.Errorf("test %d: validation loop count mismatch: have %d, want %d", i, have.ValidationLoopCnt, want.ValidationLoopCnt)
+		}
+	}
+	if _, err := ProposalData(&alien.Proposal{ProposalType: 42}); err == nil {
+		t.Errorf("unknown proposal type accepted")
+	}
+}
+
+func TestSubscribeNewLoop(t *testing.T) {
+	backend := newTestBackend(t, 3)
+	backend.seal()
+
+	client := NewClient(rpc.DialInProc(backend.server))
+	defer client.Close()
+
+	loops := make(chan *Loop)
+	sub, err := client.SubscribeNewLoop(context.Background(), loops)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	var last uint64
+	for i := 0; i < 2; i++ {
+		// Seal blocks until the next loop is delivered
+		var loop *Loop
+		for loop == nil {
+			backend.seal()
+			select {
+			case loop = <-loops:
+			case err := <-sub.Err():
+				t.Fatalf("subscription failed: %v", err)
+			case <-time.After(100 * time.Millisecond):
+			}
+			if backend.chain.CurrentHeader().Number.Uint64() > 20 {
+				t.Fatalf("no new loop delivered")
+			}
+		}
+		if loop.StartTime <= last {
+			t.Errorf("loop %d: start time %d not after %d", i, loop.StartTime, last)
+		}
+		if len(loop.Signers) != 3 {
+			t.Errorf("loop %d: signer count mismatch: have %d, want 3", i, len(loop.Signers))
+		}
+		last = loop.StartTime
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alienclient
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core/types"
+)
+
+// customTxPrefix is the prefix of the data of all alien event custom txs.
+const customTxPrefix = "ufo:1:event:"
+
+// errUnknownProposalType is returned when building a proposal of a type the
+// alien engine does not know about.
+var errUnknownProposalType = errors.New("unknown proposal type")
+
+// VoteData returns the data of a custom tx voting for its recipient.
+func VoteData() []byte {
+	return []byte(customTxPrefix + "vote")
+}
+
+// ProposalData returns the data of a custom tx submitting the given proposal.
+// Only the proposal type, the validation loop count (if set) and the fields
+// used by the proposal type are encoded.
+func ProposalData(p *alien.Proposal) ([]byte, error) {
+	fields := []string{"proposal_type", fmt.Sprint(p.ProposalType)}
+	switch p.ProposalType {
+	case 1, 2:
+		fields = append(fields, "candidate", p.TargetAddress.Hex())
+	case 3:
+		fields = append(fields, "mrpt", fmt.Sprint(p.MinerRewardPerThousand))
+	case 4:
+		fields = append(fields, "schash", p.SCHash.Hex(), "sccount", fmt.Sprint(p.SCBlockCountPerPeriod), "screward", fmt.Sprint(p.SCBlockRewardPerPeriod))
+	case 5:
+		fields = append(fields, "schash", p.SCHash.Hex())
+	case 6:
+		fields = append(fields, "mvb", fmt.Sprint(p.MinVoterBalance))
+	case 7:
+		fields = append(fields, "mpd", fmt.Sprint(p.ProposalDeposit))
+	case 8:
+		fields = append(fields, "schash", p.SCHash.Hex(), "scrt", p.TargetAddress.Hex(), "scrf", fmt.Sprint(p.SCRentFee), "scrr", fmt.Sprint(p.SCRentRate), "scrl", fmt.Sprint(p.SCRentLength))
+	default:
+		return nil, fmt.Errorf("%v: %d", errUnknownProposalType, p.ProposalType)
+	}
+	if p.ValidationLoopCnt != 0 {
+		fields = append(fields, "vlcnt", fmt.Sprint(p.ValidationLoopCnt))
+	}
+	return []byte(customTxPrefix + "proposal:" + strings.Join(fields, ":")), nil
+}
+
+// DeclareData returns the data of a custom tx declaring a decision on the
+// given proposal.
+func DeclareData(proposal common.Hash, decision bool) []byte {
+	value := "no"
+	if decision {
+		value = "yes"
+	}
+	return []byte(customTxPrefix + "declare:hash:" + proposal.Hex() + ":decision:" + value)
+}
+
+// NewVoteTx creates an unsigned custom tx voting for the given candidate with
+// the whole balance of the sender.
+func NewVoteTx(nonce uint64, candidate common.Address, gasLimit uint64, gasPrice *big.Int) *types.Transaction {
+	return types.NewTransaction(nonce, candidate, new(big.Int), gasLimit, gasPrice, VoteData())
+}
+
+// NewProposalTx creates an unsigned custom tx submitting the given proposal.
+// Proposal txs are sent by the proposer to itself, the deposit is frozen when
+// the tx is sealed.
+func NewProposalTx(nonce uint64, proposer common.Address, p *alien.Proposal, gasLimit uint64, gasPrice *big.Int) (*types.Transaction, error) {
+	data, err := ProposalData(p)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTransaction(nonce, proposer, new(big.Int), gasLimit, gasPrice, data), nil
+}
+
+// NewDeclareTx creates an unsigned custom tx declaring a decision on the given
+// proposal. Declare txs are sent by the declarer, a candidate, to itself.
+func NewDeclareTx(nonce uint64, declarer common.Address, proposal common.Hash, decision bool, gasLimit uint64, gasPrice *big.Int) *types.Transaction {
+	return types.NewTransaction(nonce, declarer, new(big.Int), gasLimit, gasPrice, DeclareData(proposal, decision))
+}
@@ -48,7 +48,7 @@ import (
 )
 
 // ExternalAPIVersion -- see extapi_changelog.md
-const ExternalAPIVersion = "2.0.0"
+const ExternalAPIVersion = "2.1.0"
 
 // InternalAPIVersion -- see intapi_changelog.md
 const InternalAPIVersion = "2.0.0"
@@ -119,6 +119,10 @@ var (
 		Name:  "stdio-ui-test",
 		Usage: "Mechanism to test interface between Clef and UI. Requires 'stdio-ui'.",
 	}
+	alienRPCFlag = cli.StringFlag{
+		Name:  "alien.rpc",
+		Usage: "RPC endpoint of a node to check alien custom transactions against its consensus state",
+	}
 	app         = cli.NewApp()
 	initCommand = cli.Command{
 		Action:    utils.MigrateFlags(initializeSecrets),
@@ -191,6 +195,7 @@ func init() {
 		ruleFlag,
 		stdiouiFlag,
 		testFlag,
+		alienRPCFlag,
 	}
 	app.Action = signer
 	app.Commands = []cli.Command{initCommand, attestCommand, addCredentialCommand}
@@ -386,6 +391,14 @@ func signer(c *cli.Context) error {
 		ui, db,
 		c.Bool(utils.LightKDFFlag.Name))
 
+	if endpoint := c.String(alienRPCFlag.Name); endpoint != "" {
+		client, err := rpc.Dial(endpoint)
+		if err != nil {
+			utils.Fatalf("Failed to connect to the alien node: %v", err)
+		}
+		apiImpl.SetAlienBackend(core.NewAlienRPCBackend(client))
+		log.Info("Alien consensus state checks enabled", "endpoint", endpoint)
+	}
 	api = apiImpl
 
 	// Audit logging
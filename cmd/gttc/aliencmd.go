@@ -0,0 +1,449 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/TTCECO/gttc/accounts/keystore"
+	"github.com/TTCECO/gttc/cmd/utils"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/core/vm"
+	"github.com/TTCECO/gttc/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	inspectMismatchesFlag = cli.BoolFlag{
+		Name:  "mismatches",
+		Usage: "Only print the blocks flagged with mismatches",
+	}
+	inspectNoReexecFlag = cli.BoolFlag{
+		Name:  "noreexec",
+		Usage: "Skip re-executing the blocks to compare their extra with Finalize",
+	}
+	checkpointSignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "Checkpoint key (address or index) to sign the checkpoint with",
+	}
+
+	alienCommand = cli.Command{
+		Name:      "alien",
+		Usage:     "Inspect the alien consensus data of the local chain",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Description: `
+The alien commands work directly on the chain database of a stopped node to
+debug the delegated-proof-of-stake consensus.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "inspect",
+				Usage:     "Decode and audit the alien header extra of blocks",
+				ArgsUsage: "<blockHash> | <blockNum> [<lastBlockNum>]",
+				Action:    utils.MigrateFlags(alienInspect),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					inspectMismatchesFlag,
+					inspectNoReexecFlag,
+				},
+				Description: `
+    gttc alien inspect 1000 2000
+
+Decodes the header extra of a block, or of a range of blocks, and prints it as
+JSON along with the recovered signer. Every block is audited against the
+snapshot of its parent: the signer must be the coinbase and in turn, and the
+missing signers recorded in the extra must match the recomputed ones.
+
+Unless --noreexec is given, blocks whose parent state is available are also
+re-executed, and any field of the extra differing from what Finalize produces
+is flagged as a mismatch.`,
+			},
+			{
+				Name:      "verifysnapshot",
+				Usage:     "Verify the alien snapshot of a block against a full replay",
+				ArgsUsage: "[<blockNum>]",
+				Action:    utils.MigrateFlags(alienVerifySnapshot),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+				},
+				Description: `
+    gttc alien verifysnapshot 1000000
+
+Rebuilds the snapshot after the given block, or after the current head, by
+replaying the headers from the genesis block, or from the trusted checkpoint if
+the block is past it. Every field is compared with the snapshots the node would
+use, in memory and on disk, and the differences are printed as JSON.`,
+			},
+			{
+				Name:  "checkpoint",
+				Usage: "Create, sign and verify trusted alien checkpoints",
+				Description: `
+Trusted checkpoints let syncing nodes start from the alien snapshot at a block
+and refuse any chain without it. They are only trusted if shipped with the
+release, or signed by enough of the checkpoint keys in the chain configuration.`,
+				Subcommands: []cli.Command{
+					{
+						Name:      "create",
+						Usage:     "Create an unsigned checkpoint of a local block",
+						ArgsUsage: "[<blockNum>]",
+						Action:    utils.MigrateFlags(alienCheckpointCreate),
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.CacheFlag,
+						},
+						Description: `
+    gttc alien checkpoint create 1000000 > checkpoint.json
+
+Prints the checkpoint of the given block, or of the current head, as JSON.`,
+					},
+					{
+						Name:      "sign",
+						Usage:     "Add the signature of a checkpoint key to a checkpoint",
+						ArgsUsage: "<checkpointFile>",
+						Action:    utils.MigrateFlags(alienCheckpointSign),
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+							checkpointSignerFlag,
+						},
+						Description: `
+    gttc alien checkpoint sign --signer <address> checkpoint.json
+
+Signs the checkpoint with a key of the keystore and writes the signature back
+to the checkpoint file.`,
+					},
+					{
+						Name:      "verify",
+						Usage:     "Verify a checkpoint against the checkpoint keys and the local chain",
+						ArgsUsage: "<checkpointFile>",
+						Action:    utils.MigrateFlags(alienCheckpointVerify),
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.CacheFlag,
+						},
+						Description: `
+    gttc alien checkpoint verify checkpoint.json
+
+Checks that the checkpoint is signed by enough checkpoint keys of the chain and,
+if the local chain already contains the block, that the block and snapshot
+hashes match it.`,
+					},
+				},
+			},
+		},
+	}
+)
+
+// inspectedBlock is the output of the inspect command for a single block.
+type inspectedBlock struct {
+	*alien.InspectedHeader
+	Reexecuted bool `json:"reexecuted"`
+}
+
+// alienInspect decodes and audits the header extra of the requested blocks.
+func alienInspect(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 || len(ctx.Args()) > 2 {
+		utils.Fatalf("This command requires a block hash, a block number or a range of block numbers.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	engine, ok := chain.Engine().(*alien.Alien)
+	if !ok {
+		utils.Fatalf("Chain is not running the alien consensus")
+	}
+	var first, last uint64
+	if arg := ctx.Args().First(); hashish(arg) {
+		if len(ctx.Args()) > 1 {
+			utils.Fatalf("A block range must be given as block numbers")
+		}
+		header := chain.GetHeaderByHash(common.HexToHash(arg))
+		if header == nil {
+			utils.Fatalf("Block %s not found", arg)
+		}
+		first = header.Number.Uint64()
+		last = first
+	} else {
+		num, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			utils.Fatalf("Invalid block number %s: %v", arg, err)
+		}
+		first, last = num, num
+		if len(ctx.Args()) > 1 {
+			if last, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+				utils.Fatalf("Invalid block number %s: %v", ctx.Args().Get(1), err)
+			}
+		}
+	}
+	if last < first {
+		utils.Fatalf("Invalid block range %d..%d", first, last)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	flagged := 0
+	for number := first; number <= last; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			utils.Fatalf("Block #%d not found", number)
+		}
+		inspected, err := engine.InspectHeader(chain, block.Header())
+		if err != nil {
+			utils.Fatalf("Failed to inspect block #%d: %v", number, err)
+		}
+		result := &inspectedBlock{InspectedHeader: inspected}
+		if !ctx.Bool(inspectNoReexecFlag.Name) && number > 0 {
+			diffs, err := reexecAlienBlock(chain, engine, block)
+			if err == nil {
+				result.Reexecuted = true
+				inspected.Mismatches = append(inspected.Mismatches, diffs...)
+			}
+		}
+		if len(inspected.Mismatches) > 0 {
+			flagged++
+		} else if ctx.Bool(inspectMismatchesFlag.Name) {
+			continue
+		}
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Inspected %d blocks, %d flagged\n", last-first+1, flagged)
+	return nil
+}
+
+// reexecAlienBlock re-executes a block on top of its parent state, and lists
+// the differences between its extra and state root and the ones produced by
+// Finalize. It fails if the parent state is not available.
+func reexecAlienBlock(chain *core.BlockChain, engine *alien.Alien, block *types.Block) ([]string, error) {
+	parent := chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent of block #%d not found", block.NumberU64())
+	}
+	statedb, err := chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		header   = types.CopyHeader(block.Header())
+		gp       = new(core.GasPool).AddGas(block.GasLimit())
+		usedGas  = new(uint64)
+		receipts types.Receipts
+	)
+	for i, tx := range block.Transactions() {
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		receipt, _, err := core.ApplyTransaction(chain.Config(), chain, nil, gp, statedb, header, tx, usedGas, vm.Config{})
+		if err != nil {
+			return []string{fmt.Sprintf("transaction %s failed: %v", tx.Hash().Hex(), err)}, nil
+		}
+		receipts = append(receipts, receipt)
+	}
+	finalized, err := engine.Finalize(chain, header, statedb, block.Transactions(), nil, receipts)
+	if err != nil {
+		return []string{fmt.Sprintf("finalize failed: %v", err)}, nil
+	}
+	diffs, err := engine.CompareHeaderExtra(block.Header(), finalized.Header())
+	if err != nil {
+		return nil, err
+	}
+	if finalized.Root() != block.Root() {
+		diffs = append(diffs, "state root differs from finalized root")
+	}
+	return diffs, nil
+}
+
+// alienVerifySnapshot rebuilds the snapshot of a block by replay and prints the
+// differences with the stored one.
+func alienVerifySnapshot(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most a block number.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	engine, ok := chain.Engine().(*alien.Alien)
+	if !ok {
+		utils.Fatalf("Chain is not running the alien consensus")
+	}
+	header := headerArg(ctx, chain)
+	result, err := engine.VerifySnapshot(chain, header)
+	if err != nil {
+		utils.Fatalf("Failed to verify snapshot: %v", err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+	if !result.Consistent {
+		utils.Fatalf("Snapshot #%d differs from its replay", result.Number)
+	}
+	return nil
+}
+
+// alienCheckpointCreate prints the unsigned checkpoint of a local block.
+func alienCheckpointCreate(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most a block number.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	engine, ok := chain.Engine().(*alien.Alien)
+	if !ok {
+		utils.Fatalf("Chain is not running the alien consensus")
+	}
+	header := headerArg(ctx, chain)
+	checkpoint, err := engine.NewCheckpoint(chain, header)
+	if err != nil {
+		utils.Fatalf("Failed to create checkpoint: %v", err)
+	}
+	return writeCheckpoint(os.Stdout, checkpoint)
+}
+
+// alienCheckpointSign signs a checkpoint file with a key of the keystore.
+func alienCheckpointSign(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires a checkpoint file.")
+	}
+	if !ctx.IsSet(checkpointSignerFlag.Name) {
+		utils.Fatalf("The checkpoint key must be given with --%s", checkpointSignerFlag.Name)
+	}
+	file := ctx.Args().First()
+	checkpoint := readCheckpoint(file)
+
+	stack, _ := makeConfigNode(ctx)
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	account, password := unlockAccount(ctx, ks, ctx.String(checkpointSignerFlag.Name), 0, utils.MakePasswordList(ctx))
+
+	sig, err := ks.SignHashWithPassphrase(account, password, checkpoint.SigHash().Bytes())
+	if err != nil {
+		utils.Fatalf("Failed to sign checkpoint: %v", err)
+	}
+	for _, have := range checkpoint.Signatures {
+		if bytes.Equal(have, sig) {
+			utils.Fatalf("Checkpoint already signed by %s", account.Address.Hex())
+		}
+	}
+	checkpoint.Signatures = append(checkpoint.Signatures, hexutil.Bytes(sig))
+
+	out, err := os.Create(file)
+	if err != nil {
+		utils.Fatalf("Failed to write checkpoint: %v", err)
+	}
+	defer out.Close()
+
+	if err := writeCheckpoint(out, checkpoint); err != nil {
+		return err
+	}
+	fmt.Printf("Signed checkpoint #%d with %s, %d signatures\n", checkpoint.Number, account.Address.Hex(), len(checkpoint.Signatures))
+	return nil
+}
+
+// alienCheckpointVerify checks a checkpoint file against the checkpoint keys of
+// the chain and against the local chain.
+func alienCheckpointVerify(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires a checkpoint file.")
+	}
+	checkpoint := readCheckpoint(ctx.Args().First())
+
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	engine, ok := chain.Engine().(*alien.Alien)
+	if !ok {
+		utils.Fatalf("Chain is not running the alien consensus")
+	}
+	signers, err := alien.VerifyCheckpoint(chain.Config().Alien, checkpoint)
+	for _, signer := range signers {
+		fmt.Printf("Signed by checkpoint key %s\n", signer.Hex())
+	}
+	if err != nil {
+		utils.Fatalf("Checkpoint #%d is not trusted: %v", checkpoint.Number, err)
+	}
+	if header := chain.GetHeaderByNumber(checkpoint.Number); header != nil {
+		local, err := engine.NewCheckpoint(chain, header)
+		if err != nil {
+			utils.Fatalf("Failed to create local checkpoint: %v", err)
+		}
+		if local.Hash != checkpoint.Hash {
+			utils.Fatalf("Checkpoint conflicts with local block #%d: have %s, want %s", checkpoint.Number, local.Hash.Hex(), checkpoint.Hash.Hex())
+		}
+		if local.SnapshotHash != checkpoint.SnapshotHash {
+			utils.Fatalf("Checkpoint conflicts with local snapshot #%d: have %s, want %s", checkpoint.Number, local.SnapshotHash.Hex(), checkpoint.SnapshotHash.Hex())
+		}
+		fmt.Printf("Checkpoint #%d matches the local chain\n", checkpoint.Number)
+	} else {
+		fmt.Printf("Checkpoint #%d is beyond the local chain, only its signatures were verified\n", checkpoint.Number)
+	}
+	return nil
+}
+
+// headerArg returns the header of the block number given as first argument, or
+// the current head if there is none.
+func headerArg(ctx *cli.Context, chain *core.BlockChain) *types.Header {
+	arg := ctx.Args().First()
+	if arg == "" {
+		return chain.CurrentHeader()
+	}
+	num, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid block number %s: %v", arg, err)
+	}
+	header := chain.GetHeaderByNumber(num)
+	if header == nil {
+		utils.Fatalf("Block #%d not found", num)
+	}
+	return header
+}
+
+// readCheckpoint loads a checkpoint from a JSON file.
+func readCheckpoint(file string) *params.AlienCheckpoint {
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		utils.Fatalf("Failed to read checkpoint: %v", err)
+	}
+	checkpoint := new(params.AlienCheckpoint)
+	if err := json.Unmarshal(blob, checkpoint); err != nil {
+		utils.Fatalf("Invalid checkpoint file %s: %v", file, err)
+	}
+	return checkpoint
+}
+
+// writeCheckpoint encodes a checkpoint as indented JSON.
+func writeCheckpoint(out *os.File, checkpoint *params.AlienCheckpoint) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(checkpoint)
+}
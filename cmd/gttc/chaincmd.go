@@ -29,6 +29,7 @@ import (
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/console"
 	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/rawdb"
 	"github.com/TTCECO/gttc/core/state"
 	"github.com/TTCECO/gttc/core/types"
 	"github.com/TTCECO/gttc/eth/downloader"
@@ -65,6 +66,8 @@ It expects the genesis file as argument.`,
 		ArgsUsage: "<filename> (<filename 2> ... <filename N>) ",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.AncientThresholdFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 			utils.GCModeFlag,
@@ -86,6 +89,7 @@ processing will proceed even if an individual RLP-file import failure occurs.`,
 		ArgsUsage: "<filename> [<blockNumFirst> <blockNumLast>]",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 		},
@@ -103,6 +107,7 @@ if already existing.`,
 		ArgsUsage: "<datafile>",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 		},
@@ -117,6 +122,7 @@ if already existing.`,
 		ArgsUsage: "<dumpfile>",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 		},
@@ -131,6 +137,7 @@ The export-preimages command export hash preimages to an RLP encoded stream`,
 		ArgsUsage: "<sourceChaindataDir>",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.SyncModeFlag,
 			utils.FakePoWFlag,
@@ -148,11 +155,34 @@ The first argument must be the directory containing the blockchain to download f
 		ArgsUsage: " ",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.LightModeFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 Remove blockchain and state databases`,
+	}
+	pruneStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(pruneState),
+		Name:      "prune-state",
+		Usage:     "Delete all state not reachable from a recent block",
+		ArgsUsage: "[<blockHash> | <blockNum>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The prune-state command deletes all the state trie nodes and contract codes from
+the chain database of a stopped node that are not reachable from the state of the
+given block (the current head block by default). The states of the blocks after
+it up to the head, as far as available, and the genesis state are retained too.
+
+The pruning can be interrupted at any point. Only the deletion phase resumes:
+once deletion started, running the command again finishes the interrupted
+pruning, regardless of the block given. A pruning interrupted while still
+marking the reachable state restarts the marking from scratch.`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -161,6 +191,7 @@ Remove blockchain and state databases`,
 		ArgsUsage: "[<blockHash> | <blockNum>]...",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 		},
@@ -246,7 +277,7 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*ethdb.LDBDatabase)
+	db := rawdb.KeyValueStore(chainDb).(*ethdb.LDBDatabase)
 
 	stats, err := db.LDB().GetProperty("leveldb.stats")
 	if err != nil {
@@ -337,7 +368,7 @@ func importPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := rawdb.KeyValueStore(utils.MakeChainDatabase(ctx, stack)).(*ethdb.LDBDatabase)
 
 	start := time.Now()
 	if err := utils.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -353,7 +384,7 @@ func exportPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := rawdb.KeyValueStore(utils.MakeChainDatabase(ctx, stack)).(*ethdb.LDBDatabase)
 
 	start := time.Now()
 	if err := utils.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -403,7 +434,7 @@ func copyDb(ctx *cli.Context) error {
 	// Compact the entire database to remove any sync overhead
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = chainDb.(*ethdb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
+	if err = rawdb.KeyValueStore(chainDb).(*ethdb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
 	}
 	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
@@ -415,29 +446,129 @@ func removeDB(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 
 	for _, name := range []string{"chaindata", "lightchaindata"} {
-		// Ensure the database exists in the first place
-		logger := log.New("database", name)
+		confirmAndRemoveDB(stack.ResolvePath(name), name)
+	}
+	// A custom ancient store lives outside of the chain database, remove it too
+	if ancient := ctx.GlobalString(utils.AncientFlag.Name); ancient != "" {
+		confirmAndRemoveDB(stack.ResolvePath(ancient), "ancient")
+	}
+	return nil
+}
+
+// confirmAndRemoveDB prompts the user for a last confirmation and removes the
+// database folder if accepted.
+func confirmAndRemoveDB(dbdir string, name string) {
+	// Ensure the database exists in the first place
+	logger := log.New("database", name)
+
+	if !common.FileExist(dbdir) {
+		logger.Info("Database doesn't exist, skipping", "path", dbdir)
+		return
+	}
+	// Confirm removal and execute
+	fmt.Println(dbdir)
+	confirm, err := console.Stdin.PromptConfirm("Remove this database?")
+	switch {
+	case err != nil:
+		utils.Fatalf("%v", err)
+	case !confirm:
+		logger.Warn("Database deletion aborted")
+	default:
+		start := time.Now()
+		os.RemoveAll(dbdir)
+		logger.Info("Database successfully deleted", "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+}
 
-		dbdir := stack.ResolvePath(name)
-		if !common.FileExist(dbdir) {
-			logger.Info("Database doesn't exist, skipping", "path", dbdir)
+func pruneState(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command requires at most one argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeOfflineChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	db, ok := rawdb.KeyValueStore(chainDb).(*ethdb.LDBDatabase)
+	if !ok {
+		utils.Fatalf("State pruning requires a persistent database")
+	}
+	pruner, err := state.NewPruner(db, stack.ResolvePath("prunestate"))
+	if err != nil {
+		utils.Fatalf("Failed to open pruning markers: %v", err)
+	}
+	roots := pruner.Pending()
+	if roots != nil {
+		if len(ctx.Args()) > 0 {
+			log.Warn("Finishing interrupted pruning, ignoring block", "block", ctx.Args().First())
+		}
+	} else {
+		roots = pruneStateRoots(ctx, chainDb)
+	}
+	start := time.Now()
+	if err := pruner.Prune(roots); err != nil {
+		pruner.Close()
+		utils.Fatalf("State pruning failed: %v", err)
+	}
+	fmt.Printf("State pruning done in %v\n", time.Since(start))
+	return nil
+}
+
+// pruneStateRoots resolves the state roots to retain while pruning: the state of
+// the requested block, the available states of all the blocks after it up to the
+// head, and the genesis state.
+func pruneStateRoots(ctx *cli.Context, db ethdb.Database) []common.Hash {
+	headHash := rawdb.ReadHeadBlockHash(db)
+	headNumber := rawdb.ReadHeaderNumber(db, headHash)
+	if headNumber == nil {
+		utils.Fatalf("Head block unavailable")
+	}
+	number := *headNumber
+	if arg := ctx.Args().First(); arg != "" {
+		if hashish(arg) {
+			hash := common.HexToHash(arg)
+			n := rawdb.ReadHeaderNumber(db, hash)
+			if n == nil || rawdb.ReadCanonicalHash(db, *n) != hash {
+				utils.Fatalf("Block %x not in the canonical chain", hash)
+			}
+			number = *n
+		} else {
+			n, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				utils.Fatalf("Invalid block number %q: %v", arg, err)
+			}
+			number = n
+		}
+		if number > *headNumber {
+			utils.Fatalf("Block #%d is above the head block #%d", number, *headNumber)
+		}
+	}
+	var (
+		sdb   = state.NewDatabase(db)
+		roots []common.Hash
+		seen  = make(map[common.Hash]bool)
+	)
+	for n := number; n <= *headNumber; n++ {
+		header := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, n), n)
+		if header == nil {
+			utils.Fatalf("Block #%d unavailable", n)
+		}
+		if _, err := sdb.OpenTrie(header.Root); err != nil {
+			if n == number {
+				utils.Fatalf("State of block #%d unavailable: %v", n, err)
+			}
 			continue
 		}
-		// Confirm removal and execute
-		fmt.Println(dbdir)
-		confirm, err := console.Stdin.PromptConfirm("Remove this database?")
-		switch {
-		case err != nil:
-			utils.Fatalf("%v", err)
-		case !confirm:
-			logger.Warn("Database deletion aborted")
-		default:
-			start := time.Now()
-			os.RemoveAll(dbdir)
-			logger.Info("Database successfully deleted", "elapsed", common.PrettyDuration(time.Since(start)))
+		if !seen[header.Root] {
+			roots, seen[header.Root] = append(roots, header.Root), true
 		}
 	}
-	return nil
+	if genesis := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, 0), 0); genesis != nil && !seen[genesis.Root] {
+		if _, err := sdb.OpenTrie(genesis.Root); err == nil {
+			roots = append(roots, genesis.Root)
+		}
+	}
+	log.Info("Retaining state", "block", number, "head", *headNumber, "roots", len(roots))
+	return roots
 }
 
 func dump(ctx *cli.Context) error {
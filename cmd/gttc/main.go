@@ -56,10 +56,13 @@ var (
 		utils.IdentityFlag,
 		utils.UnlockedAccountFlag,
 		utils.PasswordFileFlag,
+		utils.VoteRenewFlag,
 		utils.BootnodesFlag,
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
 		utils.DataDirFlag,
+		utils.AncientFlag,
+		utils.AncientThresholdFlag,
 		utils.KeyStoreDirFlag,
 		utils.NoUSBFlag,
 		utils.DashboardEnabledFlag,
@@ -81,10 +84,12 @@ var (
 		utils.TxPoolGlobalSlotsFlag,
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
+		utils.TxPoolConsensusSlotsFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.FastSyncFlag,
 		utils.LightModeFlag,
 		utils.SyncModeFlag,
+		utils.AlienCheckpointFlag,
 		utils.GCModeFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
@@ -122,6 +127,10 @@ var (
 		utils.GpoBlocksFlag,
 		utils.GpoPercentileFlag,
 		utils.ExtraDataFlag,
+		utils.ExternalSignerFlag,
+		utils.MaxClockDriftFlag,
+		utils.DriftRefuseFlag,
+		utils.DriftNTPFlag,
 		configFileFlag,
 	}
 
@@ -171,7 +180,10 @@ func init() {
 		exportPreimagesCommand,
 		copydbCommand,
 		removedbCommand,
+		pruneStateCommand,
 		dumpCommand,
+		// See aliencmd.go:
+		alienCommand,
 		// See monitorcmd.go:
 		monitorCommand,
 		// See accountcmd.go:
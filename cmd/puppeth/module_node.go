@@ -42,7 +42,7 @@ ADD genesis.json /genesis.json
 RUN \
   echo 'geth --cache 512 init /genesis.json' > geth.sh && \{{if .Unlock}}
 	echo 'mkdir -p /root/.ttceco/keystore/ && cp /signer.json /root/.ttceco/keystore/' >> geth.sh && \{{end}}
-	echo $'geth --networkid {{.NetworkID}} --cache 512 --port {{.Port}} --maxpeers {{.Peers}} {{.LightFlag}} --ethstats \'{{.Ethstats}}\' {{if .Bootnodes}}--bootnodes {{.Bootnodes}}{{end}} {{if .Etherbase}}--etherbase {{.Etherbase}} --mine --minerthreads 1{{end}} {{if .Unlock}}--unlock 0 --password /signer.pass --mine{{end}} --targetgaslimit {{.GasTarget}} --gasprice {{.GasPrice}}' >> geth.sh
+	echo $'geth --networkid {{.NetworkID}} --cache 512 --port {{.Port}} --maxpeers {{.Peers}} {{.LightFlag}} --ethstats \'{{.Ethstats}}\' {{if .Bootnodes}}--bootnodes {{.Bootnodes}}{{end}} {{if .Etherbase}}--etherbase {{.Etherbase}} --mine --minerthreads 1{{end}} {{if .Unlock}}--unlock 0 --password /signer.pass --mine{{end}} {{if .PBFT}}--pbft{{end}} {{if .MainRPCAddr}}--sca --sca.mainrpcaddr {{.MainRPCAddr}} --sca.mainrpcport {{.MainRPCPort}} --sca.period {{.Period}}{{end}} --targetgaslimit {{.GasTarget}} --gasprice {{.GasPrice}}' >> geth.sh
 
 ENTRYPOINT ["/bin/sh", "geth.sh"]
 `
@@ -68,7 +68,9 @@ services:
       - STATS_NAME={{.Ethstats}}
       - MINER_NAME={{.Etherbase}}
       - GAS_TARGET={{.GasTarget}}
-      - GAS_PRICE={{.GasPrice}}
+      - GAS_PRICE={{.GasPrice}}{{if .MainRPCAddr}}
+      - MAIN_RPC_ADDR={{.MainRPCAddr}}
+      - MAIN_RPC_PORT={{.MainRPCPort}}{{end}}
     logging:
       driver: "json-file"
       options:
@@ -96,33 +98,39 @@ func deployNode(client *sshClient, network string, bootnodes []string, config *n
 	}
 	dockerfile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(nodeDockerfile)).Execute(dockerfile, map[string]interface{}{
-		"NetworkID": config.network,
-		"Port":      config.port,
-		"Peers":     config.peersTotal,
-		"LightFlag": lightFlag,
-		"Bootnodes": strings.Join(bootnodes, ","),
-		"Ethstats":  config.ethstats,
-		"Etherbase": config.etherbase,
-		"GasTarget": uint64(1000000 * config.gasTarget),
-		"GasPrice":  uint64(1000000000 * config.gasPrice),
-		"Unlock":    config.keyJSON != "",
+		"NetworkID":   config.network,
+		"Port":        config.port,
+		"Peers":       config.peersTotal,
+		"LightFlag":   lightFlag,
+		"Bootnodes":   strings.Join(bootnodes, ","),
+		"Ethstats":    config.ethstats,
+		"Etherbase":   config.etherbase,
+		"GasTarget":   uint64(1000000 * config.gasTarget),
+		"GasPrice":    uint64(1000000000 * config.gasPrice),
+		"Unlock":      config.keyJSON != "",
+		"PBFT":        config.pbft,
+		"MainRPCAddr": config.mainRPCAddr,
+		"MainRPCPort": config.mainRPCPort,
+		"Period":      config.period,
 	})
 	files[filepath.Join(workdir, "Dockerfile")] = dockerfile.Bytes()
 
 	composefile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(nodeComposefile)).Execute(composefile, map[string]interface{}{
-		"Type":       kind,
-		"Datadir":    config.datadir,
-		"Ethashdir":  config.ethashdir,
-		"Network":    network,
-		"Port":       config.port,
-		"TotalPeers": config.peersTotal,
-		"Light":      config.peersLight > 0,
-		"LightPeers": config.peersLight,
-		"Ethstats":   config.ethstats[:strings.Index(config.ethstats, ":")],
-		"Etherbase":  config.etherbase,
-		"GasTarget":  config.gasTarget,
-		"GasPrice":   config.gasPrice,
+		"Type":        kind,
+		"Datadir":     config.datadir,
+		"Ethashdir":   config.ethashdir,
+		"Network":     network,
+		"Port":        config.port,
+		"TotalPeers":  config.peersTotal,
+		"Light":       config.peersLight > 0,
+		"LightPeers":  config.peersLight,
+		"Ethstats":    config.ethstats[:strings.Index(config.ethstats, ":")],
+		"Etherbase":   config.etherbase,
+		"GasTarget":   config.gasTarget,
+		"GasPrice":    config.gasPrice,
+		"MainRPCAddr": config.mainRPCAddr,
+		"MainRPCPort": config.mainRPCPort,
 	})
 	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
 
@@ -161,6 +169,11 @@ type nodeInfos struct {
 	keyPass    string
 	gasTarget  float64
 	gasPrice   float64
+
+	pbft        bool   // Whether the alien signer sends PBFT confirmations
+	period      uint64 // Block period of the alien side chain
+	mainRPCAddr string // Main chain RPC address of an alien side chain node
+	mainRPCPort int    // Main chain RPC port of an alien side chain node
 }
 
 // Report converts the typed struct into a plain string->string map, containing
@@ -173,6 +186,10 @@ func (info *nodeInfos) Report() map[string]string {
 		"Peer count (light nodes)": strconv.Itoa(info.peersLight),
 		"Ethstats username":        info.ethstats,
 	}
+	if info.mainRPCAddr != "" {
+		// Alien side chain node
+		report["Main chain RPC endpoint"] = fmt.Sprintf("%s:%d", info.mainRPCAddr, info.mainRPCPort)
+	}
 	if info.gasTarget > 0 {
 		// Miner or signer node
 		report["Gas limit (baseline target)"] = fmt.Sprintf("%0.3f MGas", info.gasTarget)
@@ -218,6 +235,7 @@ func checkNode(client *sshClient, network string, boot bool) (*nodeInfos, error)
 	lightPeers, _ := strconv.Atoi(infos.envvars["LIGHT_PEERS"])
 	gasTarget, _ := strconv.ParseFloat(infos.envvars["GAS_TARGET"], 64)
 	gasPrice, _ := strconv.ParseFloat(infos.envvars["GAS_PRICE"], 64)
+	mainRPCPort, _ := strconv.Atoi(infos.envvars["MAIN_RPC_PORT"])
 
 	// Container available, retrieve its node ID and its genesis json
 	var out []byte
@@ -257,6 +275,9 @@ func checkNode(client *sshClient, network string, boot bool) (*nodeInfos, error)
 		keyPass:    keyPass,
 		gasTarget:  gasTarget,
 		gasPrice:   gasPrice,
+
+		mainRPCAddr: infos.envvars["MAIN_RPC_ADDR"],
+		mainRPCPort: mainRPCPort,
 	}
 	stats.enode = fmt.Sprintf("enode://%s@%s:%d", id, client.address, stats.port)
 
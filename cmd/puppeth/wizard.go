@@ -287,6 +287,29 @@ func (w *wizard) readDefaultAddress(def common.Address) common.Address {
 	}
 }
 
+// readHash reads a single line from stdin, trimming if from spaces and converts
+// it to a 32 byte hash.
+func (w *wizard) readHash() common.Hash {
+	for {
+		// Read the hash from the user
+		fmt.Printf("> 0x")
+		text, err := w.in.ReadString('\n')
+		if err != nil {
+			log.Crit("Failed to read user input", "err", err)
+		}
+		// Make sure it looks ok and return it if so
+		if text = strings.TrimSpace(text); len(text) != 2*common.HashLength {
+			log.Error("Invalid hash length, please retry")
+			continue
+		}
+		if _, ok := new(big.Int).SetString(text, 16); !ok {
+			log.Error("Invalid hash, please retry")
+			continue
+		}
+		return common.HexToHash(text)
+	}
+}
+
 // readJSON reads a raw JSON message and returns it.
 func (w *wizard) readJSON() string {
 	var blob json.RawMessage
@@ -111,6 +111,17 @@ func (w *wizard) makeGenesis() {
 			SelfVoteSigners:  []common.UnprefixedAddress{},
 		}
 		fmt.Println()
+		fmt.Println("Is this a side chain of an existing main chain? (y/n, default = no)")
+		if w.readDefaultString("n") == "y" {
+			// Side chains are identified on the main chain by the hash registered
+			// with their proposal, which must be the parent hash of their genesis
+			genesis.Config.Alien.SideChain = true
+
+			fmt.Println()
+			fmt.Println("What is the side chain hash registered on the main chain? (mandatory)")
+			genesis.ParentHash = w.readHash()
+		}
+		fmt.Println()
 		fmt.Println("How many seconds should blocks take? (default = 3)")
 		genesis.Config.Alien.Period = uint64(w.readDefaultInt(3))
 
@@ -131,20 +142,47 @@ func (w *wizard) makeGenesis() {
 		fmt.Println("How many minutes delay to create first block ? (default = 5 minutes)")
 		genesis.Config.Alien.GenesisTimestamp = uint64(time.Now().Unix()) + uint64(w.readDefaultInt(5)*60)
 
-		// We also need the initial list of signers
 		fmt.Println()
-		fmt.Println("Which accounts are vote by themselves to seal the block?(least one, those accounts will be auto pre-funded)")
-		for {
-			if address := w.readAddress(); address != nil {
+		fmt.Println("Which block should Trantor come into effect? (default = none)")
+		genesis.Config.Alien.TrantorBlock = w.readDefaultBigInt(nil)
+
+		fmt.Println()
+		fmt.Println("Which block should Terminus come into effect? (default = none)")
+		genesis.Config.Alien.TerminusBlock = w.readDefaultBigInt(nil)
+
+		fmt.Println()
+		fmt.Println("Which block should backup signers seal the slots missed by in-turn signers from? (default = none)")
+		genesis.Config.Alien.BackupSealBlock = w.readDefaultBigInt(nil)
 
-				genesis.Config.Alien.SelfVoteSigners = append(genesis.Config.Alien.SelfVoteSigners, common.UnprefixedAddress(*address))
-				genesis.Alloc[*address] = core.GenesisAccount{
-					Balance: new(big.Int).Lsh(big.NewInt(1), 256-7), // 2^256 / 128 (allow many pre-funds without balance overflows)
+		fmt.Println()
+		fmt.Println("Which block should proposals become withdrawable and declarations changeable from? (default = none)")
+		genesis.Config.Alien.ProposalLifecycleBlock = w.readDefaultBigInt(nil)
+
+		fmt.Println()
+		fmt.Println("Which block should proposals be decided by quorum, approval and veto thresholds from? (default = none)")
+		genesis.Config.Alien.ProposalQuorumBlock = w.readDefaultBigInt(nil)
+
+		fmt.Println()
+		fmt.Println("Should the signers send PBFT confirmations? (y/n, default = no)")
+		genesis.Config.Alien.PBFTEnable = w.readDefaultString("n") == "y"
+
+		// Side chain signers are elected on the main chain, only independent
+		// chains need the initial list of signers
+		if !genesis.Config.Alien.SideChain {
+			fmt.Println()
+			fmt.Println("Which accounts are vote by themselves to seal the block?(least one, those accounts will be auto pre-funded)")
+			for {
+				if address := w.readAddress(); address != nil {
+
+					genesis.Config.Alien.SelfVoteSigners = append(genesis.Config.Alien.SelfVoteSigners, common.UnprefixedAddress(*address))
+					genesis.Alloc[*address] = core.GenesisAccount{
+						Balance: new(big.Int).Lsh(big.NewInt(1), 256-7), // 2^256 / 128 (allow many pre-funds without balance overflows)
+					}
+					continue
+				}
+				if len(genesis.Config.Alien.SelfVoteSigners) > 0 {
+					break
 				}
-				continue
-			}
-			if len(genesis.Config.Alien.SelfVoteSigners) > 0 {
-				break
 			}
 		}
 
@@ -167,6 +205,15 @@ func (w *wizard) makeGenesis() {
 		}
 		break
 	}
+	// Light clients can't read the genesis state, so they need the alloc in the
+	// alien config to count the votes of the self vote signers
+	if genesis.Config.Alien != nil {
+		fmt.Println()
+		fmt.Println("Should light clients be able to sync the chain? (y/n, default = yes)")
+		if w.readDefaultString("y") == "y" {
+			genesis.Config.Alien.LightConfig = makeAlienLightConfig(genesis.Alloc)
+		}
+	}
 	// Add a batch of precompile balances to avoid them getting deleted
 	//for i := int64(0); i < 256; i++ {
 	//	genesis.Alloc[common.BigToAddress(big.NewInt(i))] = core.GenesisAccount{Balance: big.NewInt(1)}
@@ -216,6 +263,33 @@ func (w *wizard) manageGenesis() {
 		fmt.Printf("Which block should Byzantium come into effect? (default = %v)\n", w.conf.Genesis.Config.ByzantiumBlock)
 		w.conf.Genesis.Config.ByzantiumBlock = w.readDefaultBigInt(w.conf.Genesis.Config.ByzantiumBlock)
 
+		if alien := w.conf.Genesis.Config.Alien; alien != nil {
+			fmt.Println()
+			fmt.Printf("Which block should Trantor come into effect? (default = %v)\n", alien.TrantorBlock)
+			alien.TrantorBlock = w.readDefaultBigInt(alien.TrantorBlock)
+
+			fmt.Println()
+			fmt.Printf("Which block should Terminus come into effect? (default = %v)\n", alien.TerminusBlock)
+			alien.TerminusBlock = w.readDefaultBigInt(alien.TerminusBlock)
+
+			fmt.Println()
+			fmt.Printf("Which block should backup signers seal the slots missed by in-turn signers from? (default = %v)\n", alien.BackupSealBlock)
+			alien.BackupSealBlock = w.readDefaultBigInt(alien.BackupSealBlock)
+
+			fmt.Println()
+			fmt.Printf("Which block should proposals become withdrawable and declarations changeable from? (default = %v)\n", alien.ProposalLifecycleBlock)
+			alien.ProposalLifecycleBlock = w.readDefaultBigInt(alien.ProposalLifecycleBlock)
+
+			fmt.Println()
+			fmt.Printf("Which block should proposals be decided by quorum, approval and veto thresholds from? (default = %v)\n", alien.ProposalQuorumBlock)
+			alien.ProposalQuorumBlock = w.readDefaultBigInt(alien.ProposalQuorumBlock)
+
+			// Keep the light client view of the alloc in sync with the genesis
+			if alien.LightConfig != nil {
+				alien.LightConfig = makeAlienLightConfig(w.conf.Genesis.Alloc)
+			}
+		}
+
 		out, _ := json.MarshalIndent(w.conf.Genesis.Config, "", "  ")
 		fmt.Printf("Chain configuration updated:\n\n%s\n", out)
 
@@ -244,3 +318,18 @@ func (w *wizard) manageGenesis() {
 		log.Error("That's not something I can do")
 	}
 }
+
+// makeAlienLightConfig generates the alien light client config from the genesis
+// alloc, for light clients to be able to check the balance of the voters.
+func makeAlienLightConfig(alloc core.GenesisAlloc) *params.AlienLightConfig {
+	config := &params.AlienLightConfig{
+		Alloc: make(map[common.UnprefixedAddress]params.GenesisAccount),
+	}
+	for address, account := range alloc {
+		if account.Balance == nil {
+			continue
+		}
+		config.Alloc[common.UnprefixedAddress(address)] = params.GenesisAccount{Balance: account.Balance.String()}
+	}
+	return config
+}
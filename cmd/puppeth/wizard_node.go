@@ -101,6 +101,26 @@ func (w *wizard) deployNode(boot bool) {
 		fmt.Printf("What should the node be called on the stats page? (default = %s)\n", infos.ethstats)
 		infos.ethstats = w.readDefaultString(infos.ethstats) + ":" + w.conf.ethstats
 	}
+	// Alien side chain nodes need to reach the main chain to follow its signers
+	if alien := w.conf.Genesis.Config.Alien; alien != nil {
+		infos.pbft, infos.period = alien.PBFTEnable, alien.Period
+		if alien.SideChain {
+			fmt.Println()
+			if infos.mainRPCAddr == "" {
+				fmt.Printf("What is the RPC address of the main chain node?\n")
+				infos.mainRPCAddr = w.readString()
+			} else {
+				fmt.Printf("What is the RPC address of the main chain node? (default = %s)\n", infos.mainRPCAddr)
+				infos.mainRPCAddr = w.readDefaultString(infos.mainRPCAddr)
+			}
+			if infos.mainRPCPort == 0 {
+				infos.mainRPCPort = 8545
+			}
+			fmt.Println()
+			fmt.Printf("What is the RPC port of the main chain node? (default = %d)\n", infos.mainRPCPort)
+			infos.mainRPCPort = w.readDefaultInt(infos.mainRPCPort)
+		}
+	}
 	// If the node is a miner/signer, load up needed credentials
 	if !boot {
 		if w.conf.Genesis.Config.Ethash != nil {
@@ -118,7 +138,7 @@ func (w *wizard) deployNode(boot bool) {
 				fmt.Printf("What address should the miner user? (default = %s)\n", infos.etherbase)
 				infos.etherbase = w.readDefaultAddress(common.HexToAddress(infos.etherbase)).Hex()
 			}
-		} else if w.conf.Genesis.Config.Clique != nil {
+		} else if w.conf.Genesis.Config.Clique != nil || w.conf.Genesis.Config.Alien != nil {
 			// If a previous signer was already set, offer to reuse it
 			if infos.keyJSON != "" {
 				if key, err := keystore.DecryptKey([]byte(infos.keyJSON), infos.keyPass); err != nil {
@@ -131,7 +151,7 @@ func (w *wizard) deployNode(boot bool) {
 					}
 				}
 			}
-			// Clique based signers need a keyfile and unlock password, ask if unavailable
+			// Clique and alien based signers need a keyfile and unlock password, ask if unavailable
 			if infos.keyJSON == "" {
 				fmt.Println()
 				fmt.Println("Please paste the signer's key JSON:")
@@ -119,6 +119,15 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: DirectoryString{node.DefaultDataDir()},
 	}
+	AncientFlag = DirectoryFlag{
+		Name:  "datadir.ancient",
+		Usage: "Data directory for ancient chain segments (default = inside chaindata)",
+	}
+	AncientThresholdFlag = cli.Uint64Flag{
+		Name:  "ancient.threshold",
+		Usage: "Number of recent blocks kept out of the ancient store (0 = stop moving blocks)",
+		Value: eth.DefaultConfig.FreezerThreshold,
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -171,6 +180,10 @@ var (
 		Usage: `Blockchain sync mode ("fast", "full", or "light")`,
 		Value: &defaultSyncMode,
 	}
+	AlienCheckpointFlag = cli.StringFlag{
+		Name:  "alien.checkpoint",
+		Usage: "File path or URL of a trusted alien checkpoint to sync from, signed by the chain's checkpoint keys (startup fails if it cannot be loaded)",
+	}
 	GCModeFlag = cli.StringFlag{
 		Name:  "gcmode",
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
@@ -285,6 +298,11 @@ var (
 		Usage: "Maximum number of non-executable transaction slots for all accounts",
 		Value: eth.DefaultConfig.TxPool.GlobalQueue,
 	}
+	TxPoolConsensusSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.consensusslots",
+		Usage: "Maximum number of pooled transactions per account admitted as consensus transactions",
+		Value: eth.DefaultConfig.TxPool.ConsensusSlots,
+	}
 	TxPoolLifetimeFlag = cli.DurationFlag{
 		Name:  "txpool.lifetime",
 		Usage: "Maximum amount of time non-executable transaction are queued",
@@ -340,6 +358,22 @@ var (
 		Name:  "extradata",
 		Usage: "Block extra data set by the miner (default = client version)",
 	}
+	ExternalSignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "External signer (clef IPC path or HTTP url) sealing the alien blocks of the etherbase",
+	}
+	MaxClockDriftFlag = cli.DurationFlag{
+		Name:  "alien.maxdrift",
+		Usage: "Offset of the local clock beyond which the alien signer drifts (default = half a block period)",
+	}
+	DriftRefuseFlag = cli.BoolFlag{
+		Name:  "alien.driftrefuse",
+		Usage: "Refuse to mine while the local clock drifts, instead of warning",
+	}
+	DriftNTPFlag = cli.BoolFlag{
+		Name:  "alien.driftntp",
+		Usage: "Also check the local clock against NTP before mining",
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -351,6 +385,10 @@ var (
 		Usage: "Password file to use for non-interactive password input",
 		Value: "",
 	}
+	VoteRenewFlag = cli.Uint64Flag{
+		Name:  "alien.renewvotes",
+		Usage: "Re-cast the alien votes of unlocked accounts this many blocks before they expire (0 = disabled)",
+	}
 
 	VMEnableDebugFlag = cli.BoolFlag{
 		Name:  "vmdebug",
@@ -963,6 +1001,9 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolGlobalQueueFlag.Name) {
 		cfg.GlobalQueue = ctx.GlobalUint64(TxPoolGlobalQueueFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolConsensusSlotsFlag.Name) {
+		cfg.ConsensusSlots = ctx.GlobalUint64(TxPoolConsensusSlotsFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
@@ -1076,6 +1117,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		cfg.DatabaseCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
 	}
 	cfg.DatabaseHandles = makeDatabaseHandles()
+	if ctx.GlobalIsSet(AncientFlag.Name) {
+		cfg.DatabaseFreezer = ctx.GlobalString(AncientFlag.Name)
+	}
+	if ctx.GlobalIsSet(AncientThresholdFlag.Name) {
+		cfg.FreezerThreshold = ctx.GlobalUint64(AncientThresholdFlag.Name)
+	}
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1097,6 +1144,24 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(GasPriceFlag.Name) {
 		cfg.GasPrice = GlobalBig(ctx, GasPriceFlag.Name)
 	}
+	if ctx.GlobalIsSet(ExternalSignerFlag.Name) {
+		cfg.ExternalSigner = ctx.GlobalString(ExternalSignerFlag.Name)
+	}
+	if ctx.GlobalIsSet(VoteRenewFlag.Name) {
+		cfg.AlienVoteRenew = ctx.GlobalUint64(VoteRenewFlag.Name)
+	}
+	if ctx.GlobalIsSet(MaxClockDriftFlag.Name) {
+		cfg.AlienMaxDrift = ctx.GlobalDuration(MaxClockDriftFlag.Name)
+	}
+	if ctx.GlobalIsSet(DriftRefuseFlag.Name) {
+		cfg.AlienDriftRefuse = ctx.GlobalBool(DriftRefuseFlag.Name)
+	}
+	if ctx.GlobalIsSet(DriftNTPFlag.Name) {
+		cfg.AlienDriftNTP = ctx.GlobalBool(DriftNTPFlag.Name)
+	}
+	if ctx.GlobalIsSet(AlienCheckpointFlag.Name) {
+		cfg.AlienCheckpoint = ctx.GlobalString(AlienCheckpointFlag.Name)
+	}
 	if ctx.GlobalIsSet(VMEnableDebugFlag.Name) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.GlobalBool(VMEnableDebugFlag.Name)
@@ -1177,7 +1242,14 @@ func RegisterEthService(stack *node.Node, cfg *eth.Config) {
 // RegisterDashboardService adds a dashboard to the stack.
 func RegisterDashboardService(stack *node.Node, cfg *dashboard.Config, commit string) {
 	stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
-		return dashboard.New(cfg, commit)
+		// Retrieve both eth and les services
+		var ethServ *eth.Ethereum
+		ctx.Service(&ethServ)
+
+		var lesServ *les.LightEthereum
+		ctx.Service(&lesServ)
+
+		return dashboard.New(cfg, commit, ethServ, lesServ)
 	})
 }
 
@@ -1219,11 +1291,30 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
 		cache   = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
 		handles = makeDatabaseHandles()
 	)
-	name := "chaindata"
+	var (
+		chainDb ethdb.Database
+		err     error
+	)
 	if ctx.GlobalBool(LightModeFlag.Name) {
-		name = "lightchaindata"
+		chainDb, err = stack.OpenDatabase("lightchaindata", cache, handles)
+	} else {
+		chainDb, err = stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.GlobalString(AncientFlag.Name), ctx.GlobalUint64(AncientThresholdFlag.Name))
 	}
-	chainDb, err := stack.OpenDatabase(name, cache, handles)
+	if err != nil {
+		Fatalf("Could not open database: %v", err)
+	}
+	return chainDb
+}
+
+// MakeOfflineChainDatabase opens the full node chain database of a stopped node
+// for maintenance, with the ancient data readable but no blocks migrated into
+// the freezer while it is open.
+func MakeOfflineChainDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
+	var (
+		cache   = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
+		handles = makeDatabaseHandles()
+	)
+	chainDb, err := stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.GlobalString(AncientFlag.Name), 0)
 	if err != nil {
 		Fatalf("Could not open database: %v", err)
 	}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -143,15 +144,23 @@ var (
 
 // Alien is the delegated-proof-of-stake consensus engine.
 type Alien struct {
-	config     *params.AlienConfig // Consensus engine configuration parameters
-	db         ethdb.Database      // Database to store and retrieve snapshot checkpoints
-	recents    *lru.ARCCache       // Snapshots for recent block to speed up reorgs
-	signatures *lru.ARCCache       // Signatures of recent blocks to speed up mining
-	signer     common.Address      // Ethereum address of the signing key
-	signFn     SignerFn            // Signer function to authorize hashes with
-	signTxFn   SignTxFn            // Sign transaction function to sign tx
-	lock       sync.RWMutex        // Protects the signer fields
-	lcsc       uint64              // Last confirmed side chain
+	config     *params.AlienConfig     // Consensus engine configuration parameters
+	db         ethdb.Database          // Database to store and retrieve snapshot checkpoints
+	recents    *lru.ARCCache           // Snapshots for recent block to speed up reorgs
+	signatures *lru.ARCCache           // Signatures of recent blocks to speed up mining
+	signer     common.Address          // Ethereum address of the signing key
+	signFn     SignerFn                // Signer function to authorize hashes with
+	signTxFn   SignTxFn                // Sign transaction function to sign tx
+	signHdrFn  HeaderSignerFn          // Remote signer function to seal whole headers with
+	lock       sync.RWMutex            // Protects the signer fields
+	lcsc       uint64                  // Last confirmed side chain
+	syncSnap   *Snapshot               // Snapshot imported at the fast sync pivot
+	syncPivot  common.Hash             // Hash of the fast sync pivot, whose snapshot is stored on disk
+	checkpoint *params.AlienCheckpoint // Trusted checkpoint the chain must contain
+	clock      clockDrift              // Local clock offset measured against peers and NTP
+	punish     punishMetrics           // Punish metrics reported by the applied snapshots
+	poolLock   sync.Mutex              // Protects the transaction pool snapshot
+	poolSnap   *Snapshot               // Snapshot at the head the transaction pool hooks validate on
 }
 
 // SignerFn is a signer callback function to request a hash to be signed by a
@@ -245,12 +254,16 @@ func New(config *params.AlienConfig, db ethdb.Database) *Alien {
 	recents, _ := lru.NewARC(inMemorySnapshots)
 	signatures, _ := lru.NewARC(inMemorySignatures)
 
-	return &Alien{
+	alien := &Alien{
 		config:     &conf,
 		db:         db,
 		recents:    recents,
 		signatures: signatures,
 	}
+	if pivot, err := db.Get(syncPivotKey); err == nil {
+		alien.syncPivot = common.BytesToHash(pivot)
+	}
+	return alien
 }
 
 // Author implements consensus.Engine, returning the Ethereum address recovered
@@ -261,7 +274,11 @@ func (a *Alien) Author(header *types.Header) (common.Address, error) {
 
 // VerifyHeader checks whether a header conforms to the consensus rules.
 func (a *Alien) VerifyHeader(chain consensus.ChainReader, header *types.Header, seal bool) error {
-	return a.verifyHeader(chain, header, nil)
+	if err := a.verifyHeader(chain, header, nil); err != nil {
+		return err
+	}
+	a.recordBlockTime(chain, header)
+	return nil
 }
 
 // VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers. The
@@ -270,11 +287,69 @@ func (a *Alien) VerifyHeader(chain consensus.ChainReader, header *types.Header,
 func (a *Alien) VerifyHeaders(chain consensus.ChainReader, headers []*types.Header, seals []bool) (chan<- struct{}, <-chan error) {
 	abort := make(chan struct{})
 	results := make(chan error, len(headers))
+	if len(headers) == 0 {
+		return abort, results
+	}
 
+	// Spawn as many workers as allowed threads for the stateless checks
+	workers := runtime.GOMAXPROCS(0)
+	if len(headers) < workers {
+		workers = len(headers)
+	}
+	var (
+		inputs  = make(chan int)
+		done    = make(chan int, workers)
+		ordered = make(chan int, len(headers))
+		signers = make([]common.Address, len(headers))
+		errs    = make([]error, len(headers))
+	)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for index := range inputs {
+				signers[index], errs[index] = a.verifyHeaderWorker(headers, index)
+				done <- index
+			}
+		}()
+	}
+	// Hand the headers over to the snapshot checks in order as they are done
 	go func() {
-		for i, header := range headers {
-			err := a.verifyHeader(chain, header, headers[:i])
-
+		defer close(inputs)
+		defer close(ordered)
+		var (
+			in, out = 0, 0
+			checked = make([]bool, len(headers))
+			inputs  = inputs
+		)
+		for {
+			select {
+			case inputs <- in:
+				if in++; in == len(headers) {
+					// Reached end of headers. Stop sending to workers.
+					inputs = nil
+				}
+			case index := <-done:
+				for checked[index] = true; checked[out]; out++ {
+					ordered <- out
+					if out == len(headers)-1 {
+						return
+					}
+				}
+			case <-abort:
+				return
+			}
+		}
+	}()
+	// Run the snapshot dependent checks sequentially
+	go func() {
+		for index := range ordered {
+			err := errs[index]
+			if err == nil {
+				// Workers may have run far enough ahead to evict the signature
+				if headers[index].Number.Uint64() > 0 {
+					a.signatures.Add(headers[index].Hash(), signers[index])
+				}
+				err = a.verifyCascadingFields(chain, headers[index], headers[:index])
+			}
 			select {
 			case <-abort:
 				return
@@ -285,11 +360,50 @@ func (a *Alien) VerifyHeaders(chain consensus.ChainReader, headers []*types.Head
 	return abort, results
 }
 
+// verifyHeaderWorker runs the checks of a header in a batch that don't depend on
+// any snapshot, returning the recovered signer. Being safe to run concurrently,
+// these take the bulk of the signature recovery off the sequential checks.
+func (a *Alien) verifyHeaderWorker(headers []*types.Header, index int) (common.Address, error) {
+	header := headers[index]
+	if err := a.verifyStandaloneFields(header); err != nil {
+		return common.Address{}, err
+	}
+	// The genesis block is neither signed nor carries a header extra
+	if header.Number.Uint64() == 0 {
+		return common.Address{}, nil
+	}
+	if index > 0 {
+		parent := headers[index-1]
+		if parent.Hash() == header.ParentHash && parent.Time.Uint64() > header.Time.Uint64() {
+			return common.Address{}, ErrInvalidTimestamp
+		}
+	}
+	signer, err := ecrecover(header, a.signatures)
+	if err != nil {
+		return common.Address{}, err
+	}
+	headerExtra := HeaderExtra{}
+	if err := decodeHeaderExtra(a.config, header.Number, header.Extra[extraVanity:len(header.Extra)-extraSeal], &headerExtra); err != nil {
+		return common.Address{}, err
+	}
+	return signer, nil
+}
+
 // verifyHeader checks whether a header conforms to the consensus rules.The
 // caller may optionally pass in a batch of parents (ascending order) to avoid
 // looking those up from the database. This is useful for concurrently verifying
 // a batch of new headers.
 func (a *Alien) verifyHeader(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
+	if err := a.verifyStandaloneFields(header); err != nil {
+		return err
+	}
+	// All basic checks passed, verify cascading fields
+	return a.verifyCascadingFields(chain, header, parents)
+}
+
+// verifyStandaloneFields verifies the header fields that can be checked without
+// looking at any other header.
+func (a *Alien) verifyStandaloneFields(header *types.Header) error {
 	if header.Number == nil {
 		return errUnknownBlock
 	}
@@ -315,9 +429,7 @@ func (a *Alien) verifyHeader(chain consensus.ChainReader, header *types.Header,
 	if header.UncleHash != uncleHash {
 		return errInvalidUncleHash
 	}
-
-	// All basic checks passed, verify cascading fields
-	return a.verifyCascadingFields(chain, header, parents)
+	return nil
 }
 
 // verifyCascadingFields verifies all the header fields that are not standalone,
@@ -330,6 +442,17 @@ func (a *Alien) verifyCascadingFields(chain consensus.ChainReader, header *types
 	if number == 0 {
 		return nil
 	}
+	// Refuse any chain conflicting with the trusted checkpoint
+	if err := a.verifyCheckpoint(header); err != nil {
+		return err
+	}
+	// Headers up to a confirmed fast sync pivot are only linked to it by hash
+	if snap := a.pivotSnapshot(chain); snap != nil && number <= snap.Number {
+		if number == snap.Number && header.Hash() != snap.Hash {
+			return errSyncSnapshotMismatch
+		}
+		return nil
+	}
 	// Ensure that the block's timestamp isn't too close to it's parent
 	var parent *types.Header
 	if len(parents) > 0 {
@@ -368,13 +491,20 @@ func (a *Alien) snapshot(chain consensus.ChainReader, number uint64, hash common
 	for snap == nil {
 		// If an in-memory snapshot was found, use that
 		if s, ok := a.recents.Get(hash); ok {
+			if len(headers) == 0 {
+				snapshotHitMeter.Mark(1)
+			}
 			snap = s.(*Snapshot)
 			break
 		}
-		// If an on-disk checkpoint snapshot can be found, use that
-		if number%checkpointInterval == 0 {
+		// If an on-disk snapshot can be found (checkpoint or fast sync pivot), use that
+		if a.storedSnapshot(number, hash) {
 			if s, err := loadSnapshot(a.config, a.signatures, a.db, hash); err == nil {
 				log.Trace("Loaded voting snapshot from disk", "number", number, "hash", hash)
+				s.punish = &a.punish
+				if len(headers) == 0 {
+					snapshotDiskMeter.Mark(1)
+				}
 				snap = s
 				break
 			}
@@ -387,6 +517,7 @@ func (a *Alien) snapshot(chain consensus.ChainReader, number uint64, hash common
 			}
 			a.config.Period = chain.Config().Alien.Period
 			snap = newSnapshot(a.config, a.signatures, genesis.Hash(), genesisVotes, lcrs)
+			snap.punish = &a.punish
 			if err := snap.store(a.db); err != nil {
 				return nil, err
 			}
@@ -417,11 +548,18 @@ func (a *Alien) snapshot(chain consensus.ChainReader, number uint64, hash common
 		headers[i], headers[len(headers)-1-i] = headers[len(headers)-1-i], headers[i]
 	}
 
+	if len(headers) > 0 {
+		snapshotMissMeter.Mark(1)
+	}
+	start := time.Now()
 	snap, err := snap.apply(headers)
 	if err != nil {
 		return nil, err
 	}
-
+	if len(headers) > 0 {
+		snapshotApplyTimer.UpdateSince(start)
+		confirmLagGauge.Update(int64(snap.Number - snap.ConfirmedNumber))
+	}
 	a.recents.Add(snap.Hash, snap)
 
 	// If we've generated a new checkpoint snapshot, save to disk
@@ -453,12 +591,18 @@ func (a *Alien) VerifySeal(chain consensus.ChainReader, header *types.Header) er
 // consensus protocol requirements. The method accepts an optional list of parent
 // headers that aren't yet part of the local blockchain to generate the snapshots
 // from.
-func (a *Alien) verifySeal(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
+func (a *Alien) verifySeal(chain consensus.ChainReader, header *types.Header, parents []*types.Header) (err error) {
 	// Verifying the genesis block is not supported
 	number := header.Number.Uint64()
 	if number == 0 {
 		return errUnknownBlock
 	}
+	defer func(start time.Time) {
+		verifySealTimer.UpdateSince(start)
+		if err != nil {
+			invalidSealedMeter.Mark(1)
+		}
+	}(time.Now())
 	// Retrieve the snapshot needed to verify this header and cache it
 	snap, err := a.snapshot(chain, number-1, header.ParentHash, parents, nil, defaultLoopCntRecalculateSigners)
 	if err != nil {
@@ -479,14 +623,17 @@ func (a *Alien) verifySeal(chain consensus.ChainReader, header *types.Header, pa
 	}
 
 	if !chain.Config().Alien.SideChain {
+		var parent *types.Header
+		if len(parents) > 0 {
+			parent = parents[len(parents)-1]
+		} else {
+			parent = chain.GetHeader(header.ParentHash, number-1)
+		}
+		if parent == nil {
+			return consensus.ErrUnknownAncestor
+		}
 
 		if number > a.config.MaxSignerCount {
-			var parent *types.Header
-			if len(parents) > 0 {
-				parent = parents[len(parents)-1]
-			} else {
-				parent = chain.GetHeader(header.ParentHash, number-1)
-			}
 			parentHeaderExtra := HeaderExtra{}
 			err = decodeHeaderExtra(a.config, parent.Number, parent.Extra[extraVanity:len(parent.Extra)-extraSeal], &parentHeaderExtra)
 			if err != nil {
@@ -538,7 +685,7 @@ func (a *Alien) verifySeal(chain consensus.ChainReader, header *types.Header, pa
 						return err
 					}
 				}
-				parentSignerMissing = getSignerMissingTrantor(parent.Coinbase, header.Coinbase, &parentHeaderExtra, &grandParentHeaderExtra)
+				parentSignerMissing = a.backupSignerMissing(parent, header, &parentHeaderExtra, &grandParentHeaderExtra)
 			} else {
 				newLoop := false
 				if number%a.config.MaxSignerCount == 0 {
@@ -557,9 +704,18 @@ func (a *Alien) verifySeal(chain consensus.ChainReader, header *types.Header, pa
 			}
 		}
 
-		if !snap.inturn(signer, header.Time.Uint64()) {
+		if a.backupSeal(header.Number) {
+			if err := a.verifyBackupSeal(snap, signer, parent, header); err != nil {
+				return err
+			}
+		} else if !snap.inturn(signer, header.Time.Uint64()) {
 			return errUnauthorized
 		}
+
+		// verify the commitment to the parent snapshot
+		if err := a.verifySnapshotCommit(header, snap); err != nil {
+			return err
+		}
 	} else {
 		if notice, loopStartTime, period, signerLength, _, err := a.mcSnapshot(chain, signer, header.Time.Uint64()); err != nil {
 			return err
@@ -611,6 +767,18 @@ func (a *Alien) Prepare(chain consensus.ChainReader, header *types.Header) error
 	if header.Time.Int64() < time.Now().Unix() {
 		header.Time = big.NewInt(time.Now().Unix())
 	}
+	// Seal as the backup of an absent in-turn signer if allowed
+	if a.backupSeal(header.Number) {
+		snap, err := a.snapshot(chain, number-1, header.ParentHash, nil, nil, defaultLoopCntRecalculateSigners)
+		if err != nil {
+			return err
+		}
+		a.lock.RLock()
+		signer := a.signer
+		a.lock.RUnlock()
+
+		a.prepareBackupSeal(snap, signer, parent, header)
+	}
 	// If now is later than genesis timestamp, skip prepare
 	if a.config.GenesisTimestamp < uint64(time.Now().Unix()) {
 		return nil
@@ -718,12 +886,14 @@ func (a *Alien) mcConfirmBlock(chain consensus.ChainReader, header *types.Header
 		if header.Number.Uint64() > a.lcsc && header.Number.Uint64() > a.config.MaxSignerCount*scUnconfirmLoop {
 			nonce, err := a.getTransactionCountFromMainChain(chain, signer)
 			if err != nil {
+				scConfirmFailMeter.Mark(1)
 				log.Info("Confirm tx sign fail", "err", err)
 				return
 			}
 
 			lastLoopInfo, err := a.getLastLoopInfo(chain, header)
 			if err != nil {
+				scConfirmFailMeter.Mark(1)
 				log.Info("Confirm tx sign fail", "err", err)
 				return
 			}
@@ -746,8 +916,10 @@ func (a *Alien) mcConfirmBlock(chain consensus.ChainReader, header *types.Header
 			}
 			txHash, err := a.sendTransactionToMainChain(chain, signedTx)
 			if err != nil {
+				scConfirmFailMeter.Mark(1)
 				log.Info("Confirm tx send fail", "err", err)
 			} else {
+				scConfirmSentMeter.Mark(1)
 				log.Info("Confirm tx result", "txHash", txHash)
 				a.lcsc = header.Number.Uint64()
 			}
@@ -782,18 +954,7 @@ func (a *Alien) Finalize(chain consensus.ChainReader, header *types.Header, stat
 	currentHeaderExtra := HeaderExtra{}
 
 	if number == 1 {
-		alreadyVote := make(map[common.Address]struct{})
-		for _, unPrefixVoter := range a.config.SelfVoteSigners {
-			voter := common.Address(unPrefixVoter)
-			if _, ok := alreadyVote[voter]; !ok {
-				genesisVotes = append(genesisVotes, &Vote{
-					Voter:     voter,
-					Candidate: voter,
-					Stake:     state.GetBalance(voter),
-				})
-				alreadyVote[voter] = struct{}{}
-			}
-		}
+		genesisVotes = selfVotes(a.config, state.GetBalance)
 	} else {
 		// decode extra from last header.extra
 		err := decodeHeaderExtra(a.config, parent.Number, parent.Extra[extraVanity:len(parent.Extra)-extraSeal], &parentHeaderExtra)
@@ -818,7 +979,7 @@ func (a *Alien) Finalize(chain consensus.ChainReader, header *types.Header, stat
 					return nil, err
 				}
 			}
-			currentHeaderExtra.SignerMissing = getSignerMissingTrantor(parent.Coinbase, header.Coinbase, &parentHeaderExtra, &grandParentHeaderExtra)
+			currentHeaderExtra.SignerMissing = a.backupSignerMissing(parent, header, &parentHeaderExtra, &grandParentHeaderExtra)
 		} else {
 			newLoop := false
 			if number%a.config.MaxSignerCount == 0 {
@@ -862,6 +1023,13 @@ func (a *Alien) Finalize(chain consensus.ChainReader, header *types.Header, stat
 			currentHeaderExtra.SignerQueue = newSignerQueue
 		}
 
+		// commit to the parent snapshot, so that fast syncing nodes can download it
+		if a.config.IsSnapshotCommit(header.Number) && number > 1 {
+			if currentHeaderExtra.SnapshotHash, err = snap.commitHash(); err != nil {
+				return nil, err
+			}
+		}
+
 		// Accumulate any block rewards and commit the final state root
 		if err := accumulateRewards(chain.Config(), state, header, snap, refundGas); err != nil {
 			return nil, errUnauthorized
@@ -883,8 +1051,10 @@ func (a *Alien) Finalize(chain consensus.ChainReader, header *types.Header, stat
 	header.Extra = append(header.Extra, currentHeaderExtraEnc...)
 	header.Extra = append(header.Extra, make([]byte, extraSeal)...)
 
-	// Set the correct difficulty
-	header.Difficulty = new(big.Int).Set(defaultDifficulty)
+	// Set the correct difficulty, after BackupSeal it was chosen by Prepare
+	if !a.backupSeal(header.Number) {
+		header.Difficulty = new(big.Int).Set(defaultDifficulty)
+	}
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 	// No uncle block
@@ -902,28 +1072,13 @@ func (a *Alien) Authorize(signer common.Address, signFn SignerFn, signTxFn SignT
 	a.signer = signer
 	a.signFn = signFn
 	a.signTxFn = signTxFn
+	a.signHdrFn = nil
 }
 
 // ApplyGenesis
 func (a *Alien) ApplyGenesis(chain consensus.ChainReader, genesisHash common.Hash) error {
 	if a.config.LightConfig != nil {
-		var genesisVotes []*Vote
-		alreadyVote := make(map[common.Address]struct{})
-		for _, unPrefixVoter := range a.config.SelfVoteSigners {
-			voter := common.Address(unPrefixVoter)
-			if genesisAccount, ok := a.config.LightConfig.Alloc[unPrefixVoter]; ok {
-				if _, ok := alreadyVote[voter]; !ok {
-					stake := new(big.Int)
-					stake.UnmarshalText([]byte(genesisAccount.Balance))
-					genesisVotes = append(genesisVotes, &Vote{
-						Voter:     voter,
-						Candidate: voter,
-						Stake:     stake,
-					})
-					alreadyVote[voter] = struct{}{}
-				}
-			}
-		}
+		genesisVotes := selfVotes(a.config, a.lightGenesisBalance)
 		// Assemble the voting snapshot to check which votes make sense
 		if _, err := a.snapshot(chain, 0, genesisHash, nil, genesisVotes, defaultLoopCntRecalculateSigners); err != nil {
 			return err
@@ -948,9 +1103,13 @@ func (a *Alien) Seal(chain consensus.ChainReader, block *types.Block, stop <-cha
 	if a.config.Period == 0 && len(block.Transactions()) == 0 {
 		return nil, errWaitTransactions
 	}
+	// Refuse to seal with a drifting clock if so configured
+	if a.refusing() {
+		return nil, errClockDrift
+	}
 	// Don't hold the signer fields for the entire sealing procedure
 	a.lock.RLock()
-	signer, signFn := a.signer, a.signFn
+	signer, signFn, signHdrFn := a.signer, a.signFn, a.signHdrFn
 	a.lock.RUnlock()
 
 	// Bail out if we're unauthorized to sign a block
@@ -960,7 +1119,20 @@ func (a *Alien) Seal(chain consensus.ChainReader, block *types.Block, stop <-cha
 	}
 
 	if !chain.Config().Alien.SideChain {
-		if !snap.inturn(signer, header.Time.Uint64()) {
+		if a.backupSeal(header.Number) {
+			parent := chain.GetHeader(header.ParentHash, number-1)
+			if parent == nil {
+				return nil, consensus.ErrUnknownAncestor
+			}
+			if err := a.verifyBackupSeal(snap, signer, parent, header); err != nil {
+				outOfTurnMeter.Mark(1)
+				a.logDriftSkip(snap, signer, header)
+				<-stop
+				return nil, err
+			}
+		} else if !snap.inturn(signer, header.Time.Uint64()) {
+			outOfTurnMeter.Mark(1)
+			a.logDriftSkip(snap, signer, header)
 			<-stop
 			return nil, errUnauthorized
 		}
@@ -1004,18 +1176,27 @@ func (a *Alien) Seal(chain consensus.ChainReader, block *types.Block, stop <-cha
 	}
 
 	// Sign all the things!
-	headerSigHash, err := sigHash(header)
-	if err != nil {
-		return nil, err
+	var sighash []byte
+	if signHdrFn != nil {
+		sighash, err = signHdrFn(accounts.Account{Address: signer}, header)
+	} else {
+		var headerSigHash common.Hash
+		if headerSigHash, err = sigHash(header); err != nil {
+			return nil, err
+		}
+		sighash, err = signFn(accounts.Account{Address: signer}, headerSigHash.Bytes())
 	}
-
-	sighash, err := signFn(accounts.Account{Address: signer}, headerSigHash.Bytes())
 	if err != nil {
 		return nil, err
 	}
 
 	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
 
+	sealedMeter.Mark(1)
+	if a.isBackupSeal(header) {
+		backupSealedMeter.Mark(1)
+	}
+	reportSealDelay(header.Time)
 	return block.WithSeal(header), nil
 }
 
@@ -1023,8 +1204,23 @@ func (a *Alien) Seal(chain consensus.ChainReader, block *types.Block, stop <-cha
 // that a new block should have based on the previous blocks in the chain and the
 // current signer.
 func (a *Alien) CalcDifficulty(chain consensus.ChainReader, time uint64, parent *types.Header) *big.Int {
+	if !a.backupSeal(new(big.Int).Add(parent.Number, big.NewInt(1))) {
+		return new(big.Int).Set(defaultDifficulty)
+	}
+	snap, err := a.snapshot(chain, parent.Number.Uint64(), parent.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		// Without a snapshot the slot is unknown, so don't claim the in-turn weight
+		log.Warn("Failed to retrieve snapshot for difficulty", "number", parent.Number, "hash", parent.Hash(), "err", err)
+		return new(big.Int).Set(diffBackup)
+	}
+	a.lock.RLock()
+	signer := a.signer
+	a.lock.RUnlock()
 
-	return new(big.Int).Set(defaultDifficulty)
+	if snap.inturn(signer, time) {
+		return new(big.Int).Set(diffInTurn)
+	}
+	return new(big.Int).Set(diffBackup)
 }
 
 // APIs implements consensus.Engine, returning the user facing RPC API to allow
@@ -1033,7 +1229,12 @@ func (a *Alien) APIs(chain consensus.ChainReader) []rpc.API {
 	return []rpc.API{{
 		Namespace: "alien",
 		Version:   ufoVersion,
-		Service:   &API{chain: chain, alien: a},
+		Service:   &API{chain: chain, alien: a, events: newEventSystem(a, chain)},
+		Public:    false,
+	}, {
+		Namespace: "debug",
+		Version:   ufoVersion,
+		Service:   &DebugAPI{chain: chain, alien: a},
 		Public:    false,
 	}}
 }
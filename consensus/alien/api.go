@@ -14,11 +14,11 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package alien implements the delegated-proof-of-stake consensus engine.
-
 package alien
 
 import (
+	"context"
+
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/consensus"
 	"github.com/TTCECO/gttc/core/types"
@@ -29,8 +29,9 @@ import (
 // API is a user facing RPC API to allow controlling the signer and voting
 // mechanisms of the delegated-proof-of-stake scheme.
 type API struct {
-	chain consensus.ChainReader
-	alien *Alien
+	chain  consensus.ChainReader
+	alien  *Alien
+	events *eventSystem
 }
 
 // GetSnapshot retrieves the state snapshot at a given block.
@@ -68,6 +69,51 @@ func (api *API) GetSnapshotAtNumber(number uint64) (*Snapshot, error) {
 	return api.alien.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
 }
 
+// GetClockDrift returns the measured offset of the local clock against the peer
+// blocks and NTP.
+func (api *API) GetClockDrift() *ClockDrift {
+	return api.alien.ClockDrift()
+}
+
+// GetExpiringVotes lists the votes cast by the given address, or for it as a
+// candidate, which expire within the given number of blocks after the head.
+func (api *API) GetExpiringVotes(address common.Address, within uint64) ([]*ExpiringVote, error) {
+	header := api.chain.CurrentHeader()
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.alien.ExpiringVotes(api.chain, header, address, within)
+}
+
+// GetProposal retrieves the proposal with the given hash along with its state,
+// if it is open or among the recently decided proposals.
+func (api *API) GetProposal(hash common.Hash) (*ProposalInfo, error) {
+	header := api.chain.CurrentHeader()
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.alien.Proposal(api.chain, header, hash)
+}
+
+// GetProposalResult retrieves the result of a recently decided proposal, with
+// the stake breakdown behind the decision.
+func (api *API) GetProposalResult(hash common.Hash) (*ProposalResult, error) {
+	header := api.chain.CurrentHeader()
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.alien.DecidedProposal(api.chain, header, hash)
+}
+
+// GetProposalHistory lists the recently decided proposals, oldest first.
+func (api *API) GetProposalHistory() ([]*ProposalResult, error) {
+	header := api.chain.CurrentHeader()
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.alien.ProposalHistory(api.chain, header)
+}
+
 // GetSnapshotByHeaderTime retrieves the state snapshot by timestamp of header.
 // snapshot.header.time <= targetTime < snapshot.header.time + period
 // todo: add confirm headertime in return snapshot, to minimize the request from side chain
@@ -159,3 +205,110 @@ func (api *API) GetSnapshotByHeaderTime(targetTime uint64, scHash common.Hash) (
 	}
 	return nil, errUnknownBlock
 }
+
+// NewLoop sends a notification on the first block of every new loop of the
+// signer queue.
+func (api *API) NewLoop(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicNewLoop, nil)
+}
+
+// SignerMissed sends a notification for every block recording signers which
+// missed their slot.
+func (api *API) SignerMissed(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicSignerMissed, nil)
+}
+
+// ProposalCreated sends a notification for every proposal accepted in a block.
+func (api *API) ProposalCreated(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicProposalCreated, nil)
+}
+
+// ProposalDecided sends a notification for every proposal reaching its deadline
+// or withdrawn, with its state and effect.
+func (api *API) ProposalDecided(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicProposalDecided, nil)
+}
+
+// VoteChanged sends a notification whenever a vote cast by the given address,
+// or cast for or withdrawn from it as a candidate, changes.
+func (api *API) VoteChanged(ctx context.Context, address common.Address) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicVoteChanged, func(data interface{}) bool {
+		ev := data.(*VoteChangedEvent)
+		return ev.Voter == address || ev.Candidate == address || ev.PreviousCandidate == address
+	})
+}
+
+// VoteExpired sends a notification whenever a vote cast by the given address, or
+// for it as a candidate, expires. Without an address all expiries are sent.
+func (api *API) VoteExpired(ctx context.Context, address *common.Address) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicVoteExpired, func(data interface{}) bool {
+		ev := data.(*VoteExpiredEvent)
+		return address == nil || ev.Voter == *address || ev.Candidate == *address
+	})
+}
+
+// ConfirmedNumberAdvanced sends a notification whenever the confirmed block
+// number advances.
+func (api *API) ConfirmedNumberAdvanced(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicConfirmedNumberAdvanced, nil)
+}
+
+// SideChainConfirmed sends a notification whenever the last confirmed block of
+// a side chain advances.
+func (api *API) SideChainConfirmed(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, topicSideChainConfirmed, nil)
+}
+
+// subscribe creates an RPC subscription forwarding the consensus events of the
+// given topic accepted by the filter, if any.
+func (api *API) subscribe(ctx context.Context, topic string, filter func(interface{}) bool) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	events := make(chan consensusEvent, 64)
+	sub, err := api.events.subscribe(events)
+	if err != nil {
+		return nil, err
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case ev := <-events:
+				if ev.topic == topic && (filter == nil || filter(ev.data)) {
+					notifier.Notify(rpcSub.ID, ev.data)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// DebugAPI is the collection of alien debugging APIs exposed in the debug
+// namespace.
+type DebugAPI struct {
+	chain consensus.ChainReader
+	alien *Alien
+}
+
+// VerifyAlienSnapshot rebuilds the snapshot at a given block by replaying the
+// headers, and reports every field in which the snapshots the node uses differ.
+func (api *DebugAPI) VerifyAlienSnapshot(number rpc.BlockNumber) (*SnapshotVerification, error) {
+	var header *types.Header
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return api.alien.VerifySnapshot(api.chain, header)
+}
@@ -0,0 +1,174 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+)
+
+// After BackupSeal, the next signer of the queue may seal a slot whose in-turn
+// signer produced nothing within a grace delay. Backup blocks carry a lower
+// difficulty, so the heaviest chain prefers the in-turn block of a slot.
+var (
+	diffInTurn = big.NewInt(2) // Block difficulty for in-turn signatures after BackupSeal
+	diffBackup = big.NewInt(1) // Block difficulty for backup signatures after BackupSeal
+)
+
+var (
+	// errInvalidDifficulty is returned if the difficulty of a block is neither
+	// the in-turn nor the backup one.
+	errInvalidDifficulty = errors.New("invalid difficulty")
+
+	// errInvalidBackupSeal is returned if a backup block is sealed before the
+	// grace delay of its slot, or in a slot already sealed on its chain.
+	errInvalidBackupSeal = errors.New("invalid backup seal")
+)
+
+// backupSeal returns whether backup sealing is enabled at the given block.
+func (a *Alien) backupSeal(number *big.Int) bool {
+	return !a.config.SideChain && a.config.IsBackupSeal(number)
+}
+
+// isBackupSeal returns whether the header was sealed by the backup signer of
+// its slot.
+func (a *Alien) isBackupSeal(header *types.Header) bool {
+	return a.backupSeal(header.Number) && header.Difficulty.Cmp(diffBackup) == 0
+}
+
+// backupDelay returns the number of seconds into a slot before its backup
+// signer may seal.
+func (a *Alien) backupDelay() uint64 {
+	if delay := a.config.BackupSealDelay; delay > 0 && delay < a.config.Period {
+		return delay
+	}
+	return a.config.Period / 2
+}
+
+// slotStart returns the timestamp at which the slot of the given time starts.
+func (s *Snapshot) slotStart(headerTime uint64) uint64 {
+	return headerTime - (headerTime-s.LoopStartTime)%s.config.Period
+}
+
+// slotSigner returns the in-turn signer of the slot of the given time.
+func (s *Snapshot) slotSigner(headerTime uint64) (common.Address, bool) {
+	return slotSigner(s.LoopStartTime, s.config.Period, s.signerQueue(), headerTime)
+}
+
+// backupSigner returns the signer allowed to seal the slot of the given time if
+// its in-turn signer doesn't: the next signer of the queue which differs from
+// the in-turn one.
+func (s *Snapshot) backupSigner(headerTime uint64) (common.Address, bool) {
+	signers := s.signerQueue()
+	if len(signers) == 0 {
+		return common.Address{}, false
+	}
+	index := int((headerTime - s.LoopStartTime) / s.config.Period % uint64(len(signers)))
+	for i := 1; i < len(signers); i++ {
+		if next := signers[(index+i)%len(signers)]; next != signers[index] {
+			return next, true
+		}
+	}
+	return common.Address{}, false
+}
+
+// signerQueue returns the signers of the current loop in order.
+func (s *Snapshot) signerQueue() []common.Address {
+	signers := make([]common.Address, len(s.Signers))
+	for i, signer := range s.Signers {
+		signers[i] = *signer
+	}
+	return signers
+}
+
+// slotSigner returns the signer of the given queue in turn at the given time.
+func slotSigner(loopStartTime, period uint64, signers []common.Address, headerTime uint64) (common.Address, bool) {
+	if len(signers) == 0 || headerTime < loopStartTime {
+		return common.Address{}, false
+	}
+	return signers[(headerTime-loopStartTime)/period%uint64(len(signers))], true
+}
+
+// prepareBackupSeal sets the difficulty of a header about to be sealed by the
+// given signer. If the signer is the backup of the slot instead of its in-turn
+// signer, the header is delayed by the grace delay into the slot.
+func (a *Alien) prepareBackupSeal(snap *Snapshot, signer common.Address, parent, header *types.Header) {
+	header.Difficulty = new(big.Int).Set(diffInTurn)
+
+	headerTime := header.Time.Uint64()
+	if snap.inturn(signer, headerTime) {
+		return
+	}
+	start := snap.slotStart(headerTime)
+	if backup, ok := snap.backupSigner(headerTime); !ok || backup != signer || parent.Time.Uint64() >= start {
+		return
+	}
+	if earliest := start + a.backupDelay(); headerTime < earliest {
+		header.Time = new(big.Int).SetUint64(earliest)
+	}
+	header.Difficulty = new(big.Int).Set(diffBackup)
+}
+
+// verifyBackupSeal checks the difficulty of a header sealed after BackupSeal
+// against its signer: in-turn blocks carry diffInTurn, and backup blocks carry
+// diffBackup and are sealed by the backup signer after the grace delay of a
+// slot not sealed yet.
+func (a *Alien) verifyBackupSeal(snap *Snapshot, signer common.Address, parent, header *types.Header) error {
+	headerTime := header.Time.Uint64()
+	switch {
+	case header.Difficulty.Cmp(diffInTurn) == 0:
+		if !snap.inturn(signer, headerTime) {
+			return errUnauthorized
+		}
+	case header.Difficulty.Cmp(diffBackup) == 0:
+		if !a.config.IsTrantor(header.Number) {
+			return errInvalidBackupSeal
+		}
+		if backup, ok := snap.backupSigner(headerTime); !ok || backup != signer {
+			return errUnauthorized
+		}
+		start := snap.slotStart(headerTime)
+		if headerTime < start+a.backupDelay() || parent.Time.Uint64() >= start {
+			return errInvalidBackupSeal
+		}
+	default:
+		return errInvalidDifficulty
+	}
+	return nil
+}
+
+// backupSignerMissing returns the signers which missed their slot between the
+// parent and the header after Trantor. The slot of a backup block belongs to
+// its in-turn signer, which is recorded as missing, and the next block counts
+// the missing signers from there instead of from the backup signer.
+func (a *Alien) backupSignerMissing(parent, header *types.Header, parentExtra, grandParentExtra *HeaderExtra) []common.Address {
+	lastSigner := parent.Coinbase
+	if a.isBackupSeal(parent) && len(parentExtra.SignerMissing) > 0 {
+		lastSigner = parentExtra.SignerMissing[len(parentExtra.SignerMissing)-1]
+	}
+	if !a.isBackupSeal(header) {
+		return getSignerMissingTrantor(lastSigner, header.Coinbase, parentExtra, grandParentExtra)
+	}
+	inturn, ok := slotSigner(parentExtra.LoopStartTime, a.config.Period, parentExtra.SignerQueue, header.Time.Uint64())
+	if !ok {
+		return getSignerMissingTrantor(lastSigner, header.Coinbase, parentExtra, grandParentExtra)
+	}
+	return append(getSignerMissingTrantor(lastSigner, inturn, parentExtra, grandParentExtra), inturn)
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+// Tests the checks of in-turn and backup seals after BackupSeal.
+func TestVerifyBackupSeal(t *testing.T) {
+	a, b, c := common.Address{0x0a}, common.Address{0x0b}, common.Address{0x0c}
+	config := &params.AlienConfig{
+		Period:          10,
+		TrantorBlock:    big.NewInt(0),
+		BackupSealBlock: big.NewInt(0),
+		BackupSealDelay: 4,
+	}
+	engine := &Alien{config: config}
+	// Slots start at 1000, 1010, 1020 for a, a, b, then c
+	snap := &Snapshot{config: config, LoopStartTime: 1000, Signers: []*common.Address{&a, &a, &b, &c}}
+
+	tests := []struct {
+		signer     common.Address
+		parentTime uint64
+		time       uint64
+		difficulty *big.Int
+		err        error
+	}{
+		{a, 995, 1000, diffInTurn, nil},                   // in-turn
+		{b, 995, 1000, diffInTurn, errUnauthorized},       // in-turn difficulty out of turn
+		{b, 995, 1004, diffBackup, nil},                   // backup skips the repeated signer
+		{b, 995, 1003, diffBackup, errInvalidBackupSeal},  // before the grace delay
+		{b, 1000, 1004, diffBackup, errInvalidBackupSeal}, // slot already sealed
+		{c, 995, 1004, diffBackup, errUnauthorized},       // not the backup
+		{c, 1015, 1029, diffBackup, nil},                  // backup of the slot of b
+		{a, 1025, 1034, diffBackup, nil},                  // backup wraps around the queue
+		{a, 995, 1000, big.NewInt(3), errInvalidDifficulty},
+	}
+	for i, tt := range tests {
+		parent := &types.Header{Time: new(big.Int).SetUint64(tt.parentTime)}
+		header := &types.Header{Number: big.NewInt(10), Time: new(big.Int).SetUint64(tt.time), Difficulty: tt.difficulty}
+		if err := engine.verifyBackupSeal(snap, tt.signer, parent, header); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// unknownChainReader is a chain reader that knows no headers but the genesis.
+type unknownChainReader struct {
+	testerChainReader
+}
+
+func (r *unknownChainReader) GetHeader(common.Hash, uint64) *types.Header { return nil }
+
+// Tests that the difficulty falls back to the backup difficulty if the snapshot
+// of the parent can't be retrieved.
+func TestCalcDifficultyUnknownParent(t *testing.T) {
+	config := &params.AlienConfig{
+		Period:          10,
+		TrantorBlock:    big.NewInt(0),
+		BackupSealBlock: big.NewInt(0),
+		BackupSealDelay: 4,
+		MinVoterBalance: big.NewInt(0),
+	}
+	db := ethdb.NewMemDatabase()
+	engine := New(config, db)
+
+	parent := &types.Header{Number: big.NewInt(10), ParentHash: common.Hash{0x01}, Time: big.NewInt(1000)}
+	diff := engine.CalcDifficulty(&unknownChainReader{testerChainReader{db: db}}, 1010, parent)
+	if diff == nil || diff.Cmp(diffBackup) != 0 {
+		t.Fatalf("difficulty mismatch: have %v, want %v", diff, diffBackup)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/log"
+	"github.com/TTCECO/gttc/params"
+)
+
+var (
+	// errCheckpointMismatch is returned if a header conflicts with the trusted
+	// checkpoint.
+	errCheckpointMismatch = errors.New("mismatched trusted checkpoint")
+
+	// errNoCheckpointSigners is returned if a checkpoint is verified against a
+	// chain without checkpoint keys.
+	errNoCheckpointSigners = errors.New("no checkpoint signers configured")
+
+	// errCheckpointUnsigned is returned if a checkpoint isn't signed by enough
+	// checkpoint keys.
+	errCheckpointUnsigned = errors.New("insufficient checkpoint signatures")
+
+	// errInvalidCheckpointSnapshot is returned if a snapshot doesn't match the
+	// one committed to by the trusted checkpoint.
+	errInvalidCheckpointSnapshot = errors.New("invalid checkpoint snapshot")
+)
+
+// VerifyCheckpoint checks that a checkpoint is signed by enough checkpoint keys
+// of the chain, returning the keys that signed it.
+func VerifyCheckpoint(config *params.AlienConfig, checkpoint *params.AlienCheckpoint) ([]common.Address, error) {
+	if len(config.CheckpointSigners) == 0 {
+		return nil, errNoCheckpointSigners
+	}
+	threshold := config.CheckpointThreshold
+	if threshold == 0 {
+		threshold = uint64(len(config.CheckpointSigners)/2 + 1)
+	}
+	authorized := make(map[common.Address]bool)
+	for _, signer := range config.CheckpointSigners {
+		authorized[signer] = true
+	}
+	hash := checkpoint.SigHash()
+
+	var signers []common.Address
+	for _, sig := range checkpoint.Signatures {
+		pubkey, err := crypto.Ecrecover(hash.Bytes(), sig)
+		if err != nil {
+			return nil, err
+		}
+		var signer common.Address
+		copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+		// Count every authorized key only once
+		if authorized[signer] {
+			signers = append(signers, signer)
+			delete(authorized, signer)
+		}
+	}
+	if uint64(len(signers)) < threshold {
+		return signers, errCheckpointUnsigned
+	}
+	return signers, nil
+}
+
+// NewCheckpoint assembles the unsigned checkpoint of a local block.
+func (a *Alien) NewCheckpoint(chain consensus.ChainReader, header *types.Header) (*params.AlienCheckpoint, error) {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil, errUnknownBlock
+	}
+	snap, err := a.snapshot(chain, number, header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := snap.commitHash()
+	if err != nil {
+		return nil, err
+	}
+	return &params.AlienCheckpoint{Number: number, Hash: header.Hash(), SnapshotHash: hash}, nil
+}
+
+// SetCheckpoint sets the trusted checkpoint the chain must contain and syncing
+// may start from. The caller is responsible for it being trusted, either by
+// shipping with the release or by VerifyCheckpoint.
+func (a *Alien) SetCheckpoint(checkpoint *params.AlienCheckpoint) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.checkpoint = checkpoint
+}
+
+// Checkpoint implements consensus.CheckpointSyncer, returning the number and
+// hash of the trusted checkpoint block.
+func (a *Alien) Checkpoint() (uint64, common.Hash) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if a.checkpoint == nil {
+		return 0, common.Hash{}
+	}
+	return a.checkpoint.Number, a.checkpoint.Hash
+}
+
+// ImportCheckpoint implements consensus.CheckpointSyncer, verifying a snapshot
+// against the trusted checkpoint. On success the snapshot is stored, and the
+// headers up to the checkpoint are only checked against it by hash until the
+// local header chain reaches it.
+func (a *Alien) ImportCheckpoint(chain consensus.ChainReader, blob []byte) error {
+	a.lock.RLock()
+	checkpoint := a.checkpoint
+	a.lock.RUnlock()
+
+	if checkpoint == nil {
+		return errUnknownBlock
+	}
+	snap := new(Snapshot)
+	if err := json.Unmarshal(blob, snap); err != nil {
+		return err
+	}
+	snap.config = a.config
+	snap.sigcache = a.signatures
+	snap.punish = &a.punish
+
+	if snap.Number != checkpoint.Number || snap.Hash != checkpoint.Hash {
+		return errCheckpointMismatch
+	}
+	if hash, err := snap.commitHash(); err != nil {
+		return err
+	} else if hash != checkpoint.SnapshotHash {
+		return errInvalidCheckpointSnapshot
+	}
+	if err := snap.store(a.db); err != nil {
+		return err
+	}
+	a.recents.Add(snap.Hash, snap)
+
+	a.lock.Lock()
+	a.syncSnap = snap
+	a.lock.Unlock()
+
+	log.Info("Imported checkpoint snapshot", "number", snap.Number, "hash", snap.Hash)
+	return nil
+}
+
+// verifyCheckpoint checks that a header doesn't conflict with the trusted
+// checkpoint.
+func (a *Alien) verifyCheckpoint(header *types.Header) error {
+	a.lock.RLock()
+	checkpoint := a.checkpoint
+	a.lock.RUnlock()
+
+	if checkpoint != nil && header.Number.Uint64() == checkpoint.Number && header.Hash() != checkpoint.Hash {
+		return errCheckpointMismatch
+	}
+	return nil
+}
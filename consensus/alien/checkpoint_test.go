@@ -0,0 +1,148 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+// Tests that checkpoints are only trusted if signed by enough distinct
+// checkpoint keys.
+func TestVerifyCheckpoint(t *testing.T) {
+	accounts := newTesterAccountPool()
+	checkpoint := &params.AlienCheckpoint{Number: 100, Hash: common.HexToHash("0x01"), SnapshotHash: common.HexToHash("0x02")}
+
+	sign := func(names ...string) []hexutil.Bytes {
+		var sigs []hexutil.Bytes
+		for _, name := range names {
+			accounts.address(name)
+			sig, err := crypto.Sign(checkpoint.SigHash().Bytes(), accounts.accounts[name])
+			if err != nil {
+				t.Fatalf("failed to sign checkpoint: %v", err)
+			}
+			sigs = append(sigs, sig)
+		}
+		return sigs
+	}
+	keys := []common.Address{accounts.address("A"), accounts.address("B"), accounts.address("C")}
+
+	tests := []struct {
+		signers   []common.Address
+		threshold uint64
+		sigs      []hexutil.Bytes
+		valid     int
+		err       error
+	}{
+		{signers: nil, sigs: sign("A", "B", "C"), err: errNoCheckpointSigners},
+		{signers: keys, sigs: sign("A", "B"), valid: 2},
+		{signers: keys, sigs: sign("A"), valid: 1, err: errCheckpointUnsigned},
+		{signers: keys, sigs: sign("A", "A"), valid: 1, err: errCheckpointUnsigned},
+		{signers: keys, sigs: sign("A", "D"), valid: 1, err: errCheckpointUnsigned},
+		{signers: keys, threshold: 1, sigs: sign("C"), valid: 1},
+		{signers: keys, threshold: 3, sigs: sign("A", "B"), valid: 2, err: errCheckpointUnsigned},
+	}
+	for i, tt := range tests {
+		config := &params.AlienConfig{CheckpointSigners: tt.signers, CheckpointThreshold: tt.threshold}
+		cpy := *checkpoint
+		cpy.Signatures = tt.sigs
+
+		signers, err := VerifyCheckpoint(config, &cpy)
+		if err != tt.err {
+			t.Errorf("test %d: error mismatch, have %v, want %v", i, err, tt.err)
+		}
+		if len(signers) != tt.valid {
+			t.Errorf("test %d: signer count mismatch, have %d, want %d", i, len(signers), tt.valid)
+		}
+	}
+	// Signatures don't carry over to a modified checkpoint
+	tampered := *checkpoint
+	tampered.Signatures = sign("A", "B")
+	tampered.Number++
+	if _, err := VerifyCheckpoint(&params.AlienConfig{CheckpointSigners: keys}, &tampered); err != errCheckpointUnsigned {
+		t.Errorf("tampered checkpoint: error mismatch, have %v, want %v", err, errCheckpointUnsigned)
+	}
+}
+
+// Tests that the snapshot at a checkpoint is only imported if it hashes to the
+// checkpoint, and that headers conflicting with the checkpoint are refused.
+func TestAlien_ImportCheckpoint(t *testing.T) {
+	accounts := newTesterAccountPool()
+	config := &params.AlienConfig{
+		Period:          3,
+		Epoch:           30000,
+		MaxSignerCount:  3,
+		MinVoterBalance: big.NewInt(100),
+		SelfVoteSigners: []common.UnprefixedAddress{common.UnprefixedAddress(accounts.address("A"))},
+	}
+	db := ethdb.NewMemDatabase()
+	alien := New(config, db)
+
+	snap := newSnapshot(alien.config, alien.signatures, common.Hash{}, []*Vote{
+		{Voter: accounts.address("A"), Candidate: accounts.address("A"), Stake: big.NewInt(1000)},
+	}, defaultLoopCntRecalculateSigners)
+	header := &types.Header{Number: big.NewInt(5), Time: big.NewInt(15), Extra: make([]byte, extraVanity+extraSeal)}
+	snap.Number, snap.Hash, snap.HeaderTime = 5, header.Hash(), 15
+
+	hash, err := snap.commitHash()
+	if err != nil {
+		t.Fatalf("failed to hash snapshot: %v", err)
+	}
+	blob, _ := json.Marshal(snap.copy())
+	tampered := snap.copy()
+	tampered.Tally[accounts.address("A")] = big.NewInt(2000)
+	tamperedBlob, _ := json.Marshal(tampered)
+
+	chain := &testerChainReader{db: db}
+	if err := alien.ImportCheckpoint(chain, blob); err != errUnknownBlock {
+		t.Fatalf("import without checkpoint: error mismatch, have %v, want %v", err, errUnknownBlock)
+	}
+	alien.SetCheckpoint(&params.AlienCheckpoint{Number: 5, Hash: snap.Hash, SnapshotHash: hash})
+	if number, have := alien.Checkpoint(); number != 5 || have != snap.Hash {
+		t.Errorf("checkpoint mismatch: have #%d %x, want #5 %x", number, have, snap.Hash)
+	}
+	if err := alien.ImportCheckpoint(chain, tamperedBlob); err != errInvalidCheckpointSnapshot {
+		t.Errorf("tampered snapshot: error mismatch, have %v, want %v", err, errInvalidCheckpointSnapshot)
+	}
+	if err := alien.ImportCheckpoint(chain, blob); err != nil {
+		t.Fatalf("failed to import checkpoint snapshot: %v", err)
+	}
+	if _, err := loadSnapshot(alien.config, alien.signatures, db, snap.Hash); err != nil {
+		t.Errorf("imported snapshot not stored: %v", err)
+	}
+	// Only a header conflicting with the checkpoint block is refused
+	if err := alien.verifyCheckpoint(header); err != nil {
+		t.Errorf("checkpoint header refused: %v", err)
+	}
+	conflict := types.CopyHeader(header)
+	conflict.Time = big.NewInt(16)
+	if err := alien.verifyCheckpoint(conflict); err != errCheckpointMismatch {
+		t.Errorf("conflicting header: error mismatch, have %v, want %v", err, errCheckpointMismatch)
+	}
+	conflict.Number = big.NewInt(6)
+	if err := alien.verifyCheckpoint(conflict); err != nil {
+		t.Errorf("header past the checkpoint refused: %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/log"
+)
+
+const (
+	driftSamples    = 32 // Number of recent peer blocks the clock offset is measured on
+	minDriftSamples = 5  // Number of peer blocks needed before trusting the measured offset
+)
+
+// errClockDrift is returned if the local clock drifts from the peers' or NTP's
+// by more than the allowed threshold.
+var errClockDrift = errors.New("local clock drifts")
+
+// ClockDrift is the offset of the local clock measured against the timestamps of
+// the blocks sealed by peers and, if checked, against NTP. Offsets are positive
+// when the local clock is ahead.
+type ClockDrift struct {
+	Offset     time.Duration `json:"offset"`               // Median offset against the recent peer blocks
+	Samples    int           `json:"samples"`              // Number of peer blocks measured
+	NTPOffset  time.Duration `json:"ntpOffset"`            // Offset against NTP at the last check
+	NTPChecked *time.Time    `json:"ntpChecked,omitempty"` // Time of the last NTP check, nil if never checked
+	Threshold  time.Duration `json:"threshold"`            // Offset beyond which the clock drifts
+}
+
+// clockDrift measures the offset of the local clock on the arrival times of the
+// blocks sealed by peers. As signers seal at the timestamp of their block, a
+// block arriving well before or after its timestamp reveals a skewed clock on
+// one side, and the median over many signers points at the local one.
+type clockDrift struct {
+	offsets  [driftSamples]time.Duration // Ring buffer of the recent peer block offsets
+	count    int                         // Number of offsets measured, capped at driftSamples
+	next     int                         // Index in the ring buffer of the next offset
+	ntp      time.Duration               // Offset against NTP at the last check
+	ntpTime  time.Time                   // Time of the last NTP check
+	maxDrift time.Duration               // Configured threshold, zero for the default
+	refuse   bool                        // Whether sealing is refused while the clock drifts
+	drifting bool                        // Whether the clock drifted at the last peer block
+	lock     sync.RWMutex
+}
+
+// add records the offset of a peer block arriving now.
+func (c *clockDrift) add(offset time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.offsets[c.next] = offset
+	c.next = (c.next + 1) % driftSamples
+	if c.count < driftSamples {
+		c.count++
+	}
+}
+
+// median returns the median of the recent peer block offsets, and the number of
+// offsets measured.
+func (c *clockDrift) median() (time.Duration, int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.count == 0 {
+		return 0, 0
+	}
+	offsets := make([]time.Duration, c.count)
+	copy(offsets, c.offsets[:c.count])
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets[len(offsets)/2], c.count
+}
+
+// SetMaxClockDrift sets the offset of the local clock beyond which it drifts. By
+// default it is half a block period.
+func (a *Alien) SetMaxClockDrift(threshold time.Duration) {
+	a.clock.lock.Lock()
+	defer a.clock.lock.Unlock()
+
+	a.clock.maxDrift = threshold
+}
+
+// SetClockDriftRefuse sets whether sealing is refused while the local clock
+// drifts, instead of only warning about it.
+func (a *Alien) SetClockDriftRefuse(refuse bool) {
+	a.clock.lock.Lock()
+	defer a.clock.lock.Unlock()
+
+	a.clock.refuse = refuse
+}
+
+// RecordNTPDrift records the offset of the local clock measured against NTP.
+func (a *Alien) RecordNTPDrift(offset time.Duration) {
+	a.clock.lock.Lock()
+	a.clock.ntp, a.clock.ntpTime = offset, time.Now()
+	a.clock.lock.Unlock()
+
+	clockNTPGauge.Update(int64(offset / time.Millisecond))
+}
+
+// ClockDrift returns the measured offset of the local clock.
+func (a *Alien) ClockDrift() *ClockDrift {
+	drift := new(ClockDrift)
+	drift.Offset, drift.Samples = a.clock.median()
+
+	a.clock.lock.RLock()
+	defer a.clock.lock.RUnlock()
+
+	drift.Threshold = a.clock.maxDrift
+	if drift.Threshold == 0 {
+		drift.Threshold = time.Duration(a.config.Period) * time.Second / 2
+	}
+	if !a.clock.ntpTime.IsZero() {
+		checked := a.clock.ntpTime
+		drift.NTPOffset, drift.NTPChecked = a.clock.ntp, &checked
+	}
+	return drift
+}
+
+// CheckClockDrift returns an error if the local clock drifts from the peers' or
+// NTP's by more than the threshold. The peer offset is only trusted once enough
+// peer blocks were measured.
+func (a *Alien) CheckClockDrift() error {
+	drift := a.ClockDrift()
+	if drift.Samples >= minDriftSamples && exceeds(drift.Offset, drift.Threshold) {
+		return fmt.Errorf("%v: off by %v against %d peer blocks, threshold %v", errClockDrift, drift.Offset, drift.Samples, drift.Threshold)
+	}
+	if drift.NTPChecked != nil && exceeds(drift.NTPOffset, drift.Threshold) {
+		return fmt.Errorf("%v: off by %v against NTP, threshold %v", errClockDrift, drift.NTPOffset, drift.Threshold)
+	}
+	return nil
+}
+
+// recordBlockTime measures the clock offset on a header just received from the
+// network, if it extends the local head and was sealed by another signer. Older
+// headers, like during a sync, arrive long after their timestamp.
+func (a *Alien) recordBlockTime(chain consensus.ChainReader, header *types.Header) {
+	if header.Number.Sign() == 0 {
+		return
+	}
+	if head := chain.CurrentHeader(); head == nil || head.Hash() != header.ParentHash {
+		return
+	}
+	a.lock.RLock()
+	signer := a.signer
+	a.lock.RUnlock()
+
+	if header.Coinbase == signer {
+		return
+	}
+	a.clock.add(time.Since(time.Unix(header.Time.Int64(), 0)))
+
+	offset, samples := a.clock.median()
+	if samples >= minDriftSamples {
+		clockOffsetGauge.Update(int64(offset / time.Millisecond))
+		a.evaluateClockDrift()
+	}
+}
+
+// evaluateClockDrift checks the local clock again on every measured peer block,
+// as mining may have started before enough of them arrived to trust the offset.
+// Whenever the clock starts or stops drifting it is logged loudly, and sealing
+// is refused while it drifts if so configured.
+func (a *Alien) evaluateClockDrift() {
+	err := a.CheckClockDrift()
+
+	a.clock.lock.Lock()
+	changed := a.clock.drifting != (err != nil)
+	a.clock.drifting = err != nil
+	refuse := a.clock.refuse
+	a.clock.lock.Unlock()
+
+	if !changed {
+		return
+	}
+	switch {
+	case err == nil:
+		log.Info("Local clock back in sync with the peer blocks")
+	case refuse:
+		log.Error("Refusing to seal with a drifting clock", "err", err)
+	default:
+		log.Warn(fmt.Sprintf("Sealing with a drifting clock, alien slots may be missed: %v", err))
+		log.Warn("Please enable network time synchronisation in system settings.")
+	}
+}
+
+// refusing returns whether sealing is refused due to a drifting clock.
+func (a *Alien) refusing() bool {
+	a.clock.lock.RLock()
+	defer a.clock.lock.RUnlock()
+
+	return a.clock.refuse && a.clock.drifting
+}
+
+// logDriftSkip logs the reason when the local signer skips a slot which, with
+// its clock corrected by the measured offset, would be its own.
+func (a *Alien) logDriftSkip(snap *Snapshot, signer common.Address, header *types.Header) {
+	offset, samples := a.clock.median()
+	if samples < minDriftSamples {
+		return
+	}
+	corrected := time.Unix(header.Time.Int64(), 0).Add(-offset).Unix()
+	if corrected < 0 || !snap.inturn(signer, uint64(corrected)) {
+		return
+	}
+	clockSkipMeter.Mark(1)
+	log.Warn("Skipping alien slot due to clock drift", "number", header.Number, "time", header.Time, "offset", offset, "samples", samples)
+}
+
+// exceeds returns whether the offset is beyond the threshold in any direction.
+func exceeds(offset, threshold time.Duration) bool {
+	return offset > threshold || offset < -threshold
+}
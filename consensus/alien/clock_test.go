@@ -0,0 +1,137 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+// Tests that the clock drift is only reported once enough peer blocks were
+// measured, against the default or configured threshold, and against NTP.
+func TestCheckClockDrift(t *testing.T) {
+	engine := New(&params.AlienConfig{Period: 4, MinVoterBalance: big.NewInt(0)}, ethdb.NewMemDatabase())
+	if drift := engine.ClockDrift(); drift.Threshold != 2*time.Second || drift.Samples != 0 || drift.NTPChecked != nil {
+		t.Fatalf("initial clock drift mismatch: %+v", drift)
+	}
+	for i := 0; i < minDriftSamples-1; i++ {
+		engine.clock.add(3 * time.Second)
+	}
+	if err := engine.CheckClockDrift(); err != nil {
+		t.Errorf("drift reported on too few samples: %v", err)
+	}
+	engine.clock.add(-time.Second)
+	engine.clock.add(3 * time.Second)
+	if drift := engine.ClockDrift(); drift.Offset != 3*time.Second || drift.Samples != minDriftSamples+1 {
+		t.Errorf("measured offset mismatch: have %v on %d samples, want 3s", drift.Offset, drift.Samples)
+	}
+	if err := engine.CheckClockDrift(); err == nil || !strings.Contains(err.Error(), "peer blocks") {
+		t.Errorf("peer drift not reported: %v", err)
+	}
+	engine.SetMaxClockDrift(5 * time.Second)
+	if err := engine.CheckClockDrift(); err != nil {
+		t.Errorf("drift reported within threshold: %v", err)
+	}
+	engine.RecordNTPDrift(-6 * time.Second)
+	if err := engine.CheckClockDrift(); err == nil || !strings.Contains(err.Error(), "NTP") {
+		t.Errorf("NTP drift not reported: %v", err)
+	}
+	// The ring buffer keeps the most recent offsets only
+	for i := 0; i < driftSamples; i++ {
+		engine.clock.add(0)
+	}
+	if drift := engine.ClockDrift(); drift.Offset != 0 || drift.Samples != driftSamples {
+		t.Errorf("offset mismatch after refill: have %v on %d samples", drift.Offset, drift.Samples)
+	}
+}
+
+// Tests that only the headers extending the head and sealed by other signers
+// are measured.
+func TestRecordBlockTime(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, nil)
+	sim.run(3)
+	node := sim.node("A")
+	head := node.chain.CurrentHeader()
+
+	sealed := func(parent common.Hash, coinbase common.Address) *types.Header {
+		return &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).Add(head.Number, common.Big1),
+			Coinbase:   coinbase,
+			Time:       big.NewInt(time.Now().Add(-10 * time.Second).Unix()),
+		}
+	}
+	node.engine.recordBlockTime(node.chain, sealed(head.Hash(), node.addr))
+	node.engine.recordBlockTime(node.chain, sealed(head.ParentHash, sim.node("B").addr))
+	if _, samples := node.engine.clock.median(); samples != 0 {
+		t.Fatalf("own or stale headers measured: %d samples", samples)
+	}
+	node.engine.recordBlockTime(node.chain, sealed(head.Hash(), sim.node("B").addr))
+	offset, samples := node.engine.clock.median()
+	if samples != 1 || offset < 9*time.Second || offset > 12*time.Second {
+		t.Errorf("measured offset mismatch: have %v on %d samples, want about 10s", offset, samples)
+	}
+}
+
+// Tests that a clock found drifting only after mining started, once enough peer
+// blocks arrived, stops sealing if so configured and resumes when it recovers.
+func TestSealRefusedOnLateDrift(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, nil)
+	sim.run(3)
+	node := sim.node("A")
+	node.engine.SetMaxClockDrift(time.Second)
+	node.engine.SetClockDriftRefuse(true)
+
+	head := node.chain.CurrentHeader()
+	peer := func(age time.Duration) *types.Header {
+		return &types.Header{
+			ParentHash: head.Hash(),
+			Number:     new(big.Int).Add(head.Number, common.Big1),
+			Coinbase:   sim.node("B").addr,
+			Time:       big.NewInt(time.Now().Add(-age).Unix()),
+		}
+	}
+	block := types.NewBlockWithHeader(peer(0))
+	stop := make(chan struct{})
+	close(stop)
+
+	// Mining starts without any peer block measured, the drift shows up later
+	for i := 0; i < minDriftSamples-1; i++ {
+		node.engine.recordBlockTime(node.chain, peer(10*time.Second))
+		if node.engine.refusing() {
+			t.Fatalf("sealing refused on %d samples", i+1)
+		}
+	}
+	node.engine.recordBlockTime(node.chain, peer(10*time.Second))
+	if _, err := node.engine.Seal(node.chain, block, stop); err != errClockDrift {
+		t.Fatalf("sealing with a drifting clock: have %v, want %v", err, errClockDrift)
+	}
+	// Once most peer blocks arrive on time again, sealing resumes
+	for i := 0; i <= minDriftSamples; i++ {
+		node.engine.recordBlockTime(node.chain, peer(0))
+	}
+	if node.engine.refusing() {
+		t.Errorf("sealing still refused after the clock recovered")
+	}
+}
@@ -0,0 +1,464 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/rlp"
+)
+
+// snapshotCommitVersion is the version of the snapshot encoding headers commit
+// to after SnapshotCommit. Any change to the encoding below, like committing to
+// a new snapshot field, must come with a new version activated by a fork.
+const snapshotCommitVersion = 1
+
+// snapshotCommit is the encoding of the consensus fields of a snapshot the
+// headers commit to. It lists every field explicitly and turns every map into a
+// slice sorted by key, so the commitment neither depends on the map order nor
+// changes when fields are added to the snapshot for other purposes, like the
+// proposal history.
+type snapshotCommit struct {
+	Version         uint64
+	Period          uint64
+	Number          uint64
+	ConfirmedNumber uint64
+	Hash            common.Hash
+	HistoryHash     []common.Hash
+	Signers         []common.Address
+	Votes           []commitVote
+	Tally           []commitBalance
+	Voters          []commitBalance
+	Candidates      []commitCounter
+	Punished        []commitCounter
+	Confirmations   []commitConfirmations
+	Proposals       []commitProposal
+	HeaderTime      uint64
+	LoopStartTime   uint64
+	ProposalRefund  []commitRefunds
+	SCCoinbase      []commitSCCoinbases
+	SCRecordMap     []commitSCRecord
+	SCRewardMap     []commitSCReward
+	SCNoticeMap     []commitCCNotice
+	LocalNotice     []commitCCNotice // Empty if the snapshot has no local notice
+	MinerReward     uint64
+	MinVB           *big.Int
+}
+
+type commitVote struct {
+	Voter     common.Address
+	Candidate common.Address
+	Stake     *big.Int
+}
+
+type commitBalance struct {
+	Address common.Address
+	Value   *big.Int
+}
+
+type commitCounter struct {
+	Address common.Address
+	Value   uint64
+}
+
+type commitConfirmations struct {
+	Number  uint64
+	Signers []common.Address
+}
+
+type commitDeclare struct {
+	Declarer common.Address
+	Decision bool
+	Abstain  bool
+}
+
+type commitProposal struct {
+	Hash                   common.Hash
+	ReceivedNumber         *big.Int
+	CurrentDeposit         *big.Int
+	ValidationLoopCnt      uint64
+	ProposalType           uint64
+	Proposer               common.Address
+	TargetAddress          common.Address
+	MinerRewardPerThousand uint64
+	SCHash                 common.Hash
+	SCBlockCountPerPeriod  uint64
+	SCBlockRewardPerPeriod uint64
+	Declares               []commitDeclare
+	MinVoterBalance        uint64
+	ProposalDeposit        uint64
+	SCRentFee              uint64
+	SCRentRate             uint64
+	SCRentLength           uint64
+}
+
+type commitRefunds struct {
+	Number  uint64
+	Refunds []commitBalance
+}
+
+type commitSCCoinbase struct {
+	SCHash   common.Hash
+	Coinbase common.Address
+}
+
+type commitSCCoinbases struct {
+	Signer    common.Address
+	Coinbases []commitSCCoinbase
+}
+
+type commitSCConfirmation struct {
+	Hash     common.Hash
+	Coinbase common.Address
+	Number   uint64
+	LoopInfo []string
+}
+
+type commitSCConfirmations struct {
+	Number        uint64
+	Confirmations []commitSCConfirmation
+}
+
+type commitSCRent struct {
+	Hash            common.Hash
+	RentPerPeriod   *big.Int
+	MaxRewardNumber *big.Int
+}
+
+type commitSCRecord struct {
+	SCHash              common.Hash
+	Record              []commitSCConfirmations
+	LastConfirmedNumber uint64
+	MaxHeaderNumber     uint64
+	CountPerPeriod      uint64
+	RewardPerPeriod     uint64
+	RentReward          []commitSCRent
+}
+
+type commitSCBlockReward struct {
+	Number uint64
+	Scores []commitCounter
+}
+
+type commitSCReward struct {
+	SCHash  common.Hash
+	Rewards []commitSCBlockReward
+}
+
+type commitCharging struct {
+	Hash   common.Hash
+	Target common.Address
+	Volume uint64
+}
+
+type commitNoticeConfirm struct {
+	Signer    common.Address
+	Confirmed bool
+}
+
+type commitNoticeReceived struct {
+	Hash     common.Hash
+	Confirms []commitNoticeConfirm
+	Number   uint64
+	Type     uint64
+	Success  bool
+}
+
+type commitCCNotice struct {
+	SCHash          common.Hash // Empty for the local notice
+	CurrentCharging []commitCharging
+	ConfirmReceived []commitNoticeReceived
+}
+
+// commitHash returns the hash a header extra commits to after SnapshotCommit,
+// the hash of the versioned RLP encoding of the consensus fields.
+func (s *Snapshot) commitHash() (common.Hash, error) {
+	blob, err := rlp.EncodeToBytes(s.commitment())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(blob), nil
+}
+
+// commitment assembles the encoding of the snapshot committed to by headers.
+func (s *Snapshot) commitment() *snapshotCommit {
+	commit := &snapshotCommit{
+		Version:         snapshotCommitVersion,
+		Period:          s.Period,
+		Number:          s.Number,
+		ConfirmedNumber: s.ConfirmedNumber,
+		Hash:            s.Hash,
+		HistoryHash:     s.HistoryHash,
+		HeaderTime:      s.HeaderTime,
+		LoopStartTime:   s.LoopStartTime,
+		MinerReward:     s.MinerReward,
+		MinVB:           s.MinVB,
+	}
+	for _, signer := range s.Signers {
+		commit.Signers = append(commit.Signers, *signer)
+	}
+	for _, voter := range sortedAddresses(len(s.Votes), func(add func(common.Address)) {
+		for voter := range s.Votes {
+			add(voter)
+		}
+	}) {
+		vote := s.Votes[voter]
+		commit.Votes = append(commit.Votes, commitVote{Voter: vote.Voter, Candidate: vote.Candidate, Stake: vote.Stake})
+	}
+	commit.Tally = commitBalances(s.Tally)
+	commit.Voters = commitBalances(s.Voters)
+	commit.Candidates = commitCounters(s.Candidates)
+	commit.Punished = commitCounters(s.Punished)
+
+	for _, number := range sortedNumbers(len(s.Confirmations), func(add func(uint64)) {
+		for number := range s.Confirmations {
+			add(number)
+		}
+	}) {
+		confirmations := commitConfirmations{Number: number}
+		for _, signer := range s.Confirmations[number] {
+			confirmations.Signers = append(confirmations.Signers, *signer)
+		}
+		commit.Confirmations = append(commit.Confirmations, confirmations)
+	}
+	for _, hash := range sortedHashes(len(s.Proposals), func(add func(common.Hash)) {
+		for hash := range s.Proposals {
+			add(hash)
+		}
+	}) {
+		commit.Proposals = append(commit.Proposals, commitProposalOf(s.Proposals[hash]))
+	}
+	for _, number := range sortedNumbers(len(s.ProposalRefund), func(add func(uint64)) {
+		for number := range s.ProposalRefund {
+			add(number)
+		}
+	}) {
+		commit.ProposalRefund = append(commit.ProposalRefund, commitRefunds{Number: number, Refunds: commitBalances(s.ProposalRefund[number])})
+	}
+	for _, signer := range sortedAddresses(len(s.SCCoinbase), func(add func(common.Address)) {
+		for signer := range s.SCCoinbase {
+			add(signer)
+		}
+	}) {
+		coinbases := commitSCCoinbases{Signer: signer}
+		for _, hash := range sortedHashes(len(s.SCCoinbase[signer]), func(add func(common.Hash)) {
+			for hash := range s.SCCoinbase[signer] {
+				add(hash)
+			}
+		}) {
+			coinbases.Coinbases = append(coinbases.Coinbases, commitSCCoinbase{SCHash: hash, Coinbase: s.SCCoinbase[signer][hash]})
+		}
+		commit.SCCoinbase = append(commit.SCCoinbase, coinbases)
+	}
+	for _, hash := range sortedHashes(len(s.SCRecordMap), func(add func(common.Hash)) {
+		for hash := range s.SCRecordMap {
+			add(hash)
+		}
+	}) {
+		commit.SCRecordMap = append(commit.SCRecordMap, commitSCRecordOf(hash, s.SCRecordMap[hash]))
+	}
+	for _, hash := range sortedHashes(len(s.SCRewardMap), func(add func(common.Hash)) {
+		for hash := range s.SCRewardMap {
+			add(hash)
+		}
+	}) {
+		reward := commitSCReward{SCHash: hash}
+		if blockRewards := s.SCRewardMap[hash]; blockRewards != nil {
+			for _, number := range sortedNumbers(len(blockRewards.SCBlockRewardMap), func(add func(uint64)) {
+				for number := range blockRewards.SCBlockRewardMap {
+					add(number)
+				}
+			}) {
+				blockReward := commitSCBlockReward{Number: number}
+				if scores := blockRewards.SCBlockRewardMap[number]; scores != nil {
+					blockReward.Scores = commitCounters(scores.RewardScoreMap)
+				}
+				reward.Rewards = append(reward.Rewards, blockReward)
+			}
+		}
+		commit.SCRewardMap = append(commit.SCRewardMap, reward)
+	}
+	for _, hash := range sortedHashes(len(s.SCNoticeMap), func(add func(common.Hash)) {
+		for hash := range s.SCNoticeMap {
+			add(hash)
+		}
+	}) {
+		commit.SCNoticeMap = append(commit.SCNoticeMap, commitCCNoticeOf(hash, s.SCNoticeMap[hash]))
+	}
+	if s.LocalNotice != nil {
+		commit.LocalNotice = []commitCCNotice{commitCCNoticeOf(common.Hash{}, s.LocalNotice)}
+	}
+	return commit
+}
+
+// commitProposalOf converts a proposal into its committed encoding.
+func commitProposalOf(proposal *Proposal) commitProposal {
+	commit := commitProposal{
+		Hash:                   proposal.Hash,
+		ReceivedNumber:         proposal.ReceivedNumber,
+		CurrentDeposit:         proposal.CurrentDeposit,
+		ValidationLoopCnt:      proposal.ValidationLoopCnt,
+		ProposalType:           proposal.ProposalType,
+		Proposer:               proposal.Proposer,
+		TargetAddress:          proposal.TargetAddress,
+		MinerRewardPerThousand: proposal.MinerRewardPerThousand,
+		SCHash:                 proposal.SCHash,
+		SCBlockCountPerPeriod:  proposal.SCBlockCountPerPeriod,
+		SCBlockRewardPerPeriod: proposal.SCBlockRewardPerPeriod,
+		MinVoterBalance:        proposal.MinVoterBalance,
+		ProposalDeposit:        proposal.ProposalDeposit,
+		SCRentFee:              proposal.SCRentFee,
+		SCRentRate:             proposal.SCRentRate,
+		SCRentLength:           proposal.SCRentLength,
+	}
+	// The declarations are kept in their order, as a declarer's latest one wins
+	for _, declare := range proposal.Declares {
+		commit.Declares = append(commit.Declares, commitDeclare{Declarer: declare.Declarer, Decision: declare.Decision, Abstain: declare.Abstain})
+	}
+	return commit
+}
+
+// commitSCRecordOf converts the record of a side chain into its committed encoding.
+func commitSCRecordOf(hash common.Hash, record *SCRecord) commitSCRecord {
+	commit := commitSCRecord{SCHash: hash}
+	if record == nil {
+		return commit
+	}
+	commit.LastConfirmedNumber = record.LastConfirmedNumber
+	commit.MaxHeaderNumber = record.MaxHeaderNumber
+	commit.CountPerPeriod = record.CountPerPeriod
+	commit.RewardPerPeriod = record.RewardPerPeriod
+
+	for _, number := range sortedNumbers(len(record.Record), func(add func(uint64)) {
+		for number := range record.Record {
+			add(number)
+		}
+	}) {
+		confirmations := commitSCConfirmations{Number: number}
+		for _, confirmation := range record.Record[number] {
+			confirmations.Confirmations = append(confirmations.Confirmations, commitSCConfirmation{
+				Hash:     confirmation.Hash,
+				Coinbase: confirmation.Coinbase,
+				Number:   confirmation.Number,
+				LoopInfo: confirmation.LoopInfo,
+			})
+		}
+		commit.Record = append(commit.Record, confirmations)
+	}
+	for _, rentHash := range sortedHashes(len(record.RentReward), func(add func(common.Hash)) {
+		for rentHash := range record.RentReward {
+			add(rentHash)
+		}
+	}) {
+		rent := commitSCRent{Hash: rentHash}
+		if info := record.RentReward[rentHash]; info != nil {
+			rent.RentPerPeriod, rent.MaxRewardNumber = info.RentPerPeriod, info.MaxRewardNumber
+		}
+		commit.RentReward = append(commit.RentReward, rent)
+	}
+	return commit
+}
+
+// commitCCNoticeOf converts a cross chain notice into its committed encoding.
+func commitCCNoticeOf(hash common.Hash, notice *CCNotice) commitCCNotice {
+	commit := commitCCNotice{SCHash: hash}
+	if notice == nil {
+		return commit
+	}
+	for _, chargeHash := range sortedHashes(len(notice.CurrentCharging), func(add func(common.Hash)) {
+		for chargeHash := range notice.CurrentCharging {
+			add(chargeHash)
+		}
+	}) {
+		charging := notice.CurrentCharging[chargeHash]
+		commit.CurrentCharging = append(commit.CurrentCharging, commitCharging{Hash: chargeHash, Target: charging.Target, Volume: charging.Volume})
+	}
+	for _, receivedHash := range sortedHashes(len(notice.ConfirmReceived), func(add func(common.Hash)) {
+		for receivedHash := range notice.ConfirmReceived {
+			add(receivedHash)
+		}
+	}) {
+		received := notice.ConfirmReceived[receivedHash]
+		entry := commitNoticeReceived{Hash: receivedHash, Number: received.Number, Type: received.Type, Success: received.Success}
+		for _, signer := range sortedAddresses(len(received.NRecord), func(add func(common.Address)) {
+			for signer := range received.NRecord {
+				add(signer)
+			}
+		}) {
+			entry.Confirms = append(entry.Confirms, commitNoticeConfirm{Signer: signer, Confirmed: received.NRecord[signer]})
+		}
+		commit.ConfirmReceived = append(commit.ConfirmReceived, entry)
+	}
+	return commit
+}
+
+// commitBalances converts a map of balances into a list sorted by address.
+func commitBalances(balances map[common.Address]*big.Int) []commitBalance {
+	var commit []commitBalance
+	for _, address := range sortedAddresses(len(balances), func(add func(common.Address)) {
+		for address := range balances {
+			add(address)
+		}
+	}) {
+		commit = append(commit, commitBalance{Address: address, Value: balances[address]})
+	}
+	return commit
+}
+
+// commitCounters converts a map of counters into a list sorted by address.
+func commitCounters(counters map[common.Address]uint64) []commitCounter {
+	var commit []commitCounter
+	for _, address := range sortedAddresses(len(counters), func(add func(common.Address)) {
+		for address := range counters {
+			add(address)
+		}
+	}) {
+		commit = append(commit, commitCounter{Address: address, Value: counters[address]})
+	}
+	return commit
+}
+
+// sortedAddresses collects the addresses produced by the given iteration and
+// sorts them.
+func sortedAddresses(size int, iterate func(add func(common.Address))) []common.Address {
+	addresses := make([]common.Address, 0, size)
+	iterate(func(address common.Address) { addresses = append(addresses, address) })
+	sort.Slice(addresses, func(i, j int) bool { return bytes.Compare(addresses[i][:], addresses[j][:]) < 0 })
+	return addresses
+}
+
+// sortedHashes collects the hashes produced by the given iteration and sorts
+// them.
+func sortedHashes(size int, iterate func(add func(common.Hash))) []common.Hash {
+	hashes := make([]common.Hash, 0, size)
+	iterate(func(hash common.Hash) { hashes = append(hashes, hash) })
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+	return hashes
+}
+
+// sortedNumbers collects the numbers produced by the given iteration and sorts
+// them.
+func sortedNumbers(size int, iterate func(add func(uint64))) []uint64 {
+	numbers := make([]uint64, 0, size)
+	iterate(func(number uint64) { numbers = append(numbers, number) })
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+)
+
+// newCommitTestSnapshot creates a snapshot with every committed field set.
+func newCommitTestSnapshot() *Snapshot {
+	var (
+		a = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		b = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		c = common.HexToAddress("0x0000000000000000000000000000000000000003")
+
+		proposal = common.HexToHash("0x0a")
+		chain    = common.HexToHash("0x0b")
+	)
+	return &Snapshot{
+		Period:          3,
+		Number:          100,
+		ConfirmedNumber: 98,
+		Hash:            common.HexToHash("0x64"),
+		HistoryHash:     []common.Hash{common.HexToHash("0x63"), common.HexToHash("0x64")},
+		Signers:         []*common.Address{&b, &a},
+		Votes: map[common.Address]*Vote{
+			a: {Voter: a, Candidate: a, Stake: big.NewInt(1000)},
+			c: {Voter: c, Candidate: b, Stake: big.NewInt(500)},
+		},
+		Tally:         map[common.Address]*big.Int{a: big.NewInt(1000), b: big.NewInt(500)},
+		Voters:        map[common.Address]*big.Int{a: big.NewInt(90), c: big.NewInt(95)},
+		Candidates:    map[common.Address]uint64{a: candidateStateNormal, b: candidateStateNormal},
+		Punished:      map[common.Address]uint64{b: 20},
+		Confirmations: map[uint64][]*common.Address{99: {&a}, 100: {&b, &a}},
+		Proposals: map[common.Hash]*Proposal{
+			proposal: {
+				Hash:              proposal,
+				ReceivedNumber:    big.NewInt(90),
+				CurrentDeposit:    big.NewInt(10),
+				ValidationLoopCnt: 3,
+				ProposalType:      proposalTypeCandidateAdd,
+				Proposer:          a,
+				TargetAddress:     c,
+				Declares:          []*Declare{{ProposalHash: proposal, Declarer: b, Decision: true}},
+			},
+		},
+		HeaderTime:     1500,
+		LoopStartTime:  1491,
+		ProposalRefund: map[uint64]map[common.Address]*big.Int{95: {a: big.NewInt(10)}},
+		SCCoinbase:     map[common.Address]map[common.Hash]common.Address{a: {chain: c}},
+		SCRecordMap: map[common.Hash]*SCRecord{
+			chain: {
+				Record:              map[uint64][]*SCConfirmation{7: {{Hash: common.HexToHash("0x07"), Coinbase: c, Number: 7, LoopInfo: []string{"x"}}}},
+				LastConfirmedNumber: 6,
+				MaxHeaderNumber:     7,
+				CountPerPeriod:      2,
+				RewardPerPeriod:     10,
+				RentReward:          map[common.Hash]*SCRentInfo{proposal: {RentPerPeriod: big.NewInt(5), MaxRewardNumber: big.NewInt(200)}},
+			},
+		},
+		SCRewardMap: map[common.Hash]*SCReward{
+			chain: {SCBlockRewardMap: map[uint64]*SCBlockReward{100: {RewardScoreMap: map[common.Address]uint64{c: 2}}}},
+		},
+		SCNoticeMap: map[common.Hash]*CCNotice{
+			chain: {
+				CurrentCharging: map[common.Hash]GasCharging{proposal: {Target: c, Volume: 4, Hash: proposal}},
+				ConfirmReceived: map[common.Hash]NoticeCR{proposal: {NRecord: map[common.Address]bool{a: true}, Number: 99, Type: 1, Success: true}},
+			},
+		},
+		LocalNotice: &CCNotice{
+			CurrentCharging: map[common.Hash]GasCharging{chain: {Target: a, Volume: 1, Hash: chain}},
+			ConfirmReceived: make(map[common.Hash]NoticeCR),
+		},
+		MinerReward: 618,
+		MinVB:       big.NewInt(1),
+	}
+}
+
+// Tests that the snapshot commitment is pinned, so any change to what headers
+// commit to has to come with a new commitment version.
+func TestSnapshotCommitHash(t *testing.T) {
+	snap := newCommitTestSnapshot()
+
+	hash, err := snap.commitHash()
+	if err != nil {
+		t.Fatalf("failed to hash snapshot: %v", err)
+	}
+	if want := common.HexToHash("0x061ca66e81d25107e607f7dacd2f548464034bfb8545993766c364fba6e27221"); hash != want {
+		t.Fatalf("commitment mismatch: have %x, want %x", hash, want)
+	}
+	// Informational fields must not change the commitment
+	snap.ProposalHistory = []*ProposalResult{{Number: 99, Proposal: snap.Proposals[common.HexToHash("0x0a")], Passed: true}}
+	if have, _ := snap.commitHash(); have != hash {
+		t.Errorf("proposal history changed the commitment: have %x, want %x", have, hash)
+	}
+	// Neither must the map order nor a round trip through a copy
+	for i := 0; i < 8; i++ {
+		if have, _ := newCommitTestSnapshot().copy().commitHash(); have != hash {
+			t.Fatalf("copy changed the commitment: have %x, want %x", have, hash)
+		}
+	}
+	// Every consensus field must
+	snap.Punished[common.HexToAddress("0x0000000000000000000000000000000000000002")]++
+	if have, _ := snap.commitHash(); have == hash {
+		t.Errorf("punishment not committed to")
+	}
+}
@@ -14,7 +14,6 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package alien implements the delegated-proof-of-stake consensus engine.
 package alien
 
 import (
@@ -48,6 +47,25 @@ var (
 	errMCGasChargingInvalid = errors.New("gas charging info is invalid")
 )
 
+// MainChainLink is the status of the link of a side chain node to its main chain.
+type MainChainLink struct {
+	Connected     bool   `json:"connected"`           // Whether the main chain answered over RPC
+	NetworkID     uint64 `json:"networkId,omitempty"` // Network id of the main chain
+	LastConfirmed uint64 `json:"lastConfirmed"`       // Last local block whose confirmation was sent to the main chain
+	Error         string `json:"error,omitempty"`     // Failure reaching the main chain
+}
+
+// MainChainLink checks the link of a side chain node to its main chain.
+func (a *Alien) MainChainLink(chain consensus.ChainReader) *MainChainLink {
+	link := &MainChainLink{LastConfirmed: a.lcsc}
+	if networkID, err := a.getNetVersionFromMainChain(chain); err != nil {
+		link.Error = err.Error()
+	} else {
+		link.Connected, link.NetworkID = true, networkID
+	}
+	return link
+}
+
 // getMainChainSnapshotByTime return snapshot by header time of side chain
 // the rpc api will return the snapshot with the same header time (not loopStartTime)
 func (a *Alien) getMainChainSnapshotByTime(chain consensus.ChainReader, headerTime uint64, scHash common.Hash) (*Snapshot, error) {
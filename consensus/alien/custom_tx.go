@@ -14,11 +14,11 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package alien implements the delegated-proof-of-stake consensus engine.
-
 package alien
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"github.com/TTCECO/gttc/params"
 	"math/big"
@@ -46,6 +46,7 @@ const (
 	ufoEventConfirm       = "confirm"
 	ufoEventPorposal      = "proposal"
 	ufoEventDeclare       = "declare"
+	ufoEventWithdraw      = "withdraw"
 	ufoEventSetCoinbase   = "setcb"
 	ufoMinSplitLen        = 3
 	posPrefix             = 0
@@ -55,6 +56,7 @@ const (
 	posEventConfirm       = 3
 	posEventProposal      = 3
 	posEventDeclare       = 3
+	posEventWithdraw      = 3
 	posEventSetCoinbase   = 3
 	posEventConfirmNumber = 4
 
@@ -91,6 +93,70 @@ const (
 //side chain related
 var minSCSetCoinbaseValue = big.NewInt(5e+18)
 
+// Various error messages to reject custom transactions before they enter the
+// transaction pool.
+var (
+	// errCustomTxVersion is returned if the version of a custom tx is not supported
+	errCustomTxVersion = errors.New("unsupported custom tx version")
+
+	// errCustomTxMalformed is returned if a custom tx misses required fields
+	errCustomTxMalformed = errors.New("malformed custom tx")
+
+	// errCustomTxUnknown is returned if the category or action of a custom tx is unknown
+	errCustomTxUnknown = errors.New("unknown custom tx")
+
+	// errVoteCandidateMissing is returned if a vote tx has no recipient as candidate
+	errVoteCandidateMissing = errors.New("vote without candidate")
+
+	// errVoteNotCandidate is returned if a vote tx is sent to an address which is not a candidate
+	errVoteNotCandidate = errors.New("vote for non candidate")
+
+	// errVoterBalanceTooLow is returned if the voter balance is not larger than the min voter balance
+	errVoterBalanceTooLow = errors.New("voter balance below min voter balance")
+
+	// errSenderNotCandidate is returned if a custom tx only allowed for candidates comes from other account
+	errSenderNotCandidate = errors.New("sender is not a candidate")
+
+	// errConfirmNumberInvalid is returned if the block number in confirm tx is invalid or out of the confirm window
+	errConfirmNumberInvalid = errors.New("invalid confirm block number")
+
+	// errConfirmerNotSigner is returned if the confirmer is not in the signer queue of confirmed block
+	errConfirmerNotSigner = errors.New("confirmer is not a signer of confirmed block")
+
+	// errProposalParamInvalid is returned if a parameter of proposal tx is invalid
+	errProposalParamInvalid = errors.New("invalid proposal parameter")
+
+	// errProposalDepositMissing is returned if the proposer can not pay the proposal deposit
+	errProposalDepositMissing = errors.New("insufficient balance for proposal deposit")
+
+	// errSideChainNotExist is returned if the side chain referred by custom tx is not exist
+	errSideChainNotExist = errors.New("side chain not exist")
+
+	// errRentTargetMissing is returned if a side chain rent proposal has no target address
+	errRentTargetMissing = errors.New("side chain rent target missing")
+
+	// errDeclareDecisionInvalid is returned if the decision of declare tx is not yes or no
+	errDeclareDecisionInvalid = errors.New("invalid declare decision")
+
+	// errAlreadyDeclared is returned if the declarer already declared on this proposal
+	errAlreadyDeclared = errors.New("proposal already declared by sender")
+
+	// errProposalNotOpen is returned if the proposal referred by custom tx is unknown or already decided
+	errProposalNotOpen = errors.New("proposal is not open")
+
+	// errWithdrawNotProposer is returned if a proposal is withdrawn by another account than its proposer
+	errWithdrawNotProposer = errors.New("sender is not the proposer")
+
+	// errSCConfirmInvalid is returned if a side chain confirm tx is invalid
+	errSCConfirmInvalid = errors.New("invalid side chain confirm")
+
+	// errSCCoinbaseNotRegistered is returned if side chain confirm tx not come from registered side chain coinbase
+	errSCCoinbaseNotRegistered = errors.New("sender is not a registered side chain coinbase")
+
+	// errSCSetCoinbaseInvalid is returned if set coinbase tx has no target or not enough value
+	errSCSetCoinbaseInvalid = errors.New("invalid side chain set coinbase")
+)
+
 // RefundGas :
 // refund gas to tx sender
 type RefundGas map[common.Address]*big.Int
@@ -176,10 +242,21 @@ func (p *Proposal) copy() *Proposal {
 // declare come from custom tx which data like "ufo:1:event:declare:hash:yes"
 // proposal only come from the current candidates
 // hash is the hash of proposal tx
+// decision may also be abstain after ProposalQuorum, counted for the quorum only
 type Declare struct {
 	ProposalHash common.Hash
 	Declarer     common.Address
 	Decision     bool
+	Abstain      bool `rlp:"-" json:",omitempty"` // abstentions are encoded apart in header.Extra
+}
+
+// ProposalWithdraw :
+// withdraw come from custom tx which data like "ufo:1:event:withdraw:hash:0x..."
+// only the proposer can withdraw its proposal, and only before it is decided
+// hash is the hash of proposal tx
+type ProposalWithdraw struct {
+	ProposalHash common.Hash
+	Proposer     common.Address
 }
 
 // SCConfirmation is the confirmed tx send by side chain super node
@@ -229,7 +306,33 @@ type HeaderExtra struct {
 	SideChainConfirmations    []SCConfirmation
 	SideChainSetCoinbases     []SCSetCoinbase
 	SideChainNoticeConfirmed  []SCConfirmation
-	SideChainCharging         []GasCharging //This only exist in side chain's header.Extra
+	SideChainCharging         []GasCharging      //This only exist in side chain's header.Extra
+	SnapshotHash              common.Hash        `rlp:"-"` // Commitment to the parent snapshot, only encoded after SnapshotCommit
+	ProposalWithdraws         []ProposalWithdraw `rlp:"-"` // Proposals withdrawn by their proposer, only encoded after ProposalLifecycle
+	CurrentBlockAbstains      []Declare          `rlp:"-"` // Abstentions on proposals, only encoded after ProposalQuorum
+}
+
+// headerExtraSnapshotCommit is the layout of header.Extra after the SnapshotCommit fork
+type headerExtraSnapshotCommit struct {
+	Extra        HeaderExtra
+	SnapshotHash common.Hash
+}
+
+// headerExtraProposalLifecycle is the layout of header.Extra after the ProposalLifecycle fork,
+// the snapshot hash stays empty until the SnapshotCommit fork
+type headerExtraProposalLifecycle struct {
+	Extra             HeaderExtra
+	SnapshotHash      common.Hash
+	ProposalWithdraws []ProposalWithdraw
+}
+
+// headerExtraProposalQuorum is the layout of header.Extra after the ProposalQuorum fork,
+// the fields of the previous layouts stay empty until their own forks
+type headerExtraProposalQuorum struct {
+	Extra                HeaderExtra
+	SnapshotHash         common.Hash
+	ProposalWithdraws    []ProposalWithdraw
+	CurrentBlockAbstains []Declare
 }
 
 // Encode HeaderExtra
@@ -237,8 +340,12 @@ func encodeHeaderExtra(config *params.AlienConfig, number *big.Int, val HeaderEx
 
 	var headerExtra interface{}
 	switch {
-	//case config.IsTrantor(number):
-
+	case config.IsProposalQuorum(number):
+		headerExtra = headerExtraProposalQuorum{Extra: val, SnapshotHash: val.SnapshotHash, ProposalWithdraws: val.ProposalWithdraws, CurrentBlockAbstains: val.CurrentBlockAbstains}
+	case config.IsProposalLifecycle(number):
+		headerExtra = headerExtraProposalLifecycle{Extra: val, SnapshotHash: val.SnapshotHash, ProposalWithdraws: val.ProposalWithdraws}
+	case config.IsSnapshotCommit(number):
+		headerExtra = headerExtraSnapshotCommit{Extra: val, SnapshotHash: val.SnapshotHash}
 	default:
 		headerExtra = val
 	}
@@ -250,7 +357,30 @@ func encodeHeaderExtra(config *params.AlienConfig, number *big.Int, val HeaderEx
 func decodeHeaderExtra(config *params.AlienConfig, number *big.Int, b []byte, val *HeaderExtra) error {
 	var err error
 	switch {
-	//case config.IsTrantor(number):
+	case config.IsProposalQuorum(number):
+		var headerExtra headerExtraProposalQuorum
+		if err = rlp.DecodeBytes(b, &headerExtra); err == nil {
+			*val = headerExtra.Extra
+			val.SnapshotHash = headerExtra.SnapshotHash
+			val.ProposalWithdraws = headerExtra.ProposalWithdraws
+			val.CurrentBlockAbstains = headerExtra.CurrentBlockAbstains
+			for i := range val.CurrentBlockAbstains {
+				val.CurrentBlockAbstains[i].Abstain = true
+			}
+		}
+	case config.IsProposalLifecycle(number):
+		var headerExtra headerExtraProposalLifecycle
+		if err = rlp.DecodeBytes(b, &headerExtra); err == nil {
+			*val = headerExtra.Extra
+			val.SnapshotHash = headerExtra.SnapshotHash
+			val.ProposalWithdraws = headerExtra.ProposalWithdraws
+		}
+	case config.IsSnapshotCommit(number):
+		var headerExtra headerExtraSnapshotCommit
+		if err = rlp.DecodeBytes(b, &headerExtra); err == nil {
+			*val = headerExtra.Extra
+			val.SnapshotHash = headerExtra.SnapshotHash
+		}
 	default:
 		err = rlp.DecodeBytes(b, val)
 	}
@@ -310,7 +440,9 @@ func (a *Alien) processCustomTx(headerExtra HeaderExtra, chain consensus.ChainRe
 								} else if txDataInfo[posEventProposal] == ufoEventPorposal {
 									headerExtra.CurrentBlockProposals = a.processEventProposal(headerExtra.CurrentBlockProposals, txDataInfo, state, tx, txSender, snap)
 								} else if txDataInfo[posEventDeclare] == ufoEventDeclare && snap.isCandidate(txSender) {
-									headerExtra.CurrentBlockDeclares = a.processEventDeclare(headerExtra.CurrentBlockDeclares, txDataInfo, tx, txSender)
+									headerExtra.CurrentBlockDeclares, headerExtra.CurrentBlockAbstains = a.processEventDeclare(headerExtra.CurrentBlockDeclares, headerExtra.CurrentBlockAbstains, txDataInfo, header.Number, txSender)
+								} else if txDataInfo[posEventWithdraw] == ufoEventWithdraw && a.config.IsProposalLifecycle(header.Number) {
+									headerExtra.ProposalWithdraws = a.processEventWithdraw(headerExtra.ProposalWithdraws, txDataInfo, txSender, snap)
 								}
 							} else {
 								// todo : something wrong, leave this transaction to process as normal transaction
@@ -371,6 +503,212 @@ func (a *Alien) processCustomTx(headerExtra HeaderExtra, chain consensus.ChainRe
 	return headerExtra, refundGas, nil
 }
 
+// PrepareTxValidation implements consensus.TxValidator, building the snapshot
+// the transaction pool hooks check the transactions against on the new head.
+func (a *Alien) PrepareTxValidation(chain consensus.ChainReader, head *types.Header) {
+	if chain.Config().Alien.SideChain || head.Number.Uint64() == 0 {
+		return
+	}
+	if _, err := a.poolSnapshot(chain, head); err != nil {
+		log.Warn("Failed to prepare alien tx validation", "number", head.Number, "hash", head.Hash(), "err", err)
+	}
+}
+
+// poolSnapshot returns the snapshot at the given head for the transaction pool
+// hooks. As the hooks run for every pooled transaction with the pool locked, the
+// snapshot is only retrieved once per head.
+func (a *Alien) poolSnapshot(chain consensus.ChainReader, head *types.Header) (*Snapshot, error) {
+	hash := head.Hash()
+
+	a.poolLock.Lock()
+	defer a.poolLock.Unlock()
+
+	if a.poolSnap != nil && a.poolSnap.Hash == hash {
+		return a.poolSnap, nil
+	}
+	snap, err := a.snapshot(chain, head.Number.Uint64(), hash, nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	a.poolSnap = snap
+	return snap, nil
+}
+
+// ValidateTx implements consensus.TxValidator, rejecting custom transactions
+// which are malformed or would be ignored by processCustomTx on top of the
+// current chain head.
+//
+// Note, the admission rule is stricter than consensus: a "ufo:" payload with an
+// unsupported version, or an unknown category or event, is a valid block
+// transaction that is merely executed as a plain transfer, but it is refused by
+// the pool as the sender surely meant a custom tx that would never take effect.
+// Such transactions are still accepted in blocks sealed by other nodes.
+func (a *Alien) ValidateTx(chain consensus.ChainReader, state *state.StateDB, tx *types.Transaction, sender common.Address) error {
+	txDataInfo := strings.Split(string(tx.Data()), ":")
+	if len(txDataInfo) < ufoMinSplitLen || txDataInfo[posPrefix] != ufoPrefix {
+		return nil
+	}
+	// custom txs are only processed on main chain
+	if chain.Config().Alien.SideChain {
+		return nil
+	}
+	// the genesis snapshot must be built with the genesis votes, never here
+	header := chain.CurrentHeader()
+	if header == nil || header.Number.Uint64() == 0 {
+		return nil
+	}
+	snap, err := a.poolSnapshot(chain, header)
+	if err != nil {
+		return err
+	}
+	return a.validateCustomTx(chain, snap, state, header.Number.Uint64()+1, txDataInfo, tx, sender)
+}
+
+// IsConsensusTx implements consensus.TxPrioritizer, returning whether the tx is
+// a block confirmation from a current signer or candidate, or a side chain
+// confirmation from a side chain coinbase registered by a current signer.
+func (a *Alien) IsConsensusTx(chain consensus.ChainReader, tx *types.Transaction, sender common.Address) bool {
+	if !bytes.HasPrefix(tx.Data(), []byte(ufoPrefix+":")) || chain.Config().Alien.SideChain {
+		return false
+	}
+	txDataInfo := strings.Split(string(tx.Data()), ":")
+	if len(txDataInfo) <= ufoMinSplitLen || txDataInfo[posVersion] != ufoVersion || txDataInfo[posEventConfirm] != ufoEventConfirm {
+		return false
+	}
+	header := chain.CurrentHeader()
+	if header == nil || header.Number.Uint64() == 0 {
+		return false
+	}
+	snap, err := a.poolSnapshot(chain, header)
+	if err != nil {
+		return false
+	}
+	switch txDataInfo[posCategory] {
+	case ufoCategoryEvent:
+		return snap.isSigner(sender) || snap.isCandidate(sender)
+	case ufoCategorySC:
+		if len(txDataInfo) > ufoMinSplitLen+1 {
+			return snap.isSideChainCoinbase(common.HexToHash(txDataInfo[ufoMinSplitLen+1]), sender, true)
+		}
+	}
+	return false
+}
+
+// validateCustomTx checks the custom tx against the snapshot and state the
+// block with the given number will be built on.
+func (a *Alien) validateCustomTx(chain consensus.ChainReader, snap *Snapshot, state *state.StateDB, number uint64, txDataInfo []string, tx *types.Transaction, sender common.Address) error {
+	if txDataInfo[posVersion] != ufoVersion {
+		return fmt.Errorf("%v: %s", errCustomTxVersion, txDataInfo[posVersion])
+	}
+	switch txDataInfo[posCategory] {
+	case ufoCategoryEvent:
+		if len(txDataInfo) <= ufoMinSplitLen {
+			return errCustomTxMalformed
+		}
+		switch txDataInfo[posEventVote] {
+		case ufoEventVote:
+			if tx.To() == nil {
+				return errVoteCandidateMissing
+			}
+			if candidateNeedPD && !snap.isCandidate(*tx.To()) {
+				return errVoteNotCandidate
+			}
+			// the stake is the balance after this tx executed, which never exceed balance - value
+			if new(big.Int).Sub(state.GetBalance(sender), tx.Value()).Cmp(snap.MinVB) <= 0 {
+				return errVoterBalanceTooLow
+			}
+		case ufoEventConfirm:
+			if !snap.isCandidate(sender) {
+				return errSenderNotCandidate
+			}
+			if _, err := a.checkEventConfirm(chain, txDataInfo, number, sender); err != nil {
+				return err
+			}
+		case ufoEventPorposal:
+			_, pay, err := a.buildProposal(txDataInfo, tx.Hash(), sender, snap)
+			if err != nil {
+				return err
+			}
+			if new(big.Int).Sub(state.GetBalance(sender), tx.Value()).Cmp(pay) < 0 {
+				return errProposalDepositMissing
+			}
+		case ufoEventDeclare:
+			if !snap.isCandidate(sender) {
+				return errSenderNotCandidate
+			}
+			declare, err := a.buildDeclare(txDataInfo, sender)
+			if err != nil {
+				return err
+			}
+			if declare.Abstain && !a.config.IsProposalQuorum(new(big.Int).SetUint64(number)) {
+				return fmt.Errorf("%v: abstain", errDeclareDecisionInvalid)
+			}
+			// the latest declaration wins after ProposalLifecycle, so only repeating it is useless
+			lifecycle := a.config.IsProposalLifecycle(new(big.Int).SetUint64(number))
+			if proposal, ok := snap.Proposals[declare.ProposalHash]; ok {
+				for _, v := range proposal.Declares {
+					if v.Declarer == sender && (!lifecycle || (v.Decision == declare.Decision && v.Abstain == declare.Abstain)) {
+						return errAlreadyDeclared
+					}
+				}
+			}
+		case ufoEventWithdraw:
+			if !a.config.IsProposalLifecycle(new(big.Int).SetUint64(number)) {
+				return fmt.Errorf("%v: %s", errCustomTxUnknown, txDataInfo[posEventWithdraw])
+			}
+			withdraw, err := a.buildWithdraw(txDataInfo, sender)
+			if err != nil {
+				return err
+			}
+			if err := snap.checkWithdraw(withdraw); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%v: %s", errCustomTxUnknown, txDataInfo[posEventVote])
+		}
+	case ufoCategoryLog:
+		// todo : oplog is not processed yet
+	case ufoCategorySC:
+		if len(txDataInfo) <= ufoMinSplitLen {
+			return errCustomTxMalformed
+		}
+		switch txDataInfo[posEventConfirm] {
+		case ufoEventConfirm:
+			if len(txDataInfo) <= ufoMinSplitLen+5 {
+				return errCustomTxMalformed
+			}
+			if err := new(big.Int).UnmarshalText([]byte(txDataInfo[ufoMinSplitLen+2])); err != nil {
+				return fmt.Errorf("%v: number %s", errSCConfirmInvalid, txDataInfo[ufoMinSplitLen+2])
+			}
+			if err := new(big.Int).UnmarshalText([]byte(txDataInfo[ufoMinSplitLen+3])); err != nil {
+				return fmt.Errorf("%v: time %s", errSCConfirmInvalid, txDataInfo[ufoMinSplitLen+3])
+			}
+			scHash := common.HexToHash(txDataInfo[ufoMinSplitLen+1])
+			if !snap.isSideChainExist(scHash) {
+				return errSideChainNotExist
+			}
+			if !snap.isSideChainCoinbase(scHash, sender, false) {
+				return errSCCoinbaseNotRegistered
+			}
+		case ufoEventSetCoinbase:
+			if !snap.isCandidate(sender) {
+				return errSenderNotCandidate
+			}
+			if len(txDataInfo) <= ufoMinSplitLen+1 {
+				return errCustomTxMalformed
+			}
+			if tx.Value().Cmp(minSCSetCoinbaseValue) < 0 || tx.To() == nil {
+				return errSCSetCoinbaseInvalid
+			}
+		default:
+			return fmt.Errorf("%v: %s", errCustomTxUnknown, txDataInfo[posEventConfirm])
+		}
+	default:
+		return fmt.Errorf("%v: %s", errCustomTxUnknown, txDataInfo[posCategory])
+	}
+	return nil
+}
+
 func (a *Alien) refundAddGas(refundGas RefundGas, address common.Address, value *big.Int) RefundGas {
 	if _, ok := refundGas[address]; ok {
 		refundGas[address].Add(refundGas[address], value)
@@ -418,12 +756,29 @@ func (a *Alien) processEventProposal(currentBlockProposals []Proposal, txDataInf
 	// eth.sendTransaction({from:eth.accounts[0],to:eth.accounts[0],value:0,data:web3.toHex("ufo:1:event:proposal:proposal_type:4:sccount:2:screward:50:schash:0x3210000000000000000000000000000000000000000000000000000000000000:vlcnt:4")})
 	// sample for declare
 	// eth.sendTransaction({from:eth.accounts[0],to:eth.accounts[0],value:0,data:web3.toHex("ufo:1:event:declare:hash:0x853e10706e6b9d39c5f4719018aa2417e8b852dec8ad18f9c592d526db64c725:decision:yes")})
-	if len(txDataInfo) <= posEventProposal+2 {
+	proposal, currentProposalPay, err := a.buildProposal(txDataInfo, tx.Hash(), proposer, snap)
+	if err != nil {
 		return currentBlockProposals
 	}
+	// check enough balance for deposit
+	if state.GetBalance(proposer).Cmp(currentProposalPay) < 0 {
+		return currentBlockProposals
+	}
+	// collection the fee for this proposal (deposit and other fee , sc rent fee ...)
+	state.SetBalance(proposer, new(big.Int).Sub(state.GetBalance(proposer), currentProposalPay))
+
+	return append(currentBlockProposals, proposal)
+}
+
+// buildProposal parses the proposal custom tx data and returns the proposal with
+// the amount the proposer has to pay for it (deposit and side chain rent fee).
+func (a *Alien) buildProposal(txDataInfo []string, txHash common.Hash, proposer common.Address, snap *Snapshot) (Proposal, *big.Int, error) {
+	if len(txDataInfo) <= posEventProposal+2 {
+		return Proposal{}, nil, errCustomTxMalformed
+	}
 
 	proposal := Proposal{
-		Hash:                   tx.Hash(),
+		Hash:                   txHash,
 		ReceivedNumber:         big.NewInt(0),
 		CurrentDeposit:         proposalDeposit, // for all type of deposit
 		ValidationLoopCnt:      defaultValidationLoopCnt,
@@ -448,7 +803,7 @@ func (a *Alien) processEventProposal(currentBlockProposals []Proposal, txDataInf
 		case "vlcnt":
 			// If vlcnt is missing then user default value, but if the vlcnt is beyond the min/max value then ignore this proposal
 			if validationLoopCnt, err := strconv.Atoi(v); err != nil || validationLoopCnt < minValidationLoopCnt || validationLoopCnt > maxValidationLoopCnt {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: vlcnt %s", errProposalParamInvalid, v)
 			} else {
 				proposal.ValidationLoopCnt = uint64(validationLoopCnt)
 			}
@@ -456,19 +811,19 @@ func (a *Alien) processEventProposal(currentBlockProposals []Proposal, txDataInf
 			proposal.SCHash.UnmarshalText([]byte(v))
 		case "sccount":
 			if scBlockCountPerPeriod, err := strconv.Atoi(v); err != nil {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: sccount %s", errProposalParamInvalid, v)
 			} else {
 				proposal.SCBlockCountPerPeriod = uint64(scBlockCountPerPeriod)
 			}
 		case "screward":
 			if scBlockRewardPerPeriod, err := strconv.Atoi(v); err != nil {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: screward %s", errProposalParamInvalid, v)
 			} else {
 				proposal.SCBlockRewardPerPeriod = uint64(scBlockRewardPerPeriod)
 			}
 		case "proposal_type":
 			if proposalType, err := strconv.Atoi(v); err != nil {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: proposal_type %s", errProposalParamInvalid, v)
 			} else {
 				proposal.ProposalType = uint64(proposalType)
 			}
@@ -478,21 +833,21 @@ func (a *Alien) processEventProposal(currentBlockProposals []Proposal, txDataInf
 		case "mrpt":
 			// miner reward per thousand
 			if mrpt, err := strconv.Atoi(v); err != nil || mrpt <= 0 || mrpt > 1000 {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: mrpt %s", errProposalParamInvalid, v)
 			} else {
 				proposal.MinerRewardPerThousand = uint64(mrpt)
 			}
 		case "mvb":
 			// minVoterBalance
 			if mvb, err := strconv.Atoi(v); err != nil || mvb <= 0 {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: mvb %s", errProposalParamInvalid, v)
 			} else {
 				proposal.MinVoterBalance = uint64(mvb)
 			}
 		case "mpd":
 			// proposalDeposit
 			if mpd, err := strconv.Atoi(v); err != nil || mpd <= 0 || mpd > maxProposalDeposit {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: mpd %s", errProposalParamInvalid, v)
 			} else {
 				proposal.ProposalDeposit = uint64(mpd)
 			}
@@ -502,21 +857,21 @@ func (a *Alien) processEventProposal(currentBlockProposals []Proposal, txDataInf
 		case "scrf":
 			// side chain rent fee
 			if scrf, err := strconv.Atoi(v); err != nil || scrf < minSCRentFee {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: scrf %s", errProposalParamInvalid, v)
 			} else {
 				proposal.SCRentFee = uint64(scrf)
 			}
 		case "scrr":
 			// side chain rent rate
 			if scrr, err := strconv.Atoi(v); err != nil || scrr <= 0 {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: scrr %s", errProposalParamInvalid, v)
 			} else {
 				proposal.SCRentRate = uint64(scrr)
 			}
 		case "scrl":
 			// side chain rent length
 			if scrl, err := strconv.Atoi(v); err != nil || scrl < minSCRentLength || scrl > maxSCRentLength {
-				return currentBlockProposals
+				return Proposal{}, nil, fmt.Errorf("%v: scrl %s", errProposalParamInvalid, v)
 			} else {
 				proposal.SCRentLength = uint64(scrl)
 			}
@@ -525,28 +880,55 @@ func (a *Alien) processEventProposal(currentBlockProposals []Proposal, txDataInf
 	// now the proposal is built
 	currentProposalPay := new(big.Int).Set(proposalDeposit)
 	if proposal.ProposalType == proposalTypeRentSideChain {
-		// check if the proposal target side chain exist
-		if !snap.isSideChainExist(proposal.SCHash) {
-			return currentBlockProposals
+		// check if the proposal target side chain exist, unless decoded without a snapshot
+		if snap != nil && !snap.isSideChainExist(proposal.SCHash) {
+			return Proposal{}, nil, errSideChainNotExist
 		}
 		if (proposal.TargetAddress == common.Address{}) {
-			return currentBlockProposals
+			return Proposal{}, nil, errRentTargetMissing
 		}
 		currentProposalPay.Add(currentProposalPay, new(big.Int).Mul(new(big.Int).SetUint64(proposal.SCRentFee), big.NewInt(1e+18)))
 	}
-	// check enough balance for deposit
-	if state.GetBalance(proposer).Cmp(currentProposalPay) < 0 {
-		return currentBlockProposals
+	return proposal, currentProposalPay, nil
+}
+
+func (a *Alien) processEventDeclare(currentBlockDeclares []Declare, currentBlockAbstains []Declare, txDataInfo []string, number *big.Int, declarer common.Address) ([]Declare, []Declare) {
+	declare, err := a.buildDeclare(txDataInfo, declarer)
+	if err != nil {
+		return currentBlockDeclares, currentBlockAbstains
 	}
-	// collection the fee for this proposal (deposit and other fee , sc rent fee ...)
-	state.SetBalance(proposer, new(big.Int).Sub(state.GetBalance(proposer), currentProposalPay))
+	if declare.Abstain && !a.config.IsProposalQuorum(number) {
+		return currentBlockDeclares, currentBlockAbstains
+	}
+	// After ProposalQuorum the abstentions are applied after the declarations of
+	// the block, so only the latest declaration of each declarer on a proposal is
+	// kept in the block for it to win whichever list it ends up in
+	if a.config.IsProposalQuorum(number) {
+		currentBlockDeclares = withoutDeclare(currentBlockDeclares, declare)
+		currentBlockAbstains = withoutDeclare(currentBlockAbstains, declare)
+	}
+	if declare.Abstain {
+		return currentBlockDeclares, append(currentBlockAbstains, declare)
+	}
+	return append(currentBlockDeclares, declare), currentBlockAbstains
+}
 
-	return append(currentBlockProposals, proposal)
+// withoutDeclare returns the declarations but the ones of the same declarer on
+// the same proposal as the given one.
+func withoutDeclare(declares []Declare, declare Declare) []Declare {
+	var kept []Declare
+	for _, v := range declares {
+		if v.ProposalHash != declare.ProposalHash || v.Declarer != declare.Declarer {
+			kept = append(kept, v)
+		}
+	}
+	return kept
 }
 
-func (a *Alien) processEventDeclare(currentBlockDeclares []Declare, txDataInfo []string, tx *types.Transaction, declarer common.Address) []Declare {
+// buildDeclare parses the declare custom tx data of the given declarer.
+func (a *Alien) buildDeclare(txDataInfo []string, declarer common.Address) (Declare, error) {
 	if len(txDataInfo) <= posEventDeclare+2 {
-		return currentBlockDeclares
+		return Declare{}, errCustomTxMalformed
 	}
 	declare := Declare{
 		ProposalHash: common.Hash{},
@@ -563,13 +945,34 @@ func (a *Alien) processEventDeclare(currentBlockDeclares []Declare, txDataInfo [
 				declare.Decision = true
 			} else if v == "no" {
 				declare.Decision = false
+			} else if v == "abstain" {
+				declare.Decision, declare.Abstain = false, true
 			} else {
-				return currentBlockDeclares
+				return Declare{}, fmt.Errorf("%v: %s", errDeclareDecisionInvalid, v)
 			}
 		}
 	}
+	return declare, nil
+}
 
-	return append(currentBlockDeclares, declare)
+func (a *Alien) processEventWithdraw(proposalWithdraws []ProposalWithdraw, txDataInfo []string, proposer common.Address, snap *Snapshot) []ProposalWithdraw {
+	withdraw, err := a.buildWithdraw(txDataInfo, proposer)
+	if err != nil || snap.checkWithdraw(withdraw) != nil {
+		return proposalWithdraws
+	}
+	return append(proposalWithdraws, withdraw)
+}
+
+// buildWithdraw parses the withdraw custom tx data of the given proposer.
+func (a *Alien) buildWithdraw(txDataInfo []string, proposer common.Address) (ProposalWithdraw, error) {
+	if len(txDataInfo) <= posEventWithdraw+2 || txDataInfo[posEventWithdraw+1] != "hash" {
+		return ProposalWithdraw{}, errCustomTxMalformed
+	}
+	withdraw := ProposalWithdraw{Proposer: proposer}
+	if err := withdraw.ProposalHash.UnmarshalText([]byte(txDataInfo[posEventWithdraw+2])); err != nil {
+		return ProposalWithdraw{}, fmt.Errorf("%v: %s", errCustomTxMalformed, txDataInfo[posEventWithdraw+2])
+	}
+	return withdraw, nil
 }
 
 func (a *Alien) processEventVote(currentBlockVotes []Vote, state *state.StateDB, tx *types.Transaction, voter common.Address) []Vote {
@@ -589,41 +992,55 @@ func (a *Alien) processEventVote(currentBlockVotes []Vote, state *state.StateDB,
 
 func (a *Alien) processEventConfirm(currentBlockConfirmations []Confirmation, chain consensus.ChainReader, txDataInfo []string, number uint64, tx *types.Transaction, confirmer common.Address, refundHash RefundHash) ([]Confirmation, RefundHash) {
 	if len(txDataInfo) > posEventConfirmNumber {
-		confirmedBlockNumber := new(big.Int)
-		err := confirmedBlockNumber.UnmarshalText([]byte(txDataInfo[posEventConfirmNumber]))
-		if err != nil || number-confirmedBlockNumber.Uint64() > a.config.MaxSignerCount || number-confirmedBlockNumber.Uint64() < 0 {
-			return currentBlockConfirmations, refundHash
-		}
-		// check if the voter is in block
-		confirmedHeader := chain.GetHeaderByNumber(confirmedBlockNumber.Uint64())
-		if confirmedHeader == nil {
-			//log.Info("Fail to get confirmedHeader")
-			return currentBlockConfirmations, refundHash
-		}
-		confirmedHeaderExtra := HeaderExtra{}
-		if extraVanity+extraSeal > len(confirmedHeader.Extra) {
-			return currentBlockConfirmations, refundHash
-		}
-		err = decodeHeaderExtra(a.config, confirmedBlockNumber, confirmedHeader.Extra[extraVanity:len(confirmedHeader.Extra)-extraSeal], &confirmedHeaderExtra)
+		confirmedBlockNumber, err := a.checkEventConfirm(chain, txDataInfo, number, confirmer)
 		if err != nil {
-			log.Info("Fail to decode parent header", "err", err)
 			return currentBlockConfirmations, refundHash
 		}
-		for _, s := range confirmedHeaderExtra.SignerQueue {
-			if s == confirmer {
-				currentBlockConfirmations = append(currentBlockConfirmations, Confirmation{
-					Signer:      confirmer,
-					BlockNumber: new(big.Int).Set(confirmedBlockNumber),
-				})
-				refundHash[tx.Hash()] = RefundPair{confirmer, tx.GasPrice()}
-				break
-			}
-		}
+		currentBlockConfirmations = append(currentBlockConfirmations, Confirmation{
+			Signer:      confirmer,
+			BlockNumber: new(big.Int).Set(confirmedBlockNumber),
+		})
+		refundHash[tx.Hash()] = RefundPair{confirmer, tx.GasPrice()}
 	}
 
 	return currentBlockConfirmations, refundHash
 }
 
+// checkEventConfirm returns the block number confirmed by the confirm custom tx
+// data if the confirmer is in the signer queue of that block and the block is
+// still inside the confirmation window of the block with the given number.
+func (a *Alien) checkEventConfirm(chain consensus.ChainReader, txDataInfo []string, number uint64, confirmer common.Address) (*big.Int, error) {
+	if len(txDataInfo) <= posEventConfirmNumber {
+		return nil, errCustomTxMalformed
+	}
+	confirmedBlockNumber := new(big.Int)
+	err := confirmedBlockNumber.UnmarshalText([]byte(txDataInfo[posEventConfirmNumber]))
+	if err != nil || number-confirmedBlockNumber.Uint64() > a.config.MaxSignerCount || number-confirmedBlockNumber.Uint64() < 0 {
+		return nil, fmt.Errorf("%v: %s", errConfirmNumberInvalid, txDataInfo[posEventConfirmNumber])
+	}
+	// check if the voter is in block
+	confirmedHeader := chain.GetHeaderByNumber(confirmedBlockNumber.Uint64())
+	if confirmedHeader == nil {
+		//log.Info("Fail to get confirmedHeader")
+		return nil, errUnknownBlock
+	}
+	confirmedHeaderExtra := HeaderExtra{}
+	if extraVanity+extraSeal > len(confirmedHeader.Extra) {
+		return nil, errMissingSignature
+	}
+	err = decodeHeaderExtra(a.config, confirmedBlockNumber, confirmedHeader.Extra[extraVanity:len(confirmedHeader.Extra)-extraSeal], &confirmedHeaderExtra)
+	if err != nil {
+		log.Info("Fail to decode parent header", "err", err)
+		return nil, err
+	}
+	for _, s := range confirmedHeaderExtra.SignerQueue {
+		if s == confirmer {
+			return confirmedBlockNumber, nil
+		}
+	}
+	return nil, errConfirmerNotSigner
+}
+
 func (a *Alien) processPredecessorVoter(modifyPredecessorVotes []Vote, state *state.StateDB, tx *types.Transaction, voter common.Address, snap *Snapshot) []Vote {
 	// process normal transaction which relate to voter
 	if tx.Value().Cmp(big.NewInt(0)) > 0 && tx.To() != nil {
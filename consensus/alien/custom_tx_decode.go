@@ -0,0 +1,140 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/TTCECO/gttc/common"
+)
+
+// CustomTx is the decoded content of an alien custom transaction, the data of
+// which is a string like "ufo:version:category:action:...". Only the fields of
+// the given category and action are set.
+type CustomTx struct {
+	Category string `json:"category"` // Category of the tx (event, oplog or sc)
+	Action   string `json:"action"`   // Action of the tx (vote, confirm, proposal, declare, withdraw or setcb)
+
+	Candidate *common.Address   `json:"candidate,omitempty"` // Candidate voted for
+	Number    *uint64           `json:"number,omitempty"`    // Confirmed block number, of the side chain for sc confirmations
+	Proposal  *Proposal         `json:"proposal,omitempty"`  // Proposal with the defaults filled in
+	Deposit   *big.Int          `json:"deposit,omitempty"`   // Deposit frozen from the proposer balance, in wei
+	Declare   *Declare          `json:"declare,omitempty"`   // Declaration on a proposal
+	Withdraw  *ProposalWithdraw `json:"withdraw,omitempty"`  // Withdrawal of a proposal
+	SideChain *common.Hash      `json:"sideChain,omitempty"` // Side chain of an sc tx
+	Coinbase  *common.Address   `json:"coinbase,omitempty"`  // Side chain coinbase being set
+}
+
+// IsCustomTx returns whether the given tx data is an alien custom transaction.
+func IsCustomTx(data []byte) bool {
+	return strings.HasPrefix(string(data), ufoPrefix+":")
+}
+
+// DecodeCustomTx decodes and statically validates the data of a custom tx sent
+// from the given address, with the given recipient and value. The checks which
+// depend on the chain state, like the sender being a candidate, are left to
+// the consensus engine.
+func DecodeCustomTx(data []byte, from common.Address, to *common.Address, value *big.Int) (*CustomTx, error) {
+	txDataInfo := strings.Split(string(data), ":")
+	if len(txDataInfo) <= ufoMinSplitLen || txDataInfo[posPrefix] != ufoPrefix {
+		return nil, errCustomTxMalformed
+	}
+	if txDataInfo[posVersion] != ufoVersion {
+		return nil, fmt.Errorf("%v: %s", errCustomTxVersion, txDataInfo[posVersion])
+	}
+	decoded := &CustomTx{
+		Category: txDataInfo[posCategory],
+		Action:   txDataInfo[posEventVote],
+	}
+	switch decoded.Category {
+	case ufoCategoryEvent:
+		switch decoded.Action {
+		case ufoEventVote:
+			if to == nil {
+				return nil, errVoteCandidateMissing
+			}
+			decoded.Candidate = to
+		case ufoEventConfirm:
+			if len(txDataInfo) <= posEventConfirmNumber {
+				return nil, errCustomTxMalformed
+			}
+			number := new(big.Int)
+			if err := number.UnmarshalText([]byte(txDataInfo[posEventConfirmNumber])); err != nil || !number.IsUint64() {
+				return nil, fmt.Errorf("%v: %s", errConfirmNumberInvalid, txDataInfo[posEventConfirmNumber])
+			}
+			confirmed := number.Uint64()
+			decoded.Number = &confirmed
+		case ufoEventPorposal:
+			// The hash of the proposal is the tx hash, unknown before signing
+			proposal, deposit, err := new(Alien).buildProposal(txDataInfo, common.Hash{}, from, nil)
+			if err != nil {
+				return nil, err
+			}
+			decoded.Proposal, decoded.Deposit = &proposal, deposit
+		case ufoEventDeclare:
+			declare, err := new(Alien).buildDeclare(txDataInfo, from)
+			if err != nil {
+				return nil, err
+			}
+			decoded.Declare = &declare
+		case ufoEventWithdraw:
+			withdraw, err := new(Alien).buildWithdraw(txDataInfo, from)
+			if err != nil {
+				return nil, err
+			}
+			decoded.Withdraw = &withdraw
+		default:
+			return nil, fmt.Errorf("%v: %s", errCustomTxUnknown, decoded.Action)
+		}
+	case ufoCategoryLog:
+		// todo : oplog is not processed yet
+	case ufoCategorySC:
+		if len(txDataInfo) <= ufoMinSplitLen+1 {
+			return nil, errCustomTxMalformed
+		}
+		scHash := common.HexToHash(txDataInfo[ufoMinSplitLen+1])
+		decoded.SideChain = &scHash
+
+		switch decoded.Action {
+		case ufoEventConfirm:
+			if len(txDataInfo) <= ufoMinSplitLen+5 {
+				return nil, errCustomTxMalformed
+			}
+			number := new(big.Int)
+			if err := number.UnmarshalText([]byte(txDataInfo[ufoMinSplitLen+2])); err != nil || !number.IsUint64() {
+				return nil, fmt.Errorf("%v: number %s", errSCConfirmInvalid, txDataInfo[ufoMinSplitLen+2])
+			}
+			if err := new(big.Int).UnmarshalText([]byte(txDataInfo[ufoMinSplitLen+3])); err != nil {
+				return nil, fmt.Errorf("%v: time %s", errSCConfirmInvalid, txDataInfo[ufoMinSplitLen+3])
+			}
+			confirmed := number.Uint64()
+			decoded.Number = &confirmed
+		case ufoEventSetCoinbase:
+			if to == nil || value == nil || value.Cmp(minSCSetCoinbaseValue) < 0 {
+				return nil, errSCSetCoinbaseInvalid
+			}
+			decoded.Coinbase = to
+		default:
+			return nil, fmt.Errorf("%v: %s", errCustomTxUnknown, decoded.Action)
+		}
+	default:
+		return nil, fmt.Errorf("%v: %s", errCustomTxUnknown, decoded.Category)
+	}
+	return decoded, nil
+}
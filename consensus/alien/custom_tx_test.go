@@ -0,0 +1,220 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/state"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+func TestAlien_ValidateCustomTx(t *testing.T) {
+	accounts := newTesterAccountPool()
+	config := &params.AlienConfig{
+		Period:          3,
+		Epoch:           30000,
+		MaxSignerCount:  3,
+		MinVoterBalance: big.NewInt(100),
+		SelfVoteSigners: []common.UnprefixedAddress{common.UnprefixedAddress(accounts.address("A"))},
+	}
+	alien := New(config, ethdb.NewMemDatabase())
+	snap := newSnapshot(alien.config, alien.signatures, common.Hash{}, []*Vote{
+		{Voter: accounts.address("A"), Candidate: accounts.address("A"), Stake: big.NewInt(1000)},
+	}, defaultLoopCntRecalculateSigners)
+	snap.MinVB = big.NewInt(100)
+	declared := common.HexToHash("0x01")
+	snap.Proposals[declared] = &Proposal{Hash: declared, Declares: []*Declare{{ProposalHash: declared, Declarer: accounts.address("A"), Decision: true}}}
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.SetBalance(accounts.address("A"), new(big.Int).Mul(big.NewInt(2e+4), big.NewInt(1e+18)))
+	statedb.SetBalance(accounts.address("B"), big.NewInt(50))
+
+	tests := []struct {
+		from  string
+		to    string
+		value int64
+		data  string
+		err   error
+	}{
+		{from: "B", to: "A", data: "ufo:2:event:vote", err: errCustomTxVersion},
+		{from: "B", to: "A", data: "ufo:1:event", err: errCustomTxMalformed},
+		{from: "B", to: "A", data: "ufo:1:event:vote", err: errVoterBalanceTooLow},
+		{from: "A", to: "B", data: "ufo:1:event:vote"},
+		{from: "A", data: "ufo:1:event:vote", err: errVoteCandidateMissing},
+		{from: "B", to: "A", data: "ufo:1:event:confirm:1", err: errSenderNotCandidate},
+		{from: "A", to: "A", data: "ufo:1:event:proposal:vlcnt:1", err: errProposalParamInvalid},
+		{from: "B", to: "B", data: "ufo:1:event:proposal:vlcnt:4", err: errProposalDepositMissing},
+		{from: "A", to: "A", data: "ufo:1:event:proposal:proposal_type:8:vlcnt:4", err: errSideChainNotExist},
+		{from: "A", to: "A", data: "ufo:1:event:proposal:proposal_type:3:mrpt:500:vlcnt:4"},
+		{from: "B", to: "B", data: "ufo:1:event:declare:hash:" + declared.Hex() + ":decision:yes", err: errSenderNotCandidate},
+		{from: "A", to: "A", data: "ufo:1:event:declare:hash:0x02:decision:maybe", err: errDeclareDecisionInvalid},
+		{from: "A", to: "A", data: "ufo:1:event:declare:hash:" + declared.Hex() + ":decision:no", err: errAlreadyDeclared},
+		{from: "A", to: "A", data: "ufo:1:event:declare:hash:0x02:decision:no"},
+		{from: "A", to: "A", data: "ufo:1:event:unknown", err: errCustomTxUnknown},
+		{from: "B", to: "B", data: "ufo:1:sc:confirm:0x01:10:1000:info:", err: errSideChainNotExist},
+		{from: "A", to: "B", value: 1, data: "ufo:1:sc:setcb:0x01", err: errSCSetCoinbaseInvalid},
+	}
+	for i, tt := range tests {
+		var tx *types.Transaction
+		if tt.to == "" {
+			tx = types.NewContractCreation(0, big.NewInt(tt.value), 100000, big.NewInt(1), []byte(tt.data))
+		} else {
+			tx = types.NewTransaction(0, accounts.address(tt.to), big.NewInt(tt.value), 100000, big.NewInt(1), []byte(tt.data))
+		}
+		err := alien.validateCustomTx(nil, snap, statedb, 2, strings.Split(tt.data, ":"), tx, accounts.address(tt.from))
+		if tt.err == nil && err != nil {
+			t.Errorf("test %d: unexpected error %v", i, err)
+		}
+		if tt.err != nil && (err == nil || !strings.HasPrefix(err.Error(), tt.err.Error())) {
+			t.Errorf("test %d: error mismatch, have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that the transaction pool of an alien chain refuses custom txs the engine
+// rejects, returning the engine error to the submitter (which is what
+// eth_sendRawTransaction reports), while plain txs are admitted untouched.
+func TestAlien_TxPoolAdmission(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B"}, nil)
+	sim.run(2)
+
+	node := sim.node("A")
+	config := core.DefaultTxPoolConfig
+	config.Journal = ""
+	pool := core.NewTxPool(config, sim.config, node.chain)
+	defer pool.Stop()
+
+	signer := types.NewEIP155Signer(sim.config.ChainId)
+	tests := []struct {
+		data string
+		err  error
+	}{
+		{"plain transfer", nil},
+		{"ufo:1:event:vote", nil},
+		{"ufo:2:event:vote", errCustomTxVersion},
+		{"ufo:1:event:unknown", errCustomTxUnknown},
+		{"ufo:1:unknown:vote", errCustomTxUnknown},
+		{"ufo:1:event:declare", errCustomTxMalformed},
+	}
+	for i, tt := range tests {
+		tx, err := types.SignTx(types.NewTransaction(uint64(i), sim.node("B").addr, big.NewInt(1), 200000, simGasPrice, []byte(tt.data)), signer, node.key)
+		if err != nil {
+			t.Fatalf("test %d: failed to sign tx: %v", i, err)
+		}
+		err = pool.AddLocal(tx)
+		switch {
+		case tt.err == nil && err != nil:
+			t.Errorf("test %d (%s): tx rejected: %v", i, tt.data, err)
+		case tt.err != nil && (err == nil || !strings.HasPrefix(err.Error(), tt.err.Error())):
+			t.Errorf("test %d (%s): admission error mismatch: have %v, want %v", i, tt.data, err, tt.err)
+		}
+	}
+}
+
+// Tests that the transaction pool hooks check the transactions against a head
+// snapshot retrieved once per head, and refreshed as the head moves.
+func TestAlien_PoolSnapshot(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(3)
+
+	node := sim.node("A")
+	head := node.chain.CurrentHeader()
+	node.engine.PrepareTxValidation(node.chain, head)
+	snap := node.engine.poolSnap
+	if snap == nil || snap.Hash != head.Hash() {
+		t.Fatalf("head snapshot not prepared")
+	}
+	statedb, _ := node.chain.State()
+	tx := types.NewTransaction(0, node.addr, big.NewInt(0), 200000, simGasPrice, []byte("ufo:1:event:vote"))
+	for i := 0; i < 3; i++ {
+		if err := node.engine.ValidateTx(node.chain, statedb, tx, node.addr); err != nil {
+			t.Fatalf("vote rejected: %v", err)
+		}
+		node.engine.IsConsensusTx(node.chain, tx, node.addr)
+		if node.engine.poolSnap != snap {
+			t.Fatalf("head snapshot retrieved again on the same head")
+		}
+	}
+	sim.run(1)
+	head = node.chain.CurrentHeader()
+	if err := node.engine.ValidateTx(node.chain, statedb, tx, node.addr); err != nil {
+		t.Fatalf("vote rejected: %v", err)
+	}
+	if node.engine.poolSnap.Hash != head.Hash() {
+		t.Errorf("head snapshot not refreshed: have %x, want %x", node.engine.poolSnap.Hash, head.Hash())
+	}
+}
+
+// Tests that only the confirmations of the current signers and of the registered
+// side chain coinbases are recognised as consensus transactions.
+func TestAlien_IsConsensusTx(t *testing.T) {
+	names := []string{"A", "B", "C"}
+	sim := newSimulator(t, names, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(4)
+
+	// Register a side chain with a coinbase for signer A only
+	scHash := common.HexToHash("0x3210000000000000000000000000000000000000000000000000000000000000")
+	proposer := sim.node("A")
+	proposal := sim.sendTx(proposer.key, proposer.addr, nil, fmt.Sprintf("ufo:1:event:proposal:proposal_type:%d:sccount:1:screward:50:schash:%s:vlcnt:%d", proposalTypeSideChainAdd, scHash.Hex(), minValidationLoopCnt))
+	sim.run(1)
+	for _, node := range sim.nodes {
+		sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:declare:hash:%s:decision:yes", proposal.Hash().Hex()))
+	}
+	sim.run(minValidationLoopCnt*len(names) + 2)
+
+	coinbase := crypto.PubkeyToAddress(simKey("sc-A").PublicKey)
+	sim.sendTx(proposer.key, coinbase, minSCSetCoinbaseValue, fmt.Sprintf("ufo:1:sc:setcb:%s", scHash.Hex()))
+	sim.run(1)
+
+	var (
+		node     = sim.head()
+		outsider = crypto.PubkeyToAddress(simKey("X").PublicKey)
+		unknown  = common.HexToHash("0x4560000000000000000000000000000000000000000000000000000000000000")
+	)
+	tests := []struct {
+		data   string
+		sender common.Address
+		want   bool
+	}{
+		{"ufo:1:event:confirm:1", sim.node("B").addr, true},
+		{"ufo:1:event:confirm:1", outsider, false},
+		{"ufo:1:event:vote", sim.node("B").addr, false},
+		{"plain transfer", sim.node("B").addr, false},
+		{fmt.Sprintf("ufo:1:sc:confirm:%s:1:1:", scHash.Hex()), coinbase, true},
+		{fmt.Sprintf("ufo:1:sc:confirm:%s:1:1:", scHash.Hex()), outsider, false},
+		{fmt.Sprintf("ufo:1:sc:confirm:%s:1:1:", unknown.Hex()), coinbase, false},
+	}
+	for i, tt := range tests {
+		tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 200000, simGasPrice, []byte(tt.data))
+		if have := node.engine.IsConsensusTx(node.chain, tx, tt.sender); have != tt.want {
+			t.Errorf("test %d (%s from %x): consensus tx mismatch: have %v, want %v", i, tt.data, tt.sender, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/event"
+	"github.com/TTCECO/gttc/log"
+)
+
+// Topics of the alien consensus event subscriptions.
+const (
+	topicNewLoop                 = "newLoop"
+	topicSignerMissed            = "signerMissed"
+	topicProposalCreated         = "proposalCreated"
+	topicProposalDecided         = "proposalDecided"
+	topicVoteChanged             = "voteChanged"
+	topicVoteExpired             = "voteExpired"
+	topicConfirmedNumberAdvanced = "confirmedNumberAdvanced"
+	topicSideChainConfirmed      = "sideChainConfirmed"
+)
+
+// maxEventBlocks is the maximum number of blocks replayed on a single head
+// change. Longer gaps, like during a sync, are skipped.
+const maxEventBlocks = 1024
+
+// errChainEventsUnsupported is returned when subscribing to consensus events on
+// a chain which does not announce its new heads.
+var errChainEventsUnsupported = errors.New("chain does not support head events")
+
+// errTooManyEventBlocks is returned when a head change is longer than the
+// blocks replayed at once.
+var errTooManyEventBlocks = fmt.Errorf("too many blocks to replay: more than %d", maxEventBlocks)
+
+// ProposalResult is the outcome of a proposal reaching its deadline or being
+// withdrawn.
+type ProposalResult struct {
+	Number   uint64         `json:"number"`          // Block number the proposal was decided at
+	Proposal *Proposal      `json:"proposal"`        // Proposal as it was when decided
+	Passed   bool           `json:"passed"`          // Whether more than 2/3 of the stake declared yes, or the thresholds were met after ProposalQuorum
+	State    string         `json:"state"`           // Passed, rejected, withdrawn or expired
+	Tally    *ProposalTally `json:"tally,omitempty"` // Stake breakdown of the decision, after ProposalQuorum
+}
+
+// All events carry the block they were emitted for. When that block leaves the
+// canonical chain in a reorg, the same event is emitted again with Removed set.
+
+// NewLoopEvent is emitted on the first block of a new loop of the signer queue.
+type NewLoopEvent struct {
+	Number        uint64           `json:"number"`
+	Hash          common.Hash      `json:"hash"`
+	LoopStartTime uint64           `json:"loopStartTime"`
+	SignerQueue   []common.Address `json:"signerQueue"`
+	Removed       bool             `json:"removed"`
+}
+
+// SignerMissedEvent is emitted when a block records signers which missed their
+// slot since the previous block.
+type SignerMissedEvent struct {
+	Number  uint64           `json:"number"`
+	Hash    common.Hash      `json:"hash"`
+	Signers []common.Address `json:"signers"`
+	Removed bool             `json:"removed"`
+}
+
+// ProposalCreatedEvent is emitted when a proposal is accepted in a block.
+type ProposalCreatedEvent struct {
+	Number   uint64      `json:"number"`
+	Hash     common.Hash `json:"hash"`
+	Proposal *Proposal   `json:"proposal"`
+	Removed  bool        `json:"removed"`
+}
+
+// ProposalDecidedEvent is emitted when a proposal reaches its deadline or is
+// withdrawn, with the effect the decision had on the consensus state.
+type ProposalDecidedEvent struct {
+	Number   uint64         `json:"number"`
+	Hash     common.Hash    `json:"hash"`
+	Proposal *Proposal      `json:"proposal"`
+	Passed   bool           `json:"passed"`
+	State    string         `json:"state"`
+	Tally    *ProposalTally `json:"tally,omitempty"`
+	Effect   string         `json:"effect"`
+	Removed  bool           `json:"removed"`
+}
+
+// VoteChangedEvent is emitted when the vote of a voter changes candidate or
+// stake, or the voter stops voting (Candidate is then the zero address).
+type VoteChangedEvent struct {
+	Number            uint64         `json:"number"`
+	Hash              common.Hash    `json:"hash"`
+	Voter             common.Address `json:"voter"`
+	Candidate         common.Address `json:"candidate"`
+	PreviousCandidate common.Address `json:"previousCandidate"`
+	Stake             *big.Int       `json:"stake"`
+	Removed           bool           `json:"removed"`
+}
+
+// VoteExpiredEvent is emitted when a vote is removed because more than an epoch
+// passed since it was cast, or its stake fell below the minimum voter balance.
+type VoteExpiredEvent struct {
+	Number    uint64         `json:"number"`
+	Hash      common.Hash    `json:"hash"`
+	Voter     common.Address `json:"voter"`
+	Candidate common.Address `json:"candidate"`
+	Stake     *big.Int       `json:"stake"`
+	Removed   bool           `json:"removed"`
+}
+
+// ConfirmedNumberAdvancedEvent is emitted when the confirmed block number of the
+// chain advances.
+type ConfirmedNumberAdvancedEvent struct {
+	Number          uint64      `json:"number"`
+	Hash            common.Hash `json:"hash"`
+	ConfirmedNumber uint64      `json:"confirmedNumber"`
+	Previous        uint64      `json:"previous"`
+	Removed         bool        `json:"removed"`
+}
+
+// SideChainConfirmedEvent is emitted when the last confirmed block of a side
+// chain advances.
+type SideChainConfirmedEvent struct {
+	Number          uint64      `json:"number"`
+	Hash            common.Hash `json:"hash"`
+	SideChain       common.Hash `json:"sideChain"`
+	ConfirmedNumber uint64      `json:"confirmedNumber"`
+	Previous        uint64      `json:"previous"`
+	Removed         bool        `json:"removed"`
+}
+
+// consensusEvent is a consensus event along with its topic.
+type consensusEvent struct {
+	topic string
+	data  interface{}
+}
+
+// eventSystem follows the canonical chain and emits the consensus events of
+// every block, found by diffing the snapshot of the block with its parent's.
+// It only runs while there are subscribers.
+type eventSystem struct {
+	alien *Alien
+	chain consensus.ChainReader
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+
+	lock    sync.Mutex // Protects the running flag
+	running bool
+}
+
+// newEventSystem creates the consensus event system of the given chain.
+func newEventSystem(alien *Alien, chain consensus.ChainReader) *eventSystem {
+	return &eventSystem{alien: alien, chain: chain}
+}
+
+// subscribe registers a channel for consensus events, starting to follow the
+// chain if needed.
+func (es *eventSystem) subscribe(ch chan<- consensusEvent) (event.Subscription, error) {
+	heads, ok := es.chain.(consensus.ChainHeadSubscriber)
+	if !ok {
+		return nil, errChainEventsUnsupported
+	}
+	es.lock.Lock()
+	defer es.lock.Unlock()
+
+	sub := es.scope.Track(es.feed.Subscribe(ch))
+	if !es.running {
+		es.running = true
+		headCh := make(chan *types.Header, 16)
+		go es.loop(headCh, heads.SubscribeChainHeaders(headCh))
+	}
+	return sub, nil
+}
+
+// loop emits the events of the new canonical blocks until the last subscriber
+// leaves.
+func (es *eventSystem) loop(heads chan *types.Header, sub event.Subscription) {
+	defer sub.Unsubscribe()
+
+	last := es.chain.CurrentHeader()
+	for {
+		select {
+		case head := <-heads:
+			// Resume from the last block whose events were sent on failure, so
+			// the rest of the range is retried on the next head without sending
+			// any event twice, unless the range is too long to be ever replayed
+			reached, err := es.advance(last, head)
+			switch err {
+			case nil:
+				last = head
+			case errTooManyEventBlocks:
+				log.Warn("Skipped alien consensus events", "from", last.Number, "number", head.Number, "hash", head.Hash(), "err", err)
+				last = head
+			default:
+				log.Warn("Failed to emit alien consensus events", "number", head.Number, "hash", head.Hash(), "err", err)
+				last = reached
+			}
+
+			es.lock.Lock()
+			if es.scope.Count() == 0 {
+				es.running = false
+				es.lock.Unlock()
+				return
+			}
+			es.lock.Unlock()
+		case <-sub.Err():
+			es.lock.Lock()
+			es.running = false
+			es.lock.Unlock()
+			return
+		}
+	}
+}
+
+// advance emits the events of the blocks reverted and applied moving the head
+// of the canonical chain from last to head. It returns the header the emitted
+// events have moved the chain to, which is last if none were emitted and head
+// if all were.
+func (es *eventSystem) advance(last, head *types.Header) (*types.Header, error) {
+	var (
+		reverted, applied []*types.Header
+		from, to          = last, head
+	)
+	for from.Number.Cmp(to.Number) > 0 {
+		reverted = append(reverted, from)
+		if len(reverted) > maxEventBlocks {
+			return last, errTooManyEventBlocks
+		}
+		if from = es.chain.GetHeader(from.ParentHash, from.Number.Uint64()-1); from == nil {
+			return last, consensus.ErrUnknownAncestor
+		}
+	}
+	for to.Number.Cmp(from.Number) > 0 {
+		applied = append(applied, to)
+		if len(applied) > maxEventBlocks {
+			return last, errTooManyEventBlocks
+		}
+		if to = es.chain.GetHeader(to.ParentHash, to.Number.Uint64()-1); to == nil {
+			return last, consensus.ErrUnknownAncestor
+		}
+	}
+	for from.Hash() != to.Hash() {
+		reverted, applied = append(reverted, from), append(applied, to)
+		if len(reverted) > maxEventBlocks || len(applied) > maxEventBlocks {
+			return last, errTooManyEventBlocks
+		}
+		from = es.chain.GetHeader(from.ParentHash, from.Number.Uint64()-1)
+		to = es.chain.GetHeader(to.ParentHash, to.Number.Uint64()-1)
+		if from == nil || to == nil {
+			return last, consensus.ErrUnknownAncestor
+		}
+	}
+	// Undo the reverted blocks from the old head down to the common ancestor,
+	// then redo the new ones, tracking the block the emitted events lead to
+	reached := last
+	for i, header := range reverted {
+		if err := es.emit(header, true); err != nil {
+			return reached, err
+		}
+		if reached = from; i+1 < len(reverted) {
+			reached = reverted[i+1]
+		}
+	}
+	for i := len(applied) - 1; i >= 0; i-- {
+		if err := es.emit(applied[i], false); err != nil {
+			return reached, err
+		}
+		reached = applied[i]
+	}
+	return reached, nil
+}
+
+// emit sends the events of a single block.
+func (es *eventSystem) emit(header *types.Header, removed bool) error {
+	events, err := es.blockEvents(header, removed)
+	if err != nil {
+		return err
+	}
+	for _, ev := range events {
+		es.feed.Send(ev)
+	}
+	return nil
+}
+
+// blockEvents applies a block on the snapshot of its parent and collects the
+// events from the difference between the two snapshots.
+func (es *eventSystem) blockEvents(header *types.Header, removed bool) ([]consensusEvent, error) {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil, nil
+	}
+	parent, err := es.alien.snapshot(es.chain, number-1, header.ParentHash, nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := parent.apply([]*types.Header{header})
+	if err != nil {
+		return nil, err
+	}
+	extra, err := DecodeHeaderExtra(es.alien.config, header)
+	if err != nil {
+		return nil, err
+	}
+	hash := header.Hash()
+
+	var events []consensusEvent
+	if snap.LoopStartTime != parent.LoopStartTime {
+		events = append(events, consensusEvent{topicNewLoop, &NewLoopEvent{
+			Number:        number,
+			Hash:          hash,
+			LoopStartTime: snap.LoopStartTime,
+			SignerQueue:   extra.SignerQueue,
+			Removed:       removed,
+		}})
+	}
+	if len(extra.SignerMissing) > 0 {
+		events = append(events, consensusEvent{topicSignerMissed, &SignerMissedEvent{
+			Number:  number,
+			Hash:    hash,
+			Signers: extra.SignerMissing,
+			Removed: removed,
+		}})
+	}
+	for _, proposal := range extra.CurrentBlockProposals {
+		if created, ok := snap.Proposals[proposal.Hash]; ok {
+			events = append(events, consensusEvent{topicProposalCreated, &ProposalCreatedEvent{
+				Number:   number,
+				Hash:     hash,
+				Proposal: created.copy(),
+				Removed:  removed,
+			}})
+		}
+	}
+	for _, result := range snap.decided {
+		events = append(events, consensusEvent{topicProposalDecided, &ProposalDecidedEvent{
+			Number:   number,
+			Hash:     hash,
+			Proposal: result.Proposal,
+			Passed:   result.Passed,
+			State:    result.State,
+			Tally:    result.Tally,
+			Effect:   proposalEffect(parent, snap, result),
+			Removed:  removed,
+		}})
+	}
+	for _, voter := range changedVoters(parent, snap) {
+		ev := &VoteChangedEvent{
+			Number:  number,
+			Hash:    hash,
+			Voter:   voter,
+			Stake:   new(big.Int),
+			Removed: removed,
+		}
+		if vote, ok := parent.Votes[voter]; ok {
+			ev.PreviousCandidate = vote.Candidate
+		}
+		if vote, ok := snap.Votes[voter]; ok {
+			ev.Candidate, ev.Stake = vote.Candidate, new(big.Int).Set(vote.Stake)
+		}
+		events = append(events, consensusEvent{topicVoteChanged, ev})
+	}
+	for _, vote := range snap.expired {
+		events = append(events, consensusEvent{topicVoteExpired, &VoteExpiredEvent{
+			Number:    number,
+			Hash:      hash,
+			Voter:     vote.Voter,
+			Candidate: vote.Candidate,
+			Stake:     new(big.Int).Set(vote.Stake),
+			Removed:   removed,
+		}})
+	}
+	if snap.ConfirmedNumber > parent.ConfirmedNumber {
+		events = append(events, consensusEvent{topicConfirmedNumberAdvanced, &ConfirmedNumberAdvancedEvent{
+			Number:          number,
+			Hash:            hash,
+			ConfirmedNumber: snap.ConfirmedNumber,
+			Previous:        parent.ConfirmedNumber,
+			Removed:         removed,
+		}})
+	}
+	var sideChains []common.Hash
+	for scHash := range snap.SCRecordMap {
+		sideChains = append(sideChains, scHash)
+	}
+	sort.Slice(sideChains, func(i, j int) bool { return bytes.Compare(sideChains[i][:], sideChains[j][:]) < 0 })
+	for _, scHash := range sideChains {
+		var previous uint64
+		if record, ok := parent.SCRecordMap[scHash]; ok {
+			previous = record.LastConfirmedNumber
+		}
+		if confirmed := snap.SCRecordMap[scHash].LastConfirmedNumber; confirmed > previous {
+			events = append(events, consensusEvent{topicSideChainConfirmed, &SideChainConfirmedEvent{
+				Number:          number,
+				Hash:            hash,
+				SideChain:       scHash,
+				ConfirmedNumber: confirmed,
+				Previous:        previous,
+				Removed:         removed,
+			}})
+		}
+	}
+	return events, nil
+}
+
+// changedVoters returns the voters whose vote differs between two snapshots,
+// sorted by address.
+func changedVoters(parent, snap *Snapshot) []common.Address {
+	var voters []common.Address
+	for voter, vote := range snap.Votes {
+		if prev, ok := parent.Votes[voter]; !ok || prev.Candidate != vote.Candidate || prev.Stake.Cmp(vote.Stake) != 0 {
+			voters = append(voters, voter)
+		}
+	}
+	for voter := range parent.Votes {
+		if _, ok := snap.Votes[voter]; !ok {
+			voters = append(voters, voter)
+		}
+	}
+	sort.Slice(voters, func(i, j int) bool { return bytes.Compare(voters[i][:], voters[j][:]) < 0 })
+	return voters
+}
+
+// proposalEffect describes the change a decided proposal made to the consensus
+// state.
+func proposalEffect(parent, snap *Snapshot, result *ProposalResult) string {
+	proposal := result.Proposal
+	refunded := "deposit refunded"
+	if proposal.ProposalType == proposalTypeRentSideChain {
+		refunded = "deposit and rent fee refunded"
+	}
+	switch result.State {
+	case proposalStateWithdrawn:
+		if proposal.ProposalType == proposalTypeRentSideChain {
+			return fmt.Sprintf("withdrawn, %d%% of deposit and the rent fee refunded", proposalWithdrawRefund)
+		}
+		return fmt.Sprintf("withdrawn, %d%% of deposit refunded", proposalWithdrawRefund)
+	case proposalStateExpired:
+		if result.Tally != nil {
+			return "expired without quorum, " + refunded
+		}
+		return "expired without declarations, " + refunded
+	}
+	if !result.Passed {
+		if result.Tally != nil && result.Tally.Vetoed {
+			return "vetoed, " + refunded
+		}
+		return "rejected, " + refunded
+	}
+	switch proposal.ProposalType {
+	case proposalTypeCandidateAdd:
+		if _, ok := snap.Candidates[proposal.TargetAddress]; ok {
+			return fmt.Sprintf("candidate %s added", proposal.TargetAddress.Hex())
+		}
+	case proposalTypeCandidateRemove:
+		if _, ok := parent.Candidates[proposal.TargetAddress]; ok {
+			if _, ok := snap.Candidates[proposal.TargetAddress]; !ok {
+				return fmt.Sprintf("candidate %s removed", proposal.TargetAddress.Hex())
+			}
+		}
+	case proposalTypeMinerRewardDistributionModify:
+		return fmt.Sprintf("miner reward changed from %d to %d per thousand", parent.MinerReward, snap.MinerReward)
+	case proposalTypeSideChainAdd:
+		return fmt.Sprintf("side chain %s added", proposal.SCHash.Hex())
+	case proposalTypeSideChainRemove:
+		if _, ok := parent.SCRecordMap[proposal.SCHash]; ok {
+			return fmt.Sprintf("side chain %s removed", proposal.SCHash.Hex())
+		}
+	case proposalTypeMinVoterBalanceModify:
+		return fmt.Sprintf("min voter balance changed from %v to %v", parent.MinVB, snap.MinVB)
+	case proposalTypeRentSideChain:
+		if _, ok := snap.SCRecordMap[proposal.SCHash]; ok {
+			return fmt.Sprintf("side chain %s rented for %d blocks", proposal.SCHash.Hex(), proposal.SCRentLength)
+		}
+		return fmt.Sprintf("side chain %s gone, rent fee refunded", proposal.SCHash.Hex())
+	}
+	return "passed without effect"
+}
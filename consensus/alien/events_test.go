@@ -0,0 +1,343 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/event"
+	"github.com/TTCECO/gttc/params"
+	"github.com/TTCECO/gttc/rpc"
+)
+
+// collectEvents replays the head change of a node from one header to another
+// and returns the emitted events by topic.
+func collectEvents(t *testing.T, node *simNode, from, to *types.Header) map[string][]interface{} {
+	es := newEventSystem(node.engine, node.chain)
+	ch := make(chan consensusEvent, 4096)
+	sub := es.feed.Subscribe(ch)
+	defer sub.Unsubscribe()
+
+	if _, err := es.advance(from, to); err != nil {
+		t.Fatalf("node %s: failed to replay events: %v", node.name, err)
+	}
+	events := make(map[string][]interface{})
+	for {
+		select {
+		case ev := <-ch:
+			events[ev.topic] = append(events[ev.topic], ev.data)
+		default:
+			return events
+		}
+	}
+}
+
+// Tests that the consensus events of a proposal lifecycle and of vote changes
+// are emitted on the canonical blocks.
+func TestEvents_Lifecycle(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E"}
+	sim := newSimulator(t, names, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(6)
+	from := sim.head().chain.CurrentHeader()
+
+	proposer, voter := sim.node("A"), sim.node("E")
+	proposal := sim.sendTx(proposer.key, proposer.addr, nil, fmt.Sprintf("ufo:1:event:proposal:proposal_type:%d:mrpt:500:vlcnt:%d", proposalTypeMinerRewardDistributionModify, minValidationLoopCnt))
+	sim.run(1)
+	for _, node := range sim.nodes {
+		sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:declare:hash:%s:decision:yes", proposal.Hash().Hex()))
+	}
+	sim.sendTx(voter.key, proposer.addr, nil, "ufo:1:event:vote")
+	sim.run(minValidationLoopCnt*len(names) + 2)
+	to := sim.head().chain.CurrentHeader()
+
+	events := collectEvents(t, sim.head(), from, to)
+
+	// A loop of 5 slots starts every 5 blocks
+	if have, want := len(events[topicNewLoop]), int(to.Number.Uint64()-from.Number.Uint64())/len(names); have < want {
+		t.Errorf("new loop count mismatch: have %d, want at least %d", have, want)
+	}
+	for _, data := range events[topicNewLoop] {
+		if ev := data.(*NewLoopEvent); len(ev.SignerQueue) != len(names) || ev.Removed {
+			t.Errorf("block %d: invalid new loop event: %+v", ev.Number, ev)
+		}
+	}
+	if created := events[topicProposalCreated]; len(created) != 1 || created[0].(*ProposalCreatedEvent).Proposal.Hash != proposal.Hash() {
+		t.Errorf("proposal created events mismatch: have %v", created)
+	}
+	decided := events[topicProposalDecided]
+	if len(decided) != 1 {
+		t.Fatalf("proposal decided count mismatch: have %d, want 1", len(decided))
+	}
+	if ev := decided[0].(*ProposalDecidedEvent); !ev.Passed || ev.Proposal.Hash != proposal.Hash() || !strings.HasSuffix(ev.Effect, "to 500 per thousand") {
+		t.Errorf("proposal decided event mismatch: have %+v", ev)
+	}
+	var voted bool
+	for _, data := range events[topicVoteChanged] {
+		if ev := data.(*VoteChangedEvent); ev.Voter == voter.addr && ev.Candidate == proposer.addr && ev.PreviousCandidate == voter.addr {
+			voted = true
+		}
+	}
+	if !voted {
+		t.Errorf("vote change of %s not emitted", voter.name)
+	}
+	// The confirmed number advances in steps covering the whole range
+	confirmed := sim.snapshot(sim.head(), from).ConfirmedNumber
+	for _, data := range events[topicConfirmedNumberAdvanced] {
+		ev := data.(*ConfirmedNumberAdvancedEvent)
+		if ev.Previous != confirmed || ev.ConfirmedNumber <= confirmed {
+			t.Errorf("block %d: confirmed number advanced from %d to %d, want from %d", ev.Number, ev.Previous, ev.ConfirmedNumber, confirmed)
+		}
+		confirmed = ev.ConfirmedNumber
+	}
+	if want := sim.snapshot(sim.head(), to).ConfirmedNumber; confirmed != want {
+		t.Errorf("confirmed number mismatch: have %d, want %d", confirmed, want)
+	}
+	// Nothing happened between the head and itself
+	if events := collectEvents(t, sim.head(), to, to); len(events) != 0 {
+		t.Errorf("events emitted without head change: %v", events)
+	}
+}
+
+// Tests that a reorg emits the events of the reverted blocks as removed, and
+// then the events of the new canonical blocks.
+func TestEvents_Reorg(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C", "D", "E"}, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(12)
+	sim.partition([]string{"A", "B", "C"}, []string{"D", "E"})
+	sim.run(15)
+
+	minority := sim.node("D")
+	from := minority.chain.CurrentHeader()
+	sim.heal()
+	to := minority.chain.CurrentHeader()
+
+	events := collectEvents(t, minority, from, to)
+
+	var removed, added int
+	for _, data := range events[topicNewLoop] {
+		ev := data.(*NewLoopEvent)
+		canonical := minority.chain.GetHeaderByNumber(ev.Number)
+		switch {
+		case ev.Removed:
+			removed++
+			if canonical != nil && canonical.Hash() == ev.Hash {
+				t.Errorf("block %d: removed event for canonical block", ev.Number)
+			}
+			if added > 0 {
+				t.Errorf("block %d: removed event after added ones", ev.Number)
+			}
+		default:
+			added++
+			if canonical == nil || canonical.Hash() != ev.Hash {
+				t.Errorf("block %d: added event for non canonical block", ev.Number)
+			}
+		}
+	}
+	if removed == 0 || added == 0 {
+		t.Errorf("reorg events mismatch: removed %d, added %d", removed, added)
+	}
+	// Missed slots of the minority are removed as well
+	var missedRemoved bool
+	for _, data := range events[topicSignerMissed] {
+		if data.(*SignerMissedEvent).Removed {
+			missedRemoved = true
+		}
+	}
+	if !missedRemoved {
+		t.Errorf("signer missed events of the minority not removed")
+	}
+}
+
+// Tests that the blocks of a head change failing to replay are retried on the
+// next head.
+func TestEvents_RetryFailedHead(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(3)
+
+	node := sim.node("A")
+	from := node.chain.CurrentHeader()
+
+	es := newEventSystem(node.engine, node.chain)
+	ch := make(chan consensusEvent, 4096)
+	sub := es.scope.Track(es.feed.Subscribe(ch))
+	defer sub.Unsubscribe()
+
+	var feed event.Feed
+	heads := make(chan *types.Header)
+	headSub := feed.Subscribe(make(chan *types.Header))
+	defer headSub.Unsubscribe()
+	go es.loop(heads, headSub)
+	heads <- from // wait for the loop to start from the current head
+
+	sim.run(6)
+	head := node.chain.CurrentHeader()
+	want := 0
+	for _, events := range collectEvents(t, node, from, head) {
+		want += len(events)
+	}
+	// Announce a head with an unknown ancestor before the real one
+	heads <- &types.Header{ParentHash: common.Hash{0x01}, Number: new(big.Int).Add(head.Number, common.Big1)}
+	heads <- head
+
+	for have := 0; have < want; have++ {
+		select {
+		case <-ch:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("events missing after a failed head: have %d, want %d", have, want)
+		}
+	}
+}
+
+// faultyChain is a chain returning a header with a broken extra-data for one
+// hash, making the replay of that block fail.
+type faultyChain struct {
+	*core.BlockChain
+	fault common.Hash
+}
+
+func (c *faultyChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header := c.BlockChain.GetHeader(hash, number)
+	if header != nil && hash == c.fault {
+		header = types.CopyHeader(header)
+		header.Extra = nil
+	}
+	return header
+}
+
+// Tests that a head change failing partway is resumed after the last block
+// whose events were sent, without sending any event twice.
+func TestEvents_ResumeFailedHead(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(3)
+
+	node := sim.node("A")
+	from := node.chain.CurrentHeader()
+	sim.run(6)
+	head := node.chain.CurrentHeader()
+
+	want := 0
+	for _, events := range collectEvents(t, node, from, head) {
+		want += len(events)
+	}
+	fault := node.chain.GetHeaderByNumber(from.Number.Uint64() + 4)
+	chain := &faultyChain{BlockChain: node.chain, fault: fault.Hash()}
+
+	es := newEventSystem(node.engine, chain)
+	ch := make(chan consensusEvent, 4096)
+	sub := es.feed.Subscribe(ch)
+	defer sub.Unsubscribe()
+
+	reached, err := es.advance(from, head)
+	if err == nil {
+		t.Fatalf("replay succeeded across a broken block")
+	}
+	if reached.Hash() != fault.ParentHash {
+		t.Fatalf("reached block mismatch: have %d, want %d", reached.Number, fault.Number.Uint64()-1)
+	}
+	if len(ch) == 0 {
+		t.Fatalf("no events sent before the broken block")
+	}
+	chain.fault = common.Hash{}
+	if reached, err = es.advance(reached, head); err != nil {
+		t.Fatalf("failed to resume replay: %v", err)
+	}
+	if reached.Hash() != head.Hash() {
+		t.Errorf("reached block mismatch: have %d, want %d", reached.Number, head.Number)
+	}
+	if have := len(ch); have != want {
+		t.Errorf("event count mismatch: have %d, want %d", have, want)
+	}
+}
+
+// Tests that the events are delivered over RPC subscriptions as new blocks are
+// imported.
+func TestEvents_Subscribe(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(3)
+
+	node := sim.node("A")
+	server := rpc.NewServer()
+	for _, api := range node.engine.APIs(node.chain) {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	loops := make(chan *NewLoopEvent, 16)
+	sub, err := client.Subscribe(context.Background(), "alien", loops, topicNewLoop)
+	if err != nil {
+		t.Fatalf("failed to subscribe to new loops: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	votes := make(chan *VoteChangedEvent, 16)
+	voter := sim.node("C")
+	voteSub, err := client.Subscribe(context.Background(), "alien", votes, topicVoteChanged, node.addr)
+	if err != nil {
+		t.Fatalf("failed to subscribe to vote changes: %v", err)
+	}
+	defer voteSub.Unsubscribe()
+
+	sim.sendTx(voter.key, node.addr, nil, "ufo:1:event:vote")
+	sim.run(6)
+
+	select {
+	case ev := <-loops:
+		if len(ev.SignerQueue) != 3 {
+			t.Errorf("signer queue length mismatch: have %d, want 3", len(ev.SignerQueue))
+		}
+	case err := <-sub.Err():
+		t.Fatalf("new loop subscription failed: %v", err)
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no new loop delivered")
+	}
+	for {
+		select {
+		case ev := <-votes:
+			if ev.Voter != node.addr && ev.Candidate != node.addr && ev.PreviousCandidate != node.addr {
+				t.Fatalf("vote change of unrelated address delivered: %+v", ev)
+			}
+			if ev.Voter == voter.addr && ev.Candidate == node.addr {
+				return
+			}
+		case err := <-voteSub.Err():
+			t.Fatalf("vote subscription failed: %v", err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("vote change not delivered")
+		}
+	}
+}
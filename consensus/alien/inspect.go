@@ -0,0 +1,178 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/params"
+)
+
+// InspectedHeader is the consensus content decoded from the extra data of a
+// header, along with the findings of auditing it against its parent snapshot.
+type InspectedHeader struct {
+	Number        uint64           `json:"number"`
+	Hash          common.Hash      `json:"hash"`
+	Time          uint64           `json:"time"`
+	Coinbase      common.Address   `json:"coinbase"`
+	Signer        common.Address   `json:"signer"`
+	InturnSigner  common.Address   `json:"inturnSigner"`
+	InTurn        bool             `json:"inturn"`
+	Backup        bool             `json:"backup,omitempty"` // Sealed by the backup of the in-turn signer
+	Extra         *HeaderExtra     `json:"extra"`
+	SignerMissing []common.Address `json:"expectedSignerMissing"`
+	Mismatches    []string         `json:"mismatches,omitempty"`
+}
+
+// Snapshot retrieves the voting snapshot after the given header.
+func (a *Alien) Snapshot(chain consensus.ChainReader, header *types.Header) (*Snapshot, error) {
+	return a.snapshot(chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+}
+
+// DecodeHeaderExtra decodes the consensus content of the extra data of a header.
+func DecodeHeaderExtra(config *params.AlienConfig, header *types.Header) (*HeaderExtra, error) {
+	if len(header.Extra) < extraVanity+extraSeal {
+		return nil, errMissingSignature
+	}
+	headerExtra := new(HeaderExtra)
+	if err := decodeHeaderExtra(config, header.Number, header.Extra[extraVanity:len(header.Extra)-extraSeal], headerExtra); err != nil {
+		return nil, err
+	}
+	return headerExtra, nil
+}
+
+// InspectHeader decodes the extra data of the given header and audits it against
+// the snapshot of its parent: the recovered signer must be the coinbase and in
+// turn, and the recorded missing signers must match the recomputed ones.
+func (a *Alien) InspectHeader(chain consensus.ChainReader, header *types.Header) (*InspectedHeader, error) {
+	number := header.Number.Uint64()
+	inspected := &InspectedHeader{
+		Number:   number,
+		Hash:     header.Hash(),
+		Time:     header.Time.Uint64(),
+		Coinbase: header.Coinbase,
+	}
+	// The genesis header carries no consensus content
+	if number == 0 {
+		return inspected, nil
+	}
+	extra, err := DecodeHeaderExtra(a.config, header)
+	if err != nil {
+		return nil, err
+	}
+	inspected.Extra = extra
+
+	if inspected.Signer, err = ecrecover(header, a.signatures); err != nil {
+		return nil, err
+	}
+	if inspected.Signer != header.Coinbase {
+		inspected.Mismatches = append(inspected.Mismatches, fmt.Sprintf("signer %s is not the coinbase", inspected.Signer.Hex()))
+	}
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	snap, err := a.snapshot(chain, number-1, header.ParentHash, nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	if !a.config.SideChain {
+		inspected.InturnSigner, _ = snap.slotSigner(inspected.Time)
+		inspected.InTurn = snap.inturn(inspected.Signer, inspected.Time)
+		if inspected.Backup = a.isBackupSeal(header); inspected.Backup {
+			if err := a.verifyBackupSeal(snap, inspected.Signer, parent, header); err != nil {
+				inspected.Mismatches = append(inspected.Mismatches, fmt.Sprintf("invalid backup seal: %v", err))
+			}
+		} else if !inspected.InTurn {
+			inspected.Mismatches = append(inspected.Mismatches, "signer is not in turn")
+		}
+	}
+	if number > 1 {
+		if inspected.SignerMissing, err = a.expectedSignerMissing(chain, header, parent); err != nil {
+			return nil, err
+		}
+		if !equalAddresses(inspected.SignerMissing, extra.SignerMissing) {
+			inspected.Mismatches = append(inspected.Mismatches, fmt.Sprintf("signer missing mismatch: have %d, want %d", len(extra.SignerMissing), len(inspected.SignerMissing)))
+		}
+	}
+	return inspected, nil
+}
+
+// expectedSignerMissing recomputes the signers which missed their slot between
+// the parent and the given header, the same way Finalize records them.
+func (a *Alien) expectedSignerMissing(chain consensus.ChainReader, header *types.Header, parent *types.Header) ([]common.Address, error) {
+	number := header.Number.Uint64()
+	parentHeaderExtra, err := DecodeHeaderExtra(a.config, parent)
+	if err != nil {
+		return nil, err
+	}
+	if !a.config.IsTrantor(header.Number) {
+		return getSignerMissing(parent.Coinbase, header.Coinbase, *parentHeaderExtra, number%a.config.MaxSignerCount == 0), nil
+	}
+	var grandParentHeaderExtra HeaderExtra
+	if number%a.config.MaxSignerCount == 1 {
+		grandParent := chain.GetHeader(parent.ParentHash, number-2)
+		if grandParent == nil {
+			return nil, errLastLoopHeaderFail
+		}
+		extra, err := DecodeHeaderExtra(a.config, grandParent)
+		if err != nil {
+			return nil, err
+		}
+		grandParentHeaderExtra = *extra
+	}
+	return a.backupSignerMissing(parent, header, parentHeaderExtra, &grandParentHeaderExtra), nil
+}
+
+// CompareHeaderExtra lists the fields of the extra data of the given header
+// which differ from the extra data Finalize produced for the same block.
+func (a *Alien) CompareHeaderExtra(header *types.Header, finalized *types.Header) ([]string, error) {
+	have, err := DecodeHeaderExtra(a.config, header)
+	if err != nil {
+		return nil, err
+	}
+	want, err := DecodeHeaderExtra(a.config, finalized)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []string
+	haveValue, wantValue := reflect.ValueOf(*have), reflect.ValueOf(*want)
+	for i := 0; i < haveValue.NumField(); i++ {
+		if !reflect.DeepEqual(haveValue.Field(i).Interface(), wantValue.Field(i).Interface()) {
+			diffs = append(diffs, fmt.Sprintf("%s differs from finalized extra", haveValue.Type().Field(i).Name))
+		}
+	}
+	return diffs, nil
+}
+
+// equalAddresses reports whether two address lists hold the same addresses in
+// the same order.
+func equalAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+)
+
+// Tests that inspecting a generated chain reports the decoded extra without any
+// mismatch, and that tampering with the missing signers gets flagged.
+func TestAlien_InspectHeader(t *testing.T) {
+	config, genesis, generated := newTesterHeaders(t, 3, 20)
+	engine, _ := newTesterVerifier(config, genesis, generated)
+
+	for _, header := range generated.headers[1:] {
+		inspected, err := engine.InspectHeader(generated, header)
+		if err != nil {
+			t.Fatalf("header %d: failed to inspect: %v", header.Number, err)
+		}
+		if inspected.Signer != header.Coinbase || inspected.InturnSigner != header.Coinbase || !inspected.InTurn {
+			t.Errorf("header %d: signer %x, in turn %v", header.Number, inspected.Signer, inspected.InTurn)
+		}
+		if len(inspected.Mismatches) > 0 {
+			t.Errorf("header %d: unexpected mismatches: %v", header.Number, inspected.Mismatches)
+		}
+		if inspected.Extra == nil || len(inspected.Extra.SignerQueue) != 3 {
+			t.Errorf("header %d: signer queue not decoded", header.Number)
+		}
+	}
+	// Record the coinbase as missing its own slot, keeping the stale seal
+	header := types.CopyHeader(generated.headers[10])
+	extra, err := DecodeHeaderExtra(config.Alien, header)
+	if err != nil {
+		t.Fatalf("failed to decode header extra: %v", err)
+	}
+	extra.SignerMissing = []common.Address{header.Coinbase}
+	enc, err := encodeHeaderExtra(config.Alien, header.Number, *extra)
+	if err != nil {
+		t.Fatalf("failed to encode header extra: %v", err)
+	}
+	header.Extra = append(append(append([]byte{}, header.Extra[:extraVanity]...), enc...), header.Extra[len(header.Extra)-extraSeal:]...)
+
+	inspected, err := engine.InspectHeader(generated, header)
+	if err != nil {
+		t.Fatalf("failed to inspect tampered header: %v", err)
+	}
+	if !strings.Contains(strings.Join(inspected.Mismatches, "\n"), "signer missing mismatch") {
+		t.Errorf("tampered signer missing not flagged: %v", inspected.Mismatches)
+	}
+	diffs, err := engine.CompareHeaderExtra(header, generated.headers[10])
+	if err != nil {
+		t.Fatalf("failed to compare header extra: %v", err)
+	}
+	if len(diffs) != 1 || !strings.HasPrefix(diffs[0], "SignerMissing") {
+		t.Errorf("extra diff mismatch: have %v, want SignerMissing", diffs)
+	}
+}
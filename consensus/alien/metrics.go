@@ -0,0 +1,121 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/metrics"
+)
+
+// Metrics collected by the alien engine. Per signer metrics are registered on
+// demand under the given prefix suffixed with the signer address, e.g.
+// alien/punish/missed/0x6f2c....
+var (
+	// alien/seal/sealed counts the blocks sealed locally, alien/seal/backup those
+	// sealed as the backup of an absent in-turn signer, alien/seal/outofturn the
+	// sealing attempts aborted because the local signer was not in turn.
+	sealedMeter       = metrics.NewRegisteredMeter("alien/seal/sealed", nil)
+	backupSealedMeter = metrics.NewRegisteredMeter("alien/seal/backup", nil)
+	outOfTurnMeter    = metrics.NewRegisteredMeter("alien/seal/outofturn", nil)
+
+	// alien/seal/delay measures how late a block was signed after the start of
+	// its in-turn slot.
+	sealDelayTimer = metrics.NewRegisteredTimer("alien/seal/delay", nil)
+
+	// alien/verify/seal measures verifySeal, alien/verify/invalid counts the
+	// seals rejected by it.
+	verifySealTimer    = metrics.NewRegisteredTimer("alien/verify/seal", nil)
+	invalidSealedMeter = metrics.NewRegisteredMeter("alien/verify/invalid", nil)
+
+	// alien/snapshot/hit, alien/snapshot/disk and alien/snapshot/miss count the
+	// snapshot lookups served from the in-memory cache, loaded from disk, or
+	// rebuilt from an older snapshot. alien/snapshot/apply measures the rebuilds.
+	snapshotHitMeter   = metrics.NewRegisteredMeter("alien/snapshot/hit", nil)
+	snapshotDiskMeter  = metrics.NewRegisteredMeter("alien/snapshot/disk", nil)
+	snapshotMissMeter  = metrics.NewRegisteredMeter("alien/snapshot/miss", nil)
+	snapshotApplyTimer = metrics.NewRegisteredTimer("alien/snapshot/apply", nil)
+
+	// alien/confirm/lag is the number of blocks between the latest snapshot and
+	// its confirmed block.
+	confirmLagGauge = metrics.NewRegisteredGauge("alien/confirm/lag", nil)
+
+	// alien/sidechain/confirm/sent and alien/sidechain/confirm/fail count the
+	// side chain confirmation transactions sent to or failed to reach the main
+	// chain.
+	scConfirmSentMeter = metrics.NewRegisteredMeter("alien/sidechain/confirm/sent", nil)
+	scConfirmFailMeter = metrics.NewRegisteredMeter("alien/sidechain/confirm/fail", nil)
+
+	// alien/clock/offset and alien/clock/ntp are the offsets of the local clock
+	// in milliseconds against the peer blocks and NTP, alien/clock/skipped counts
+	// the slots skipped because of them.
+	clockOffsetGauge = metrics.NewRegisteredGauge("alien/clock/offset", nil)
+	clockNTPGauge    = metrics.NewRegisteredGauge("alien/clock/ntp", nil)
+	clockSkipMeter   = metrics.NewRegisteredMeter("alien/clock/skipped", nil)
+
+	// alien/punish/missed counts the missed slots of all signers.
+	missedSlotMeter = metrics.NewRegisteredMeter("alien/punish/missed", nil)
+)
+
+// Prefixes of the per signer metrics: alien/punish/missed/<signer> counts the
+// missed slots of a signer, alien/punish/credit/<signer> is its current punish
+// score.
+const (
+	signerMissedPrefix = "alien/punish/missed/"
+	signerCreditPrefix = "alien/punish/credit/"
+)
+
+// punishMetrics reports the punish metrics of the snapshots of an engine once
+// per block number, as snapshots are applied again when rebuilt or on other
+// forks.
+type punishMetrics struct {
+	number uint64 // Highest block number whose punishment has been reported
+}
+
+// report updates the punish metrics after the given header was applied to a
+// snapshot. The signers are the ones whose punish score may have changed.
+func (m *punishMetrics) report(number *big.Int, signerMissing []common.Address, punished map[common.Address]uint64, signers []common.Address) {
+	for {
+		reported := atomic.LoadUint64(&m.number)
+		if number.Uint64() <= reported {
+			return
+		}
+		if atomic.CompareAndSwapUint64(&m.number, reported, number.Uint64()) {
+			break
+		}
+	}
+	missedSlotMeter.Mark(int64(len(signerMissing)))
+	for _, signer := range signerMissing {
+		metrics.GetOrRegisterMeter(signerMissedPrefix+signer.Hex(), nil).Mark(1)
+	}
+	for _, signer := range signers {
+		metrics.GetOrRegisterGauge(signerCreditPrefix+signer.Hex(), nil).Update(int64(punished[signer]))
+	}
+}
+
+// reportSealDelay updates the sealing delay metric with the time elapsed since
+// the given header timestamp.
+func reportSealDelay(headerTime *big.Int) {
+	if delay := time.Since(time.Unix(headerTime.Int64(), 0)); delay > 0 {
+		sealDelayTimer.Update(delay)
+	} else {
+		sealDelayTimer.Update(0)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/metrics"
+)
+
+// Tests that the per signer punish metrics are reported once per block number,
+// even if the block is applied to snapshots several times.
+func TestAlien_ReportPunish(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	missing, signer := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	punished := map[common.Address]uint64{missing: missingPublishCredit}
+	number := big.NewInt(1)
+
+	var punish punishMetrics
+	for i := 0; i < 2; i++ {
+		punish.report(number, []common.Address{missing}, punished, []common.Address{missing, signer})
+	}
+	if meter, ok := metrics.DefaultRegistry.Get(signerMissedPrefix + missing.Hex()).(metrics.Meter); !ok || meter.Count() != 1 {
+		t.Errorf("missed slot meter not reported once: %v", meter)
+	}
+	if gauge, ok := metrics.DefaultRegistry.Get(signerCreditPrefix + missing.Hex()).(metrics.Gauge); !ok || gauge.Value() != int64(missingPublishCredit) {
+		t.Errorf("punish credit gauge mismatch: %v", gauge)
+	}
+	if gauge, ok := metrics.DefaultRegistry.Get(signerCreditPrefix + signer.Hex()).(metrics.Gauge); !ok || gauge.Value() != 0 {
+		t.Errorf("cleared punish credit gauge mismatch: %v", gauge)
+	}
+}
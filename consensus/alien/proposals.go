@@ -0,0 +1,277 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/params"
+)
+
+// Lifecycle states of a proposal. A proposal is open until it is withdrawn by
+// its proposer or reaches its deadline, where it passes, is rejected or, if no
+// candidate declared on it at all (not enough stake after ProposalQuorum),
+// expires.
+const (
+	proposalStateOpen      = "open"
+	proposalStatePassed    = "passed"
+	proposalStateRejected  = "rejected"
+	proposalStateWithdrawn = "withdrawn"
+	proposalStateExpired   = "expired"
+)
+
+const (
+	proposalWithdrawRefund = 50  // Percentage of the deposit refunded to a proposer withdrawing its proposal
+	maxProposalHistory     = 128 // Number of decided proposals kept in the snapshot
+)
+
+// defaultProposalThreshold decides the proposals of the types without threshold
+// in the config after ProposalQuorum.
+var defaultProposalThreshold = params.AlienProposalThreshold{
+	Quorum:   500,
+	Approval: 667,
+	Veto:     334,
+}
+
+// errUnknownProposal is returned if a proposal is neither open nor in the history.
+var errUnknownProposal = errors.New("unknown proposal")
+
+// ProposalInfo is a proposal along with its lifecycle state.
+type ProposalInfo struct {
+	Proposal *Proposal      `json:"proposal"`
+	State    string         `json:"state"`
+	Deadline uint64         `json:"deadline"`          // Block the proposal is decided at, unless withdrawn before
+	Decided  uint64         `json:"decided,omitempty"` // Block the proposal left the open state at
+	Tally    *ProposalTally `json:"tally,omitempty"`   // Stake breakdown, as of now for the open proposals
+}
+
+// ProposalTally is the stake breakdown behind the decision on a proposal. The
+// stake of a declarer is the tally of the candidate.
+type ProposalTally struct {
+	Total     *big.Int                      `json:"total"` // Stake of all candidates
+	Yes       *big.Int                      `json:"yes"`
+	No        *big.Int                      `json:"no"`
+	Abstain   *big.Int                      `json:"abstain"`
+	Threshold params.AlienProposalThreshold `json:"threshold"` // Rule of the proposal type
+	Quorum    bool                          `json:"quorum"`    // Whether enough stake declared
+	Approved  bool                          `json:"approved"`  // Whether enough of the yes and no stake is yes
+	Vetoed    bool                          `json:"vetoed"`    // Whether too much of the declared stake is no
+}
+
+// State returns the state the proposal ends up in at its deadline.
+func (t *ProposalTally) State() string {
+	switch {
+	case !t.Quorum:
+		return proposalStateExpired
+	case t.Vetoed || !t.Approved:
+		return proposalStateRejected
+	default:
+		return proposalStatePassed
+	}
+}
+
+// Proposal returns the proposal with the given hash as of the header, either
+// open or among the recently decided ones.
+func (a *Alien) Proposal(chain consensus.ChainReader, header *types.Header, hash common.Hash) (*ProposalInfo, error) {
+	snap, err := a.snapshot(chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	info := snap.proposalInfo(hash)
+	if info == nil {
+		return nil, errUnknownProposal
+	}
+	return info, nil
+}
+
+// ProposalHistory returns the recently decided proposals as of the header,
+// oldest first.
+func (a *Alien) ProposalHistory(chain consensus.ChainReader, header *types.Header) ([]*ProposalResult, error) {
+	snap, err := a.snapshot(chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	return snap.ProposalHistory, nil
+}
+
+// DecidedProposal returns the result of the recently decided proposal with the
+// given hash as of the header, with its stake breakdown after ProposalQuorum.
+func (a *Alien) DecidedProposal(chain consensus.ChainReader, header *types.Header, hash common.Hash) (*ProposalResult, error) {
+	snap, err := a.snapshot(chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(snap.ProposalHistory) - 1; i >= 0; i-- {
+		if result := snap.ProposalHistory[i]; result.Proposal.Hash == hash {
+			return result, nil
+		}
+	}
+	return nil, errUnknownProposal
+}
+
+// proposalInfo looks the proposal with the given hash up in the open proposals
+// and then in the history, the latest decision first.
+func (s *Snapshot) proposalInfo(hash common.Hash) *ProposalInfo {
+	if proposal, ok := s.Proposals[hash]; ok {
+		info := &ProposalInfo{
+			Proposal: proposal.copy(),
+			State:    proposalStateOpen,
+			Deadline: s.proposalDeadline(proposal),
+		}
+		if s.config.IsProposalQuorum(new(big.Int).SetUint64(s.Number)) {
+			info.Tally = s.tallyProposal(proposal)
+		}
+		return info
+	}
+	for i := len(s.ProposalHistory) - 1; i >= 0; i-- {
+		if result := s.ProposalHistory[i]; result.Proposal.Hash == hash {
+			return &ProposalInfo{
+				Proposal: result.Proposal.copy(),
+				State:    result.State,
+				Deadline: s.proposalDeadline(result.Proposal),
+				Decided:  result.Number,
+				Tally:    result.Tally,
+			}
+		}
+	}
+	return nil
+}
+
+// proposalDeadline returns the block the result of the proposal is calculated at.
+func (s *Snapshot) proposalDeadline(proposal *Proposal) uint64 {
+	return proposal.ReceivedNumber.Uint64() + proposal.ValidationLoopCnt*s.config.MaxSignerCount + 1
+}
+
+// proposalThreshold returns the rule deciding the proposals of the given type.
+func (s *Snapshot) proposalThreshold(proposalType uint64) params.AlienProposalThreshold {
+	if threshold, ok := s.config.ProposalThresholds[proposalType]; ok && threshold != nil {
+		return *threshold
+	}
+	if threshold, ok := s.config.ProposalThresholds[0]; ok && threshold != nil {
+		return *threshold
+	}
+	return defaultProposalThreshold
+}
+
+// tallyProposal sums up the stake declared on the proposal and checks it against
+// the threshold of the proposal type.
+func (s *Snapshot) tallyProposal(proposal *Proposal) *ProposalTally {
+	tally := &ProposalTally{
+		Total:     new(big.Int),
+		Yes:       new(big.Int),
+		No:        new(big.Int),
+		Abstain:   new(big.Int),
+		Threshold: s.proposalThreshold(proposal.ProposalType),
+	}
+	for _, stake := range s.Tally {
+		tally.Total.Add(tally.Total, stake)
+	}
+	for _, declare := range proposal.Declares {
+		stake, ok := s.Tally[declare.Declarer]
+		if !ok {
+			continue
+		}
+		switch {
+		case declare.Abstain:
+			tally.Abstain.Add(tally.Abstain, stake)
+		case declare.Decision:
+			tally.Yes.Add(tally.Yes, stake)
+		default:
+			tally.No.Add(tally.No, stake)
+		}
+	}
+	var (
+		perThousand = big.NewInt(1000)
+		declared    = new(big.Int).Add(tally.Yes, tally.No)
+		decided     = new(big.Int).Set(declared)
+	)
+	declared.Add(declared, tally.Abstain)
+
+	// declared / total >= quorum, yes / (yes + no) > approval and no / declared > veto
+	tally.Quorum = new(big.Int).Mul(declared, perThousand).Cmp(new(big.Int).Mul(tally.Total, new(big.Int).SetUint64(tally.Threshold.Quorum))) >= 0
+	tally.Approved = new(big.Int).Mul(tally.Yes, perThousand).Cmp(new(big.Int).Mul(decided, new(big.Int).SetUint64(tally.Threshold.Approval))) > 0
+	tally.Vetoed = tally.Threshold.Veto > 0 && new(big.Int).Mul(tally.No, perThousand).Cmp(new(big.Int).Mul(declared, new(big.Int).SetUint64(tally.Threshold.Veto))) > 0
+	return tally
+}
+
+// checkWithdraw returns whether the proposal of the withdraw is open and was
+// made by the withdrawing account.
+func (s *Snapshot) checkWithdraw(withdraw ProposalWithdraw) error {
+	if s == nil {
+		return errProposalNotOpen
+	}
+	proposal, ok := s.Proposals[withdraw.ProposalHash]
+	if !ok {
+		return errProposalNotOpen
+	}
+	if proposal.Proposer != withdraw.Proposer {
+		return errWithdrawNotProposer
+	}
+	return nil
+}
+
+// updateSnapshotByWithdraws closes the withdrawn proposals, refunding part of
+// the deposit and the whole side chain rent fee to their proposers. The rest of
+// the deposit is kept as the price of the withdrawal.
+func (s *Snapshot) updateSnapshotByWithdraws(withdraws []ProposalWithdraw, headerNumber *big.Int) {
+	for _, withdraw := range withdraws {
+		if s.checkWithdraw(withdraw) != nil {
+			continue
+		}
+		proposal := s.Proposals[withdraw.ProposalHash]
+
+		refund := new(big.Int).Mul(proposal.CurrentDeposit, big.NewInt(proposalWithdrawRefund))
+		refund.Div(refund, big.NewInt(100))
+		if proposal.ProposalType == proposalTypeRentSideChain {
+			refund.Add(refund, new(big.Int).Mul(new(big.Int).SetUint64(proposal.SCRentFee), big.NewInt(1e+18)))
+		}
+		s.addProposalRefund(headerNumber.Uint64(), proposal.Proposer, refund)
+
+		s.decideProposal(&ProposalResult{Number: headerNumber.Uint64(), Proposal: proposal.copy(), State: proposalStateWithdrawn})
+		delete(s.Proposals, withdraw.ProposalHash)
+	}
+}
+
+// addProposalRefund records a refund to the proposer, paid once the refund delay
+// after the given block passed.
+func (s *Snapshot) addProposalRefund(number uint64, proposer common.Address, refund *big.Int) {
+	if _, ok := s.ProposalRefund[number]; !ok {
+		s.ProposalRefund[number] = make(map[common.Address]*big.Int)
+	}
+	if _, ok := s.ProposalRefund[number][proposer]; !ok {
+		s.ProposalRefund[number][proposer] = new(big.Int).Set(refund)
+	} else {
+		s.ProposalRefund[number][proposer].Add(s.ProposalRefund[number][proposer], refund)
+	}
+}
+
+// decideProposal reports the result of a proposal leaving the open state, and
+// after ProposalLifecycle keeps it in the bounded history.
+func (s *Snapshot) decideProposal(result *ProposalResult) {
+	s.decided = append(s.decided, result)
+	if !s.config.IsProposalLifecycle(new(big.Int).SetUint64(result.Number)) {
+		return
+	}
+	if len(s.ProposalHistory) >= maxProposalHistory {
+		s.ProposalHistory = s.ProposalHistory[len(s.ProposalHistory)-maxProposalHistory+1:]
+	}
+	s.ProposalHistory = append(s.ProposalHistory, result)
+}
@@ -0,0 +1,313 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+// Tests that proposals end up passed, rejected, withdrawn or expired, that the
+// latest declaration wins and that a withdrawal refunds part of the deposit.
+func TestProposals_Lifecycle(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E"}
+	sim := newSimulator(t, names, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+		config.ProposalLifecycleBlock = big.NewInt(1)
+	})
+	sim.run(6)
+
+	propose := func(name string, reward int) common.Hash {
+		node := sim.node(name)
+		tx := sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:proposal:proposal_type:%d:mrpt:%d:vlcnt:%d", proposalTypeMinerRewardDistributionModify, reward, minValidationLoopCnt))
+		return tx.Hash()
+	}
+	declare := func(hash common.Hash, decision string) {
+		for _, node := range sim.nodes {
+			sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:declare:hash:%s:decision:%s", hash.Hex(), decision))
+		}
+	}
+	withdraw := func(name string, hash common.Hash) {
+		node := sim.node(name)
+		sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:withdraw:hash:%s", hash.Hex()))
+	}
+	passed, rejected, withdrawn, expired := propose("A", 500), propose("B", 600), propose("C", 700), propose("D", 800)
+	sim.run(1)
+
+	declare(passed, "yes")
+	declare(rejected, "yes")
+	withdraw("C", withdrawn)
+	withdraw("A", expired) // not the proposer, ignored
+	sim.run(1)
+
+	declare(rejected, "no")
+	sim.run(minValidationLoopCnt*len(names) + 2)
+
+	node := sim.head()
+	head := node.chain.CurrentHeader()
+	for hash, want := range map[common.Hash]string{
+		passed:    proposalStatePassed,
+		rejected:  proposalStateRejected,
+		withdrawn: proposalStateWithdrawn,
+		expired:   proposalStateExpired,
+	} {
+		info, err := node.engine.Proposal(node.chain, head, hash)
+		if err != nil {
+			t.Fatalf("proposal %x: failed to retrieve: %v", hash, err)
+		}
+		if info.State != want {
+			t.Errorf("proposal %x: state mismatch: have %s, want %s", hash, info.State, want)
+		}
+	}
+	if snap := sim.snapshot(node, head); snap.MinerReward != 500 {
+		t.Errorf("miner reward mismatch: have %d, want 500", snap.MinerReward)
+	}
+	history, err := node.engine.ProposalHistory(node.chain, head)
+	if err != nil {
+		t.Fatalf("failed to retrieve proposal history: %v", err)
+	}
+	if len(history) != 4 || history[0].Proposal.Hash != withdrawn {
+		t.Errorf("proposal history mismatch: have %d proposals", len(history))
+	}
+	// Half of the deposit is refunded on the block of the withdrawal
+	info, _ := node.engine.Proposal(node.chain, head, withdrawn)
+	snap := sim.snapshot(node, node.chain.GetHeaderByNumber(info.Decided))
+	refund := new(big.Int).Div(proposalDeposit, big.NewInt(100/proposalWithdrawRefund))
+	if have := snap.ProposalRefund[info.Decided][sim.node("C").addr]; have == nil || have.Cmp(refund) != 0 {
+		t.Errorf("withdrawal refund mismatch: have %v, want %v", have, refund)
+	}
+	if _, err := node.engine.Proposal(node.chain, head, common.HexToHash("0x01")); err != errUnknownProposal {
+		t.Errorf("unknown proposal error mismatch: have %v, want %v", err, errUnknownProposal)
+	}
+}
+
+// Tests that only the proposer may withdraw an open proposal, that a declaration
+// may be changed after ProposalLifecycle and abstained after ProposalQuorum.
+func TestAlien_ValidateWithdraw(t *testing.T) {
+	accounts := newTesterAccountPool()
+	config := &params.AlienConfig{
+		Period:                 3,
+		Epoch:                  30000,
+		MaxSignerCount:         3,
+		MinVoterBalance:        big.NewInt(100),
+		SelfVoteSigners:        []common.UnprefixedAddress{common.UnprefixedAddress(accounts.address("A"))},
+		ProposalLifecycleBlock: big.NewInt(2),
+		ProposalQuorumBlock:    big.NewInt(3),
+	}
+	alien := New(config, ethdb.NewMemDatabase())
+	snap := newSnapshot(alien.config, alien.signatures, common.Hash{}, []*Vote{
+		{Voter: accounts.address("A"), Candidate: accounts.address("A"), Stake: big.NewInt(1000)},
+	}, defaultLoopCntRecalculateSigners)
+	open := common.HexToHash("0x01")
+	snap.Proposals[open] = &Proposal{Hash: open, Proposer: accounts.address("A"), Declares: []*Declare{{ProposalHash: open, Declarer: accounts.address("A"), Decision: true}}}
+
+	tests := []struct {
+		from   string
+		number uint64
+		data   string
+		err    error
+	}{
+		{from: "A", number: 1, data: "ufo:1:event:withdraw:hash:" + open.Hex(), err: errCustomTxUnknown},
+		{from: "A", number: 2, data: "ufo:1:event:withdraw:hash:" + open.Hex()},
+		{from: "A", number: 2, data: "ufo:1:event:withdraw", err: errCustomTxMalformed},
+		{from: "A", number: 2, data: "ufo:1:event:withdraw:hash:" + common.HexToHash("0x02").Hex(), err: errProposalNotOpen},
+		{from: "B", number: 2, data: "ufo:1:event:withdraw:hash:" + open.Hex(), err: errWithdrawNotProposer},
+		{from: "A", number: 1, data: "ufo:1:event:declare:hash:" + open.Hex() + ":decision:no", err: errAlreadyDeclared},
+		{from: "A", number: 2, data: "ufo:1:event:declare:hash:" + open.Hex() + ":decision:no"},
+		{from: "A", number: 2, data: "ufo:1:event:declare:hash:" + open.Hex() + ":decision:yes", err: errAlreadyDeclared},
+		{from: "A", number: 2, data: "ufo:1:event:declare:hash:" + open.Hex() + ":decision:abstain", err: errDeclareDecisionInvalid},
+		{from: "A", number: 3, data: "ufo:1:event:declare:hash:" + open.Hex() + ":decision:abstain"},
+	}
+	for i, tt := range tests {
+		tx := types.NewTransaction(0, accounts.address(tt.from), new(big.Int), 100000, big.NewInt(1), []byte(tt.data))
+		err := alien.validateCustomTx(nil, snap, nil, tt.number, strings.Split(tt.data, ":"), tx, accounts.address(tt.from))
+		if tt.err == nil && err != nil {
+			t.Errorf("test %d: unexpected error %v", i, err)
+		}
+		if tt.err != nil && (err == nil || !strings.HasPrefix(err.Error(), tt.err.Error())) {
+			t.Errorf("test %d: error mismatch, have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that the proposal history only keeps the latest decided proposals.
+func TestSnapshot_ProposalHistoryBound(t *testing.T) {
+	snap := &Snapshot{config: &params.AlienConfig{ProposalLifecycleBlock: big.NewInt(10)}}
+	for i := 0; i < maxProposalHistory+20; i++ {
+		snap.decideProposal(&ProposalResult{Number: uint64(i), Proposal: &Proposal{}, State: proposalStateExpired})
+	}
+	if len(snap.decided) != maxProposalHistory+20 {
+		t.Errorf("decided count mismatch: have %d, want %d", len(snap.decided), maxProposalHistory+20)
+	}
+	if len(snap.ProposalHistory) != maxProposalHistory {
+		t.Fatalf("history length mismatch: have %d, want %d", len(snap.ProposalHistory), maxProposalHistory)
+	}
+	if first, last := snap.ProposalHistory[0].Number, snap.ProposalHistory[maxProposalHistory-1].Number; first != 20 || last != maxProposalHistory+19 {
+		t.Errorf("history range mismatch: have %d-%d, want %d-%d", first, last, 20, maxProposalHistory+19)
+	}
+}
+
+// Tests that after ProposalQuorum abstentions count for the quorum only, and
+// that the proposals without quorum expire.
+func TestProposals_Quorum(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E"}
+	sim := newSimulator(t, names, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+		config.ProposalLifecycleBlock = big.NewInt(1)
+		config.ProposalQuorumBlock = big.NewInt(1)
+	})
+	sim.run(6)
+
+	var hashes []common.Hash
+	for i, name := range []string{"A", "B"} {
+		node := sim.node(name)
+		tx := sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:proposal:proposal_type:%d:mrpt:%d:vlcnt:%d", proposalTypeMinerRewardDistributionModify, 500+i*100, minValidationLoopCnt))
+		hashes = append(hashes, tx.Hash())
+	}
+	sim.run(1)
+
+	// 3 of 5 candidates declare on the first proposal, a single one on the second
+	for name, decision := range map[string]string{"A": "yes", "B": "yes", "C": "abstain"} {
+		node := sim.node(name)
+		sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:declare:hash:%s:decision:%s", hashes[0].Hex(), decision))
+	}
+	node := sim.node("D")
+	sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:declare:hash:%s:decision:yes", hashes[1].Hex()))
+	sim.run(1)
+
+	head := sim.head()
+	info, err := head.engine.Proposal(head.chain, head.chain.CurrentHeader(), hashes[0])
+	if err != nil {
+		t.Fatalf("failed to retrieve open proposal: %v", err)
+	}
+	if info.State != proposalStateOpen || info.Tally == nil || !info.Tally.Quorum || info.Tally.Abstain.Sign() == 0 {
+		t.Errorf("open proposal tally mismatch: have %+v", info.Tally)
+	}
+	sim.run(minValidationLoopCnt*len(names) + 2)
+
+	passed, err := head.engine.DecidedProposal(head.chain, head.chain.CurrentHeader(), hashes[0])
+	if err != nil {
+		t.Fatalf("failed to retrieve decided proposal: %v", err)
+	}
+	if passed.State != proposalStatePassed || !passed.Passed {
+		t.Errorf("proposal state mismatch: have %s, want %s", passed.State, proposalStatePassed)
+	}
+	stake := new(big.Int).Div(passed.Tally.Total, big.NewInt(int64(len(names))))
+	if passed.Tally.Yes.Cmp(new(big.Int).Mul(stake, big.NewInt(2))) != 0 || passed.Tally.Abstain.Cmp(stake) != 0 || passed.Tally.No.Sign() != 0 {
+		t.Errorf("stake breakdown mismatch: have %+v", passed.Tally)
+	}
+	expired, err := head.engine.DecidedProposal(head.chain, head.chain.CurrentHeader(), hashes[1])
+	if err != nil {
+		t.Fatalf("failed to retrieve decided proposal: %v", err)
+	}
+	if expired.State != proposalStateExpired || expired.Tally.Quorum {
+		t.Errorf("proposal without quorum mismatch: have %s, quorum %v", expired.State, expired.Tally.Quorum)
+	}
+}
+
+// Tests that the latest declaration of a declarer in a block wins, whether it
+// is an abstention or not.
+func TestProposals_SameBlockDeclares(t *testing.T) {
+	names := []string{"A", "B", "C"}
+	sim := newSimulator(t, names, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+		config.ProposalLifecycleBlock = big.NewInt(1)
+		config.ProposalQuorumBlock = big.NewInt(1)
+	})
+	sim.run(4)
+
+	proposer := sim.node("A")
+	proposal := sim.sendTx(proposer.key, proposer.addr, nil, fmt.Sprintf("ufo:1:event:proposal:proposal_type:%d:mrpt:500:vlcnt:%d", proposalTypeMinerRewardDistributionModify, minValidationLoopCnt))
+	sim.run(1)
+
+	for name, decisions := range map[string][]string{"A": {"abstain", "yes"}, "B": {"yes", "abstain"}, "C": {"no", "abstain", "no"}} {
+		node := sim.node(name)
+		for _, decision := range decisions {
+			sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:declare:hash:%s:decision:%s", proposal.Hash().Hex(), decision))
+		}
+	}
+	sim.run(1)
+
+	head := sim.head()
+	info, err := head.engine.Proposal(head.chain, head.chain.CurrentHeader(), proposal.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve open proposal: %v", err)
+	}
+	stake := new(big.Int).Div(info.Tally.Total, big.NewInt(int64(len(names))))
+	if info.Tally.Yes.Cmp(stake) != 0 || info.Tally.Abstain.Cmp(stake) != 0 || info.Tally.No.Cmp(stake) != 0 {
+		t.Errorf("stake breakdown mismatch: have %+v, want one declarer each", info.Tally)
+	}
+	sim.checkConverged()
+}
+
+// Tests the quorum, approval and veto rules on the stake breakdown.
+func TestSnapshot_TallyProposal(t *testing.T) {
+	accounts := newTesterAccountPool()
+	config := &params.AlienConfig{
+		ProposalThresholds: map[uint64]*params.AlienProposalThreshold{
+			0:                           {Quorum: 400, Approval: 500, Veto: 0},
+			proposalTypeCandidateRemove: {Quorum: 600, Approval: 750, Veto: 200},
+		},
+	}
+	snap := &Snapshot{config: config, Tally: make(map[common.Address]*big.Int)}
+	for _, name := range []string{"A", "B", "C", "D", "E"} {
+		snap.Tally[accounts.address(name)] = big.NewInt(100)
+	}
+	tests := []struct {
+		proposalType uint64
+		yes, no      []string
+		abstain      []string
+		state        string
+		vetoed       bool
+	}{
+		{proposalType: proposalTypeCandidateAdd, yes: []string{"A"}, state: proposalStateExpired},
+		{proposalType: proposalTypeCandidateAdd, yes: []string{"A"}, abstain: []string{"B"}, state: proposalStatePassed},
+		{proposalType: proposalTypeCandidateAdd, yes: []string{"A"}, no: []string{"B"}, state: proposalStateRejected},
+		{proposalType: proposalTypeCandidateAdd, yes: []string{"A", "B"}, no: []string{"C"}, state: proposalStatePassed},
+		{proposalType: proposalTypeCandidateRemove, yes: []string{"A", "B"}, abstain: []string{"C"}, state: proposalStatePassed},
+		{proposalType: proposalTypeCandidateRemove, yes: []string{"A", "B", "C"}, no: []string{"D"}, state: proposalStateRejected, vetoed: true},
+		{proposalType: proposalTypeCandidateRemove, yes: []string{"A", "B"}, state: proposalStateExpired},
+	}
+	for i, tt := range tests {
+		proposal := &Proposal{ProposalType: tt.proposalType}
+		for _, names := range [][]string{tt.yes, tt.no, tt.abstain} {
+			for _, name := range names {
+				proposal.Declares = append(proposal.Declares, &Declare{Declarer: accounts.address(name)})
+			}
+		}
+		for j := range tt.yes {
+			proposal.Declares[j].Decision = true
+		}
+		for j := range tt.abstain {
+			proposal.Declares[len(tt.yes)+len(tt.no)+j].Abstain = true
+		}
+		tally := snap.tallyProposal(proposal)
+		if state := tally.State(); state != tt.state || tally.Vetoed != tt.vetoed {
+			t.Errorf("test %d: decision mismatch: have %s (vetoed %v), want %s (vetoed %v)", i, state, tally.Vetoed, tt.state, tt.vetoed)
+		}
+		if tally.Total.Int64() != 500 || tally.Yes.Int64() != int64(100*len(tt.yes)) || tally.No.Int64() != int64(100*len(tt.no)) || tally.Abstain.Int64() != int64(100*len(tt.abstain)) {
+			t.Errorf("test %d: stake breakdown mismatch: have %+v", i, tally)
+		}
+	}
+}
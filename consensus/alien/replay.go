@@ -0,0 +1,329 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/state"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/log"
+	"github.com/TTCECO/gttc/params"
+)
+
+// replayBatchSize is the number of headers applied to a snapshot at once while
+// replaying the chain.
+const replayBatchSize = 2048
+
+// errGenesisStateUnavailable is returned if the genesis votes can't be rebuilt
+// to replay the chain from the genesis block.
+var errGenesisStateUnavailable = errors.New("genesis state unavailable")
+
+// stateReader is implemented by chains giving access to historical states.
+type stateReader interface {
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// SnapshotVerification is the result of checking the snapshots of a block that
+// the engine would use against the snapshot rebuilt by replaying the headers.
+type SnapshotVerification struct {
+	Number     uint64           `json:"number"`
+	Hash       common.Hash      `json:"hash"`
+	Base       string           `json:"base"` // Replay origin, "genesis" or "checkpoint"
+	BaseNumber uint64           `json:"baseNumber"`
+	Replayed   uint64           `json:"replayed"` // Number of headers replayed
+	Checks     []*SnapshotCheck `json:"checks"`
+	Consistent bool             `json:"consistent"`
+}
+
+// SnapshotCheck lists the differences between one source of a snapshot and the
+// replayed snapshot.
+type SnapshotCheck struct {
+	Source string          `json:"source"` // "memory", "disk" or "derived" from the nearest ancestor
+	Diffs  []*SnapshotDiff `json:"diffs,omitempty"`
+}
+
+// SnapshotDiff is a single field of a snapshot differing from the replayed one.
+type SnapshotDiff struct {
+	Field    string      `json:"field"`
+	Have     interface{} `json:"have"`     // Value in the checked snapshot
+	Replayed interface{} `json:"replayed"` // Value in the replayed snapshot
+}
+
+// VerifySnapshot rebuilds the snapshot after a canonical header by replaying the
+// headers from the genesis block, or from the trusted checkpoint if the header
+// is past it, and compares every field with the snapshots the engine has in
+// memory and on disk for the header, or derives from an earlier one otherwise.
+func (a *Alien) VerifySnapshot(chain consensus.ChainReader, header *types.Header) (*SnapshotVerification, error) {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil, errUnknownBlock
+	}
+	replayed, result, err := a.replaySnapshot(chain, header)
+	if err != nil {
+		return nil, err
+	}
+	// Gather every snapshot the engine could use for the header
+	hash := header.Hash()
+	if s, ok := a.recents.Get(hash); ok {
+		if err := result.check("memory", s.(*Snapshot), replayed); err != nil {
+			return nil, err
+		}
+	}
+	if s, err := loadSnapshot(a.config, a.signatures, a.db, hash); err == nil {
+		if err := result.check("disk", s, replayed); err != nil {
+			return nil, err
+		}
+	}
+	if len(result.Checks) == 0 {
+		snap, err := a.snapshot(chain, number, hash, nil, nil, defaultLoopCntRecalculateSigners)
+		if err != nil {
+			return nil, err
+		}
+		if err := result.check("derived", snap, replayed); err != nil {
+			return nil, err
+		}
+	}
+	result.Consistent = true
+	for _, check := range result.Checks {
+		if len(check.Diffs) > 0 {
+			result.Consistent = false
+		}
+	}
+	return result, nil
+}
+
+// replaySnapshot rebuilds the snapshot after a canonical header without using
+// any cached or stored snapshot, except for one verified by the trusted checkpoint.
+func (a *Alien) replaySnapshot(chain consensus.ChainReader, header *types.Header) (*Snapshot, *SnapshotVerification, error) {
+	result := &SnapshotVerification{Number: header.Number.Uint64(), Hash: header.Hash()}
+
+	snap := a.checkpointSnapshot(chain, result.Number)
+	if snap != nil {
+		result.Base, result.BaseNumber = "checkpoint", snap.Number
+	} else {
+		genesis := chain.GetHeaderByNumber(0)
+		if genesis == nil {
+			return nil, nil, errUnknownBlock
+		}
+		votes, err := a.replayGenesisVotes(chain, genesis)
+		if err != nil {
+			return nil, nil, err
+		}
+		snap = newSnapshot(a.config, a.signatures, genesis.Hash(), votes, defaultLoopCntRecalculateSigners)
+		result.Base = "genesis"
+	}
+	var (
+		batch  = make([]*types.Header, 0, replayBatchSize)
+		parent = snap.Hash
+		logged = time.Now()
+		err    error
+	)
+	for n := snap.Number + 1; n <= result.Number; n++ {
+		h := chain.GetHeaderByNumber(n)
+		if h == nil || h.ParentHash != parent {
+			return nil, nil, consensus.ErrUnknownAncestor
+		}
+		batch, parent = append(batch, h), h.Hash()
+
+		if len(batch) == replayBatchSize || n == result.Number {
+			if snap, err = snap.apply(batch); err != nil {
+				return nil, nil, fmt.Errorf("replay failed at block #%d: %v", batch[0].Number, err)
+			}
+			result.Replayed += uint64(len(batch))
+			batch = batch[:0]
+
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Replaying alien snapshot", "number", n, "target", result.Number)
+				logged = time.Now()
+			}
+		}
+	}
+	if parent != result.Hash {
+		return nil, nil, consensus.ErrUnknownAncestor
+	}
+	return snap, result, nil
+}
+
+// checkpointSnapshot returns the stored snapshot of the trusted checkpoint if it
+// precedes the given block on the local chain and hashes to the checkpoint.
+func (a *Alien) checkpointSnapshot(chain consensus.ChainReader, number uint64) *Snapshot {
+	a.lock.RLock()
+	checkpoint := a.checkpoint
+	a.lock.RUnlock()
+
+	if checkpoint == nil || checkpoint.Number >= number {
+		return nil
+	}
+	if header := chain.GetHeaderByNumber(checkpoint.Number); header == nil || header.Hash() != checkpoint.Hash {
+		return nil
+	}
+	snap, err := loadSnapshot(a.config, a.signatures, a.db, checkpoint.Hash)
+	if err != nil {
+		return nil
+	}
+	if hash, err := snap.commitHash(); err != nil || hash != checkpoint.SnapshotHash {
+		log.Warn("Stored checkpoint snapshot mismatch", "number", checkpoint.Number, "hash", checkpoint.Hash)
+		return nil
+	}
+	return snap
+}
+
+// replayGenesisVotes rebuilds the self votes of the genesis signers, from the
+// genesis state if available or from the light config otherwise.
+func (a *Alien) replayGenesisVotes(chain consensus.ChainReader, genesis *types.Header) ([]*Vote, error) {
+	if chain, ok := chain.(stateReader); ok {
+		if statedb, err := chain.StateAt(genesis.Root); err == nil {
+			return selfVotes(a.config, statedb.GetBalance), nil
+		}
+	}
+	if a.config.LightConfig != nil {
+		return selfVotes(a.config, a.lightGenesisBalance), nil
+	}
+	return nil, errGenesisStateUnavailable
+}
+
+// lightGenesisBalance returns the genesis balance of an account in the light
+// config, or nil if it has none.
+func (a *Alien) lightGenesisBalance(address common.Address) *big.Int {
+	account, ok := a.config.LightConfig.Alloc[common.UnprefixedAddress(address)]
+	if !ok {
+		return nil
+	}
+	balance := new(big.Int)
+	balance.UnmarshalText([]byte(account.Balance))
+	return balance
+}
+
+// selfVotes returns the self votes of the genesis signers, skipping the ones
+// without a balance.
+func selfVotes(config *params.AlienConfig, balance func(common.Address) *big.Int) []*Vote {
+	var votes []*Vote
+	alreadyVote := make(map[common.Address]struct{})
+	for _, unPrefixVoter := range config.SelfVoteSigners {
+		voter := common.Address(unPrefixVoter)
+		if _, ok := alreadyVote[voter]; ok {
+			continue
+		}
+		if stake := balance(voter); stake != nil {
+			votes = append(votes, &Vote{Voter: voter, Candidate: voter, Stake: stake})
+			alreadyVote[voter] = struct{}{}
+		}
+	}
+	return votes
+}
+
+// check compares a snapshot with the replayed one and records the differences.
+func (v *SnapshotVerification) check(source string, snap *Snapshot, replayed *Snapshot) error {
+	have, err := snapshotFields(snap)
+	if err != nil {
+		return err
+	}
+	want, err := snapshotFields(replayed)
+	if err != nil {
+		return err
+	}
+	check := &SnapshotCheck{Source: source}
+	diffFields("", have, want, &check.Diffs)
+	v.Checks = append(v.Checks, check)
+	return nil
+}
+
+// snapshotFields decodes a snapshot into its generic JSON representation. Numbers
+// are kept as text, as stakes don't fit a float64.
+func snapshotFields(snap *Snapshot) (interface{}, error) {
+	blob, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(blob))
+	decoder.UseNumber()
+
+	var fields interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffFields recursively appends the differences between two JSON values to
+// diffs, naming each by its path. Missing and empty values are equal.
+func diffFields(path string, have, want interface{}, diffs *[]*SnapshotDiff) {
+	if isEmptyField(have) && isEmptyField(want) {
+		return
+	}
+	switch h := have.(type) {
+	case map[string]interface{}:
+		w, ok := want.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(h)+len(w))
+		for key := range h {
+			keys = append(keys, key)
+		}
+		for key := range w {
+			if _, ok := h[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			diffFields(field, h[key], w[key], diffs)
+		}
+		return
+
+	case []interface{}:
+		w, ok := want.([]interface{})
+		if !ok || len(h) != len(w) {
+			break
+		}
+		for i := range h {
+			diffFields(fmt.Sprintf("%s[%d]", path, i), h[i], w[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(have, want) {
+		*diffs = append(*diffs, &SnapshotDiff{Field: path, Have: have, Replayed: want})
+	}
+}
+
+// isEmptyField reports whether a JSON value is null or an empty object or array.
+func isEmptyField(field interface{}) bool {
+	switch f := field.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(f) == 0
+	case []interface{}:
+		return len(f) == 0
+	}
+	return false
+}
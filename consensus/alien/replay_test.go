@@ -0,0 +1,102 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+)
+
+// Tests that the snapshots a node uses are verified against a replay of the
+// chain, reporting the differing fields of tampered ones.
+func TestVerifySnapshot(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, nil)
+	sim.run(12)
+	node := sim.node("A")
+	head := node.chain.CurrentHeader()
+
+	result, err := node.engine.VerifySnapshot(node.chain, head)
+	if err != nil {
+		t.Fatalf("failed to verify snapshot: %v", err)
+	}
+	if !result.Consistent || result.Base != "genesis" || result.Replayed != head.Number.Uint64() {
+		t.Fatalf("verification mismatch: consistent %v, base %s, replayed %d", result.Consistent, result.Base, result.Replayed)
+	}
+	if len(result.Checks) == 0 || result.Checks[0].Source != "memory" {
+		t.Fatalf("cached snapshot not checked: %+v", result.Checks)
+	}
+	// Tamper with the cached snapshot and store a different one on disk
+	snap, err := node.engine.Snapshot(node.chain, head)
+	if err != nil {
+		t.Fatalf("failed to retrieve snapshot: %v", err)
+	}
+	voter := sim.node("B").addr
+	stored := snap.copy()
+	stored.LoopStartTime++
+	if err := stored.store(node.engine.db); err != nil {
+		t.Fatalf("failed to store snapshot: %v", err)
+	}
+	snap.Tally[voter] = new(big.Int).Add(snap.Tally[voter], common.Big1)
+
+	if result, err = node.engine.VerifySnapshot(node.chain, head); err != nil {
+		t.Fatalf("failed to verify snapshot: %v", err)
+	}
+	if result.Consistent || len(result.Checks) != 2 {
+		t.Fatalf("tampered snapshots not reported: %+v", result.Checks)
+	}
+	want := map[string]string{
+		"memory": "tally." + strings.ToLower(voter.Hex()),
+		"disk":   "loopStartTime",
+	}
+	for _, check := range result.Checks {
+		if len(check.Diffs) != 1 || check.Diffs[0].Field != want[check.Source] {
+			t.Errorf("%s: diff mismatch: have %+v, want field %s", check.Source, check.Diffs, want[check.Source])
+		}
+	}
+}
+
+// Tests the field paths of snapshot differences.
+func TestDiffFields(t *testing.T) {
+	have := map[string]interface{}{
+		"number":  json.Number("5"),
+		"signers": []interface{}{"a", "b"},
+		"tally":   map[string]interface{}{"x": "1", "y": "2"},
+		"votes":   nil,
+	}
+	want := map[string]interface{}{
+		"number":  json.Number("5"),
+		"signers": []interface{}{"a", "c"},
+		"tally":   map[string]interface{}{"x": "1", "z": "3"},
+		"votes":   map[string]interface{}{},
+	}
+	var diffs []*SnapshotDiff
+	diffFields("", have, want, &diffs)
+
+	fields := []string{"signers[1]", "tally.y", "tally.z"}
+	if len(diffs) != len(fields) {
+		t.Fatalf("diff count mismatch: have %d, want %d", len(diffs), len(fields))
+	}
+	for i, field := range fields {
+		if diffs[i].Field != field {
+			t.Errorf("diff %d: field mismatch: have %s, want %s", i, diffs[i].Field, field)
+		}
+	}
+}
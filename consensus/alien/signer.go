@@ -0,0 +1,68 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+)
+
+// errNotAuthorized is returned when the engine is asked to sign a transaction
+// before any signer was authorized.
+var errNotAuthorized = errors.New("alien signer not authorized")
+
+// HeaderSignerFn is a signer callback function to request a whole header to be
+// sealed by a backing account. It allows remote signers to inspect the block
+// they seal instead of signing a blind hash.
+type HeaderSignerFn func(accounts.Account, *types.Header) ([]byte, error)
+
+// AuthorizeRemote injects a remote signer into the consensus engine to mint new
+// blocks with. Headers are sealed through signHdrFn, while the PBFT and side
+// chain confirmation transactions are signed through signTxFn.
+func (a *Alien) AuthorizeRemote(signer common.Address, signHdrFn HeaderSignerFn, signTxFn SignTxFn) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.signer = signer
+	a.signFn = nil
+	a.signTxFn = signTxFn
+	a.signHdrFn = signHdrFn
+}
+
+// SignTx signs a transaction with the credentials the engine was authorized
+// with, so that the confirmation transactions are signed by the same (possibly
+// remote) signer which seals the blocks.
+func (a *Alien) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	a.lock.RLock()
+	signer, signTxFn := a.signer, a.signTxFn
+	a.lock.RUnlock()
+
+	if signTxFn == nil {
+		return nil, errNotAuthorized
+	}
+	return signTxFn(accounts.Account{Address: signer}, tx, chainID)
+}
+
+// SealHash returns the hash of a block prior to it being sealed, which is the
+// hash a signer signs to seal the block.
+func SealHash(header *types.Header) (common.Hash, error) {
+	return sigHash(header)
+}
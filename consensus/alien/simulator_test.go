@@ -0,0 +1,704 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/core/vm"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+// simGenesisTime is the fixed genesis time of simulated networks. Being far in
+// the past, blocks are sealed without waiting and the chains are deterministic.
+const simGenesisTime = 1546300800
+
+var (
+	simBalance  = new(big.Int).Mul(big.NewInt(1e+6), big.NewInt(1e+18)) // Genesis balance of each signer
+	simGasPrice = big.NewInt(1e+9)                                      // Gas price of all custom txs
+)
+
+// simKey returns the deterministic private key of the given account name.
+func simKey(name string) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("alien-simulator-" + name)))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// simNode is a signer of the simulated network, running its own engine on top
+// of its own database.
+type simNode struct {
+	name   string
+	key    *ecdsa.PrivateKey
+	addr   common.Address
+	engine *Alien
+	chain  *core.BlockChain
+	online bool
+	group  int // Network partition the node is in, only nodes in the same one talk
+}
+
+// simulator runs a network of alien signers slot by slot on a mock clock. Each
+// slot the in-turn signer of every partition seals a block on its head, which
+// is imported by all online nodes of the partition. The snapshot invariants are
+// checked on every new head of every node.
+type simulator struct {
+	t       *testing.T
+	config  *params.ChainConfig
+	genesis *types.Block
+	nodes   []*simNode
+	clock   uint64 // Mock clock, the timestamp of the next slot
+
+	pending []*types.Transaction        // Txs to include in the next sealed block
+	missed  map[common.Address]int      // Total slots missed by each signer
+	checked map[*simNode]common.Hash    // Head last checked on each node
+	signers map[common.Address]*simNode // Nodes by signer address
+}
+
+// newSimulator creates a network of self voting signers with the given names,
+// all online and connected, and starting at genesis.
+func newSimulator(t *testing.T, names []string, configure func(config *params.AlienConfig)) *simulator {
+	alienConfig := &params.AlienConfig{
+		Period:           1,
+		Epoch:            30000,
+		MaxSignerCount:   uint64(len(names)),
+		MinVoterBalance:  big.NewInt(100),
+		GenesisTimestamp: simGenesisTime,
+		LightConfig:      &params.AlienLightConfig{Alloc: make(map[common.UnprefixedAddress]params.GenesisAccount)},
+	}
+	alloc := make(core.GenesisAlloc)
+	for _, name := range names {
+		addr := crypto.PubkeyToAddress(simKey(name).PublicKey)
+		alloc[addr] = core.GenesisAccount{Balance: simBalance}
+		alienConfig.SelfVoteSigners = append(alienConfig.SelfVoteSigners, common.UnprefixedAddress(addr))
+		alienConfig.LightConfig.Alloc[common.UnprefixedAddress(addr)] = params.GenesisAccount{Balance: simBalance.String()}
+	}
+	if configure != nil {
+		configure(alienConfig)
+	}
+	config := *params.AllAlienProtocolChanges
+	config.Alien = alienConfig
+
+	gspec := &core.Genesis{
+		Config:    &config,
+		Timestamp: simGenesisTime,
+		ExtraData: make([]byte, extraVanity+extraSeal),
+		GasLimit:  params.GenesisGasLimit,
+		Alloc:     alloc,
+	}
+	sim := &simulator{
+		t:       t,
+		config:  &config,
+		clock:   simGenesisTime,
+		missed:  make(map[common.Address]int),
+		checked: make(map[*simNode]common.Hash),
+		signers: make(map[common.Address]*simNode),
+	}
+	for _, name := range names {
+		db := ethdb.NewMemDatabase()
+		sim.genesis = gspec.MustCommit(db)
+
+		engine := New(alienConfig, db)
+		chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{})
+		if err != nil {
+			t.Fatalf("node %s: failed to create chain: %v", name, err)
+		}
+		// Build the genesis snapshot with the genesis votes before any import
+		if err := engine.ApplyGenesis(chain, sim.genesis.Hash()); err != nil {
+			t.Fatalf("node %s: failed to apply genesis: %v", name, err)
+		}
+		key := simKey(name)
+		node := &simNode{
+			name:   name,
+			key:    key,
+			addr:   crypto.PubkeyToAddress(key.PublicKey),
+			engine: engine,
+			chain:  chain,
+			online: true,
+		}
+		engine.Authorize(node.addr, func(account accounts.Account, hash []byte) ([]byte, error) {
+			return crypto.Sign(hash, key)
+		}, nil)
+
+		sim.nodes = append(sim.nodes, node)
+		sim.signers[node.addr] = node
+	}
+	return sim
+}
+
+// node returns the simulated node with the given name.
+func (s *simulator) node(name string) *simNode {
+	for _, node := range s.nodes {
+		if node.name == name {
+			return node
+		}
+	}
+	s.t.Fatalf("unknown node %s", name)
+	return nil
+}
+
+// groups returns the online nodes of each network partition.
+func (s *simulator) groups() [][]*simNode {
+	var (
+		ids    []int
+		groups = make(map[int][]*simNode)
+	)
+	for _, node := range s.nodes {
+		if !node.online {
+			continue
+		}
+		if _, ok := groups[node.group]; !ok {
+			ids = append(ids, node.group)
+		}
+		groups[node.group] = append(groups[node.group], node)
+	}
+	sort.Ints(ids)
+
+	var result [][]*simNode
+	for _, id := range ids {
+		result = append(result, groups[id])
+	}
+	return result
+}
+
+// run advances the mock clock by the given number of slots.
+func (s *simulator) run(slots int) {
+	for i := 0; i < slots; i++ {
+		s.step()
+	}
+}
+
+// step seals the blocks of a single slot in every partition. If the in-turn
+// signer of the slot is absent, its backup seals after the grace delay once
+// allowed.
+func (s *simulator) step() {
+	now := s.clock
+	s.clock += s.config.Alien.Period
+
+	for _, group := range s.groups() {
+		head := group[0].chain.CurrentHeader()
+		snap := s.snapshot(group[0], head)
+		signer := *snap.Signers[(now-snap.LoopStartTime)/s.config.Alien.Period%uint64(len(snap.Signers))]
+
+		sealer, time := s.signers[signer], now
+		if !s.present(sealer, group[0].group) {
+			s.missed[signer]++
+
+			backup, ok := snap.backupSigner(now)
+			if !ok || !s.config.Alien.IsBackupSeal(new(big.Int).Add(head.Number, common.Big1)) || !s.present(s.signers[backup], group[0].group) {
+				continue
+			}
+			sealer = s.signers[backup]
+			time += sealer.engine.backupDelay()
+		}
+		// Blocks following a delayed backup block keep a period from it
+		if earliest := head.Time.Uint64() + s.config.Alien.Period; sealer.engine.isBackupSeal(head) && time < earliest {
+			time = earliest
+		}
+		block := s.seal(sealer, time)
+		if _, err := sealer.chain.InsertChain(types.Blocks{block}); err != nil {
+			s.t.Fatalf("node %s: failed to import sealed block %d: %v", sealer.name, block.Number(), err)
+		}
+		for _, node := range group {
+			s.sync(node, sealer)
+		}
+		s.checkMissing(sealer, block)
+	}
+	for _, node := range s.nodes {
+		if node.online {
+			s.check(node)
+		}
+	}
+}
+
+// present returns whether the node is online in the given partition.
+func (s *simulator) present(node *simNode, group int) bool {
+	return node != nil && node.online && node.group == group
+}
+
+// seal assembles and seals a block with all pending txs on top of the head of
+// the given node.
+func (s *simulator) seal(node *simNode, time uint64) *types.Block {
+	parent := node.chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+		Time:       new(big.Int).SetUint64(time),
+		Coinbase:   node.addr,
+		Extra:      make([]byte, extraVanity),
+		Difficulty: node.engine.CalcDifficulty(node.chain, time, parent.Header()),
+	}
+	statedb, err := node.chain.StateAt(parent.Root())
+	if err != nil {
+		s.t.Fatalf("block %d: failed to retrieve state: %v", header.Number, err)
+	}
+	var (
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+		txs      []*types.Transaction
+		receipts []*types.Receipt
+	)
+	for _, tx := range s.pending {
+		statedb.Prepare(tx.Hash(), common.Hash{}, len(txs))
+		receipt, _, err := core.ApplyTransaction(s.config, node.chain, &header.Coinbase, gp, statedb, header, tx, &header.GasUsed, vm.Config{})
+		if err != nil {
+			s.t.Fatalf("block %d: failed to apply tx %x: %v", header.Number, tx.Hash(), err)
+		}
+		txs = append(txs, tx)
+		receipts = append(receipts, receipt)
+	}
+	s.pending = nil
+
+	block, err := node.engine.Finalize(node.chain, header, statedb, txs, nil, receipts)
+	if err != nil {
+		s.t.Fatalf("block %d: failed to finalize: %v", header.Number, err)
+	}
+	if block, err = node.engine.Seal(node.chain, block, make(chan struct{})); err != nil {
+		s.t.Fatalf("block %d: failed to seal: %v", header.Number, err)
+	}
+	return block
+}
+
+// sync imports the canonical chain of the peer into the given node.
+func (s *simulator) sync(node *simNode, peer *simNode) {
+	var blocks types.Blocks
+	for block := peer.chain.CurrentBlock(); !node.chain.HasBlock(block.Hash(), block.NumberU64()); block = peer.chain.GetBlock(block.ParentHash(), block.NumberU64()-1) {
+		blocks = append(blocks, block)
+	}
+	for i := 0; i < len(blocks)/2; i++ {
+		blocks[i], blocks[len(blocks)-1-i] = blocks[len(blocks)-1-i], blocks[i]
+	}
+	if len(blocks) > 0 {
+		if _, err := node.chain.InsertChain(blocks); err != nil {
+			s.t.Fatalf("node %s: failed to import blocks of %s: %v", node.name, peer.name, err)
+		}
+	}
+}
+
+// setOnline turns a node on or off. Coming back online it catches up with its
+// partition.
+func (s *simulator) setOnline(name string, online bool) {
+	node := s.node(name)
+	node.online = online
+	if online {
+		for _, peer := range s.nodes {
+			if peer.online && peer.group == node.group {
+				s.sync(node, peer)
+			}
+		}
+		s.check(node)
+	}
+}
+
+// partition splits the network, every listed set of nodes only talking to
+// each other from now on.
+func (s *simulator) partition(groups ...[]string) {
+	for i, names := range groups {
+		for _, name := range names {
+			s.node(name).group = i + 1
+		}
+	}
+}
+
+// heal reconnects all partitions, every node syncing the chain of all others.
+func (s *simulator) heal() {
+	for _, node := range s.nodes {
+		node.group = 0
+	}
+	for _, node := range s.nodes {
+		for _, peer := range s.nodes {
+			if node.online && peer.online {
+				s.sync(node, peer)
+			}
+		}
+	}
+	for _, node := range s.nodes {
+		if node.online {
+			s.check(node)
+		}
+	}
+}
+
+// sendTx signs a tx of the given account and queues it for the next block.
+func (s *simulator) sendTx(key *ecdsa.PrivateKey, to common.Address, value *big.Int, data string) *types.Transaction {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	statedb, err := s.head().chain.State()
+	if err != nil {
+		s.t.Fatalf("failed to retrieve state: %v", err)
+	}
+	nonce := statedb.GetNonce(from)
+	for _, tx := range s.pending {
+		if sender, _ := types.Sender(types.NewEIP155Signer(s.config.ChainId), tx); sender == from {
+			nonce++
+		}
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	tx, err := types.SignTx(types.NewTransaction(nonce, to, value, 200000, simGasPrice, []byte(data)), types.NewEIP155Signer(s.config.ChainId), key)
+	if err != nil {
+		s.t.Fatalf("failed to sign tx: %v", err)
+	}
+	s.pending = append(s.pending, tx)
+	return tx
+}
+
+// head returns the first online node.
+func (s *simulator) head() *simNode {
+	for _, node := range s.nodes {
+		if node.online {
+			return node
+		}
+	}
+	s.t.Fatalf("no node online")
+	return nil
+}
+
+// snapshot returns the snapshot of the given node at the given header.
+func (s *simulator) snapshot(node *simNode, header *types.Header) *Snapshot {
+	snap, err := node.engine.snapshot(node.chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		s.t.Fatalf("node %s: failed to retrieve snapshot at %d: %v", node.name, header.Number, err)
+	}
+	return snap
+}
+
+// headerExtra decodes the header extra of the given header.
+func (s *simulator) headerExtra(header *types.Header) HeaderExtra {
+	var extra HeaderExtra
+	if err := decodeHeaderExtra(s.config.Alien, header.Number, header.Extra[extraVanity:len(header.Extra)-extraSeal], &extra); err != nil {
+		s.t.Fatalf("block %d: failed to decode header extra: %v", header.Number, err)
+	}
+	return extra
+}
+
+// checkMissing checks that a block after missed slots punishes the signers in
+// turn during them.
+func (s *simulator) checkMissing(node *simNode, block *types.Block) {
+	header := block.Header()
+	if !s.config.Alien.IsTrantor(header.Number) || header.Number.Uint64() <= s.config.Alien.MaxSignerCount {
+		return
+	}
+	parent := node.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	snap := s.snapshot(node, parent)
+
+	// The slot of a backup block is missed by its in-turn signer
+	end, backup := header.Time.Uint64(), node.engine.isBackupSeal(header)
+	if backup {
+		end = snap.slotStart(end)
+	}
+	var missing []common.Address
+	for time := parent.Time.Uint64() + s.config.Alien.Period; time < end; time += s.config.Alien.Period {
+		missing = append(missing, *snap.Signers[(time-snap.LoopStartTime)/s.config.Alien.Period%uint64(len(snap.Signers))])
+	}
+	if backup {
+		missing = append(missing, *snap.Signers[(end-snap.LoopStartTime)/s.config.Alien.Period%uint64(len(snap.Signers))])
+	}
+	if len(missing) >= len(snap.Signers) {
+		return
+	}
+	extra := s.headerExtra(header)
+	if fmt.Sprint(extra.SignerMissing) != fmt.Sprint(missing) {
+		s.t.Fatalf("block %d: signer missing mismatch: have %v, want %v", header.Number, extra.SignerMissing, missing)
+	}
+}
+
+// check verifies the snapshot invariants at the head of the given node.
+func (s *simulator) check(node *simNode) {
+	header := node.chain.CurrentHeader()
+	if header.Number.Uint64() == 0 || s.checked[node] == header.Hash() {
+		return
+	}
+	s.checked[node] = header.Hash()
+	snap := s.snapshot(node, header)
+
+	// The tally of each candidate must sum up the stake of its votes
+	tally := make(map[common.Address]*big.Int)
+	for _, vote := range snap.Votes {
+		if _, ok := tally[vote.Candidate]; !ok {
+			tally[vote.Candidate] = new(big.Int)
+		}
+		tally[vote.Candidate].Add(tally[vote.Candidate], vote.Stake)
+		if _, ok := snap.Voters[vote.Voter]; !ok {
+			s.t.Fatalf("node %s, block %d: vote of %x without voter", node.name, header.Number, vote.Voter)
+		}
+	}
+	if len(tally) != len(snap.Tally) {
+		s.t.Fatalf("node %s, block %d: tally count mismatch: have %d, want %d", node.name, header.Number, len(snap.Tally), len(tally))
+	}
+	for candidate, stake := range tally {
+		if have := snap.Tally[candidate]; have == nil || have.Cmp(stake) != 0 {
+			s.t.Fatalf("node %s, block %d: tally of %x mismatch: have %v, want %v", node.name, header.Number, candidate, have, stake)
+		}
+	}
+	// The signer queue must be the one in the header, made of distinct candidates
+	extra := s.headerExtra(header)
+	if uint64(len(snap.Signers)) != s.config.Alien.MaxSignerCount || len(snap.Signers) != len(extra.SignerQueue) {
+		s.t.Fatalf("node %s, block %d: signer queue length mismatch: have %d, want %d", node.name, header.Number, len(snap.Signers), s.config.Alien.MaxSignerCount)
+	}
+	queued := make(map[common.Address]bool)
+	for i, signer := range snap.Signers {
+		if *signer != extra.SignerQueue[i] {
+			s.t.Fatalf("node %s, block %d: signer %d mismatch: have %x, want %x", node.name, header.Number, i, *signer, extra.SignerQueue[i])
+		}
+		if _, ok := snap.Candidates[*signer]; !ok || queued[*signer] {
+			s.t.Fatalf("node %s, block %d: invalid signer %x in queue", node.name, header.Number, *signer)
+		}
+		queued[*signer] = true
+	}
+	// Punish scores are capped, and reset at the Trantor fork
+	for signer, score := range snap.Punished {
+		if score == 0 || score > 10*defaultFullCredit+missingPublishCredit {
+			s.t.Fatalf("node %s, block %d: invalid punish score %d of %x", node.name, header.Number, score, signer)
+		}
+	}
+	if s.config.Alien.TrantorBlock != nil && header.Number.Cmp(s.config.Alien.TrantorBlock) == 0 && len(snap.Punished) > 0 {
+		s.t.Fatalf("node %s, block %d: punish scores not reset at trantor", node.name, header.Number)
+	}
+	// The snapshot must not depend on the import history of the node
+	fresh := New(s.config.Alien, ethdb.NewMemDatabase())
+	fresh.signatures = node.engine.signatures
+	if err := fresh.ApplyGenesis(node.chain, s.genesis.Hash()); err != nil {
+		s.t.Fatalf("node %s: failed to apply genesis: %v", node.name, err)
+	}
+	replayed, err := fresh.snapshot(node.chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		s.t.Fatalf("node %s, block %d: failed to replay snapshot: %v", node.name, header.Number, err)
+	}
+	have, _ := snap.commitHash()
+	want, _ := replayed.commitHash()
+	if have != want {
+		s.t.Fatalf("node %s, block %d: snapshot differs from replay", node.name, header.Number)
+	}
+}
+
+// checkConverged checks that all online nodes share the same head.
+func (s *simulator) checkConverged() *types.Header {
+	head := s.head().chain.CurrentHeader()
+	for _, node := range s.nodes {
+		if node.online && node.chain.CurrentHeader().Hash() != head.Hash() {
+			s.t.Fatalf("node %s: head mismatch: have %d [%x], want %d [%x]", node.name,
+				node.chain.CurrentHeader().Number, node.chain.CurrentHeader().Hash(), head.Number, head.Hash())
+		}
+	}
+	return head
+}
+
+// Tests that signers missing their slots are punished, across the Trantor and
+// Terminus forks.
+func TestSimulator_MissedSlots(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C", "D", "E"}, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(12)
+		config.TerminusBlock = big.NewInt(30)
+	})
+	sim.run(20)
+
+	sim.setOnline("C", false)
+	sim.run(10)
+	if sim.missed[sim.node("C").addr] == 0 {
+		t.Fatalf("offline signer never in turn")
+	}
+	snap := sim.snapshot(sim.head(), sim.head().chain.CurrentHeader())
+	if snap.Punished[sim.node("C").addr] == 0 {
+		t.Errorf("offline signer not punished")
+	}
+	sim.setOnline("C", true)
+	sim.run(20)
+
+	head := sim.checkConverged()
+	if want := uint64(50 - sim.missed[sim.node("C").addr]); head.Number.Uint64() != want {
+		t.Errorf("head mismatch: have %d, want %d", head.Number, want)
+	}
+	for addr, misses := range sim.missed {
+		if addr != sim.node("C").addr && misses > 0 {
+			t.Errorf("online signer %x missed %d slots", addr, misses)
+		}
+	}
+}
+
+// Tests that after BackupSeal the slots of an offline signer are sealed by its
+// backup, punishing the offline signer without slowing the chain, and that an
+// in-turn block outweighs the backup block of the same slot.
+func TestSimulator_BackupSeal(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C", "D", "E"}, func(config *params.AlienConfig) {
+		config.Period = 2
+		config.TrantorBlock = big.NewInt(1)
+		config.BackupSealBlock = big.NewInt(12)
+		config.BackupSealDelay = 1
+	})
+	sim.run(20)
+
+	offline := sim.node("C")
+	sim.setOnline("C", false)
+	sim.run(10)
+	if sim.missed[offline.addr] == 0 {
+		t.Fatalf("offline signer never in turn")
+	}
+	head := sim.checkConverged()
+	if head.Number.Uint64() != 30 {
+		t.Errorf("head mismatch with backup sealing: have %d, want 30", head.Number)
+	}
+	snap := sim.snapshot(sim.head(), head)
+	if snap.Punished[offline.addr] == 0 {
+		t.Errorf("offline signer not punished")
+	}
+	var backups int
+	for number := uint64(21); number <= 30; number++ {
+		header := sim.head().chain.GetHeaderByNumber(number)
+		if sim.head().engine.isBackupSeal(header) {
+			backups++
+			if header.Coinbase == offline.addr {
+				t.Errorf("block %d: backup sealed by the offline signer", number)
+			}
+		}
+	}
+	if backups != sim.missed[offline.addr] {
+		t.Errorf("backup block count mismatch: have %d, want %d", backups, sim.missed[offline.addr])
+	}
+	sim.setOnline("C", true)
+	sim.run(10)
+	head = sim.checkConverged()
+
+	// Race the in-turn signer of the next slot against its backup
+	snap = sim.snapshot(sim.head(), head)
+	now := sim.clock
+	if now < head.Time.Uint64()+sim.config.Alien.Period {
+		now = head.Time.Uint64() + sim.config.Alien.Period
+	}
+	inturn, _ := snap.slotSigner(now)
+	backup, _ := snap.backupSigner(now)
+	if now+sim.node("A").engine.backupDelay() >= snap.slotStart(now)+sim.config.Alien.Period {
+		t.Fatalf("no room for a backup block in the slot")
+	}
+	inturnBlock := sim.seal(sim.signers[inturn], now)
+	backupBlock := sim.seal(sim.signers[backup], snap.slotStart(now)+sim.node("A").engine.backupDelay())
+	if !sim.node("A").engine.isBackupSeal(backupBlock.Header()) || inturnBlock.Difficulty().Cmp(diffInTurn) != 0 {
+		t.Fatalf("seal difficulty mismatch: in-turn %v, backup %v", inturnBlock.Difficulty(), backupBlock.Difficulty())
+	}
+	for _, node := range sim.nodes {
+		if _, err := node.chain.InsertChain(types.Blocks{backupBlock}); err != nil {
+			t.Fatalf("node %s: failed to import backup block: %v", node.name, err)
+		}
+	}
+	for _, node := range sim.nodes {
+		if _, err := node.chain.InsertChain(types.Blocks{inturnBlock}); err != nil {
+			t.Fatalf("node %s: failed to import in-turn block: %v", node.name, err)
+		}
+		if node.chain.CurrentHeader().Hash() != inturnBlock.Hash() {
+			t.Errorf("node %s: backup block preferred over in-turn block", node.name)
+		}
+	}
+	sim.clock = now + sim.config.Alien.Period
+	sim.run(10)
+	sim.checkConverged()
+}
+
+// Tests that a network partition forks the chain and healing it reorgs the
+// minority on the majority chain.
+func TestSimulator_Reorg(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C", "D", "E"}, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(12)
+	fork := sim.checkConverged()
+
+	sim.partition([]string{"A", "B", "C"}, []string{"D", "E"})
+	sim.run(15)
+	majority := sim.node("A").chain.CurrentHeader()
+	minority := sim.node("D").chain.CurrentHeader()
+	if majority.Number.Cmp(minority.Number) <= 0 {
+		t.Fatalf("minority outgrew majority: %d <= %d", majority.Number, minority.Number)
+	}
+	if minority.Number.Cmp(fork.Number) <= 0 {
+		t.Fatalf("minority sealed no blocks")
+	}
+	sim.heal()
+	if head := sim.checkConverged(); head.Hash() != majority.Hash() {
+		t.Fatalf("head mismatch after heal: have %d [%x], want %d [%x]", head.Number, head.Hash(), majority.Number, majority.Hash())
+	}
+	if sim.node("D").chain.GetBlockByNumber(minority.Number.Uint64()).Hash() == minority.Hash() {
+		t.Fatalf("minority chain not reorged")
+	}
+	sim.run(15)
+	sim.checkConverged()
+}
+
+// Tests that a side chain proposal passes with the declares of all signers, and
+// that the side chain gets confirmed by the coinbases set by the signers.
+func TestSimulator_SideChainConfirm(t *testing.T) {
+	names := []string{"A", "B", "C", "D", "E"}
+	sim := newSimulator(t, names, func(config *params.AlienConfig) {
+		config.TrantorBlock = big.NewInt(1)
+	})
+	sim.run(6)
+
+	// Propose the side chain and let every signer agree
+	scHash := common.HexToHash("0x3210000000000000000000000000000000000000000000000000000000000000")
+	proposer := sim.node("A")
+	proposal := sim.sendTx(proposer.key, proposer.addr, nil, fmt.Sprintf("ufo:1:event:proposal:proposal_type:%d:sccount:1:screward:50:schash:%s:vlcnt:%d", proposalTypeSideChainAdd, scHash.Hex(), minValidationLoopCnt))
+	sim.run(1)
+	for _, node := range sim.nodes {
+		sim.sendTx(node.key, node.addr, nil, fmt.Sprintf("ufo:1:event:declare:hash:%s:decision:yes", proposal.Hash().Hex()))
+	}
+	sim.run(minValidationLoopCnt*len(names) + 2)
+
+	snap := sim.snapshot(sim.head(), sim.head().chain.CurrentHeader())
+	if !snap.isSideChainExist(scHash) {
+		t.Fatalf("side chain proposal not passed")
+	}
+	// Set the side chain coinbases and confirm the first side chain loop
+	var loopInfo []string
+	for i, name := range names {
+		coinbase := crypto.PubkeyToAddress(simKey("sc-" + name).PublicKey)
+		sim.sendTx(sim.node(name).key, coinbase, minSCSetCoinbaseValue, fmt.Sprintf("ufo:1:sc:setcb:%s", scHash.Hex()))
+		loopInfo = append(loopInfo, fmt.Sprint(i+1), coinbase.Hex())
+	}
+	sim.run(1)
+
+	confirmed := uint64(len(names))
+	from := sim.head().chain.CurrentHeader()
+	for _, name := range names {
+		sim.sendTx(simKey("sc-"+name), common.Address{}, nil, fmt.Sprintf("ufo:1:sc:confirm:%s:%d:%d:%s:", scHash.Hex(), confirmed, simGenesisTime+confirmed, strings.Join(loopInfo, "#")))
+	}
+	sim.run(2 * len(names))
+
+	snap = sim.snapshot(sim.head(), sim.head().chain.CurrentHeader())
+	if have := snap.SCRecordMap[scHash].LastConfirmedNumber; have != confirmed {
+		t.Errorf("side chain confirmed number mismatch: have %d, want %d", have, confirmed)
+	}
+	events := collectEvents(t, sim.head(), from, sim.head().chain.CurrentHeader())[topicSideChainConfirmed]
+	if len(events) != 1 {
+		t.Fatalf("side chain confirmed event count mismatch: have %d, want 1", len(events))
+	}
+	if ev := events[0].(*SideChainConfirmedEvent); ev.SideChain != scHash || ev.ConfirmedNumber != confirmed || ev.Previous != 0 {
+		t.Errorf("side chain confirmed event mismatch: have %+v", ev)
+	}
+	sim.checkConverged()
+}
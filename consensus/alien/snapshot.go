@@ -14,16 +14,16 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package alien implements the delegated-proof-of-stake consensus engine.
-
 package alien
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/core/types"
 	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/metrics"
 	"github.com/TTCECO/gttc/params"
 	"github.com/hashicorp/golang-lru"
 	"math/big"
@@ -120,31 +120,36 @@ type CCNotice struct {
 type Snapshot struct {
 	config   *params.AlienConfig // Consensus engine parameters to fine tune behavior
 	sigcache *lru.ARCCache       // Cache of recent block signatures to speed up ecrecover
+	punish   *punishMetrics      // Punish metrics reporter of the engine, nil to not report
 	LCRS     uint64              // Loop count to recreate signers from top tally
 
-	Period          uint64                                            `json:"period"`            // Period of seal each block
-	Number          uint64                                            `json:"number"`            // Block number where the snapshot was created
-	ConfirmedNumber uint64                                            `json:"confirmedNumber"`   // Block number confirmed when the snapshot was created
-	Hash            common.Hash                                       `json:"hash"`              // Block hash where the snapshot was created
-	HistoryHash     []common.Hash                                     `json:"historyHash"`       // Block hash list for two recent loop
-	Signers         []*common.Address                                 `json:"signers"`           // Signers queue in current header
-	Votes           map[common.Address]*Vote                          `json:"votes"`             // All validate votes from genesis block
-	Tally           map[common.Address]*big.Int                       `json:"tally"`             // Stake for each candidate address
-	Voters          map[common.Address]*big.Int                       `json:"voters"`            // Block number for each voter address
-	Candidates      map[common.Address]uint64                         `json:"candidates"`        // Candidates for Signers (0- adding procedure 1- normal 2- removing procedure)
-	Punished        map[common.Address]uint64                         `json:"punished"`          // The signer be punished count cause of missing seal
-	Confirmations   map[uint64][]*common.Address                      `json:"confirms"`          // The signer confirm given block number
-	Proposals       map[common.Hash]*Proposal                         `json:"proposals"`         // The Proposals going or success (failed proposal will be removed)
-	HeaderTime      uint64                                            `json:"headerTime"`        // Time of the current header
-	LoopStartTime   uint64                                            `json:"loopStartTime"`     // Start Time of the current loop
-	ProposalRefund  map[uint64]map[common.Address]*big.Int            `json:"proposalRefund"`    // Refund proposal deposit
-	SCCoinbase      map[common.Address]map[common.Hash]common.Address `json:"sideChainCoinbase"` // main chain set Coinbase of side chain setting
-	SCRecordMap     map[common.Hash]*SCRecord                         `json:"sideChainRecord"`   // main chain record Confirmation of side chain setting
-	SCRewardMap     map[common.Hash]*SCReward                         `json:"sideChainReward"`   // main chain record Side Chain Reward
-	SCNoticeMap     map[common.Hash]*CCNotice                         `json:"sideChainNotice"`   // main chain record Notification to side chain
-	LocalNotice     *CCNotice                                         `json:"localNotice"`       // side chain record Notification
-	MinerReward     uint64                                            `json:"minerReward"`       // miner reward per thousand
-	MinVB           *big.Int                                          `json:"minVoterBalance"`   // min voter balance
+	Period          uint64                                            `json:"period"`                    // Period of seal each block
+	Number          uint64                                            `json:"number"`                    // Block number where the snapshot was created
+	ConfirmedNumber uint64                                            `json:"confirmedNumber"`           // Block number confirmed when the snapshot was created
+	Hash            common.Hash                                       `json:"hash"`                      // Block hash where the snapshot was created
+	HistoryHash     []common.Hash                                     `json:"historyHash"`               // Block hash list for two recent loop
+	Signers         []*common.Address                                 `json:"signers"`                   // Signers queue in current header
+	Votes           map[common.Address]*Vote                          `json:"votes"`                     // All validate votes from genesis block
+	Tally           map[common.Address]*big.Int                       `json:"tally"`                     // Stake for each candidate address
+	Voters          map[common.Address]*big.Int                       `json:"voters"`                    // Block number for each voter address
+	Candidates      map[common.Address]uint64                         `json:"candidates"`                // Candidates for Signers (0- adding procedure 1- normal 2- removing procedure)
+	Punished        map[common.Address]uint64                         `json:"punished"`                  // The signer be punished count cause of missing seal
+	Confirmations   map[uint64][]*common.Address                      `json:"confirms"`                  // The signer confirm given block number
+	Proposals       map[common.Hash]*Proposal                         `json:"proposals"`                 // The Proposals going or success (failed proposal will be removed)
+	HeaderTime      uint64                                            `json:"headerTime"`                // Time of the current header
+	LoopStartTime   uint64                                            `json:"loopStartTime"`             // Start Time of the current loop
+	ProposalRefund  map[uint64]map[common.Address]*big.Int            `json:"proposalRefund"`            // Refund proposal deposit
+	SCCoinbase      map[common.Address]map[common.Hash]common.Address `json:"sideChainCoinbase"`         // main chain set Coinbase of side chain setting
+	SCRecordMap     map[common.Hash]*SCRecord                         `json:"sideChainRecord"`           // main chain record Confirmation of side chain setting
+	SCRewardMap     map[common.Hash]*SCReward                         `json:"sideChainReward"`           // main chain record Side Chain Reward
+	SCNoticeMap     map[common.Hash]*CCNotice                         `json:"sideChainNotice"`           // main chain record Notification to side chain
+	LocalNotice     *CCNotice                                         `json:"localNotice"`               // side chain record Notification
+	MinerReward     uint64                                            `json:"minerReward"`               // miner reward per thousand
+	MinVB           *big.Int                                          `json:"minVoterBalance"`           // min voter balance
+	ProposalHistory []*ProposalResult                                 `json:"proposalHistory,omitempty"` // Proposals decided after ProposalLifecycle, the latest ones only
+
+	decided []*ProposalResult // Proposals decided by the last apply, never stored
+	expired []*Vote           // Votes expired by the last apply, never stored
 }
 
 // newSnapshot creates a new snapshot with the specified startup parameters. only ever use if for
@@ -247,6 +252,7 @@ func (s *Snapshot) copy() *Snapshot {
 	cpy := &Snapshot{
 		config:          s.config,
 		sigcache:        s.sigcache,
+		punish:          s.punish,
 		LCRS:            s.LCRS,
 		Period:          s.Period,
 		Number:          s.Number,
@@ -272,8 +278,9 @@ func (s *Snapshot) copy() *Snapshot {
 		LocalNotice:    &CCNotice{CurrentCharging: make(map[common.Hash]GasCharging), ConfirmReceived: make(map[common.Hash]NoticeCR)},
 		ProposalRefund: make(map[uint64]map[common.Address]*big.Int),
 
-		MinerReward: s.MinerReward,
-		MinVB:       nil,
+		MinerReward:     s.MinerReward,
+		MinVB:           nil,
+		ProposalHistory: append([]*ProposalResult(nil), s.ProposalHistory...),
 	}
 	copy(cpy.HistoryHash, s.HistoryHash)
 	copy(cpy.Signers, s.Signers)
@@ -450,8 +457,13 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 		// deal proposals
 		snap.updateSnapshotByProposals(headerExtra.CurrentBlockProposals, header.Number)
 
-		// deal declares
+		// deal declares, a declarer has at most one declaration or abstention on a
+		// proposal in a block after ProposalQuorum so the order of the lists is moot
 		snap.updateSnapshotByDeclares(headerExtra.CurrentBlockDeclares, header.Number)
+		snap.updateSnapshotByDeclares(headerExtra.CurrentBlockAbstains, header.Number)
+
+		// deal proposal withdrawals
+		snap.updateSnapshotByWithdraws(headerExtra.ProposalWithdraws, header.Number)
 
 		// deal trantor upgrade
 		if snap.Period == 0 {
@@ -827,9 +839,12 @@ func (s *Snapshot) updateSnapshotByDeclares(declares []Declare, headerNumber *bi
 			}
 			// check if this signer already declare on this proposal
 			alreadyDeclare := false
-			for _, v := range proposal.Declares {
+			for i, v := range proposal.Declares {
 				if v.Declarer.Str() == declare.Declarer.Str() {
-					// this declarer already declare for this proposal
+					// this declarer already declare for this proposal, the latest declaration wins after ProposalLifecycle
+					if s.config.IsProposalLifecycle(headerNumber) {
+						proposal.Declares[i] = &Declare{declare.ProposalHash, declare.Declarer, declare.Decision, declare.Abstain}
+					}
 					alreadyDeclare = true
 					break
 				}
@@ -839,7 +854,7 @@ func (s *Snapshot) updateSnapshotByDeclares(declares []Declare, headerNumber *bi
 			}
 			// add declare to proposal
 			s.Proposals[declare.ProposalHash].Declares = append(s.Proposals[declare.ProposalHash].Declares,
-				&Declare{declare.ProposalHash, declare.Declarer, declare.Decision})
+				&Declare{declare.ProposalHash, declare.Declarer, declare.Decision, declare.Abstain})
 
 		}
 	}
@@ -852,7 +867,15 @@ func (s *Snapshot) calculateProposalResult(headerNumber *big.Int) {
 		delete(s.ProposalRefund, expiredHeaderNumber)
 	}
 
-	for hashKey, proposal := range s.Proposals {
+	// decide the proposals in hash order, so that all nodes apply and record them alike
+	hashKeys := make([]common.Hash, 0, len(s.Proposals))
+	for hashKey := range s.Proposals {
+		hashKeys = append(hashKeys, hashKey)
+	}
+	sort.Slice(hashKeys, func(i, j int) bool { return bytes.Compare(hashKeys[i][:], hashKeys[j][:]) < 0 })
+
+	for _, hashKey := range hashKeys {
+		proposal := s.Proposals[hashKey]
 		// the result will be calculate at receiverdNumber + vlcnt + 1
 		if proposal.ReceivedNumber.Uint64()+proposal.ValidationLoopCnt*s.config.MaxSignerCount+1 == headerNumber.Uint64() {
 			//return deposit for proposal
@@ -881,7 +904,21 @@ func (s *Snapshot) calculateProposalResult(headerNumber *big.Int) {
 					}
 				}
 			}
-			if yesDeclareStake.Cmp(judegmentStake) > 0 {
+			passed := yesDeclareStake.Cmp(judegmentStake) > 0
+			state := proposalStateRejected
+			if passed {
+				state = proposalStatePassed
+			} else if len(proposal.Declares) == 0 && s.config.IsProposalLifecycle(headerNumber) {
+				state = proposalStateExpired
+			}
+			// the thresholds of the proposal type replace the 2/3 rule after ProposalQuorum
+			var tally *ProposalTally
+			if s.config.IsProposalQuorum(headerNumber) {
+				tally = s.tallyProposal(proposal)
+				passed, state = tally.State() == proposalStatePassed, tally.State()
+			}
+			s.decideProposal(&ProposalResult{Number: headerNumber.Uint64(), Proposal: proposal.copy(), Passed: passed, State: state, Tally: tally})
+			if passed {
 				// process add candidate
 				switch proposal.ProposalType {
 				case proposalTypeCandidateAdd:
@@ -981,6 +1018,7 @@ func (s *Snapshot) updateSnapshotForExpired(headerNumber *big.Int) {
 	// remove expiredVotes only enough voters left
 	if uint64(len(s.Voters)-len(expiredVotes)) >= s.config.MaxSignerCount {
 		for _, expiredVote := range expiredVotes {
+			s.expired = append(s.expired, expiredVote)
 			if _, ok := s.Tally[expiredVote.Candidate]; ok {
 				s.Tally[expiredVote.Candidate].Sub(s.Tally[expiredVote.Candidate], expiredVote.Stake)
 				if s.Tally[expiredVote.Candidate].Cmp(big.NewInt(0)) == 0 {
@@ -1081,6 +1119,14 @@ func (s *Snapshot) updateSnapshotForPunish(signerMissing []common.Address, heade
 			}
 		}
 	*/
+	// remember the signers whose punish score may change for the metrics
+	var touched []common.Address
+	if s.punish != nil && metrics.Enabled {
+		touched = append([]common.Address{coinbase}, signerMissing...)
+		for signerEach := range s.Punished {
+			touched = append(touched, signerEach)
+		}
+	}
 	// punish the missing signer
 	for _, signerEach := range signerMissing {
 		if _, ok := s.Punished[signerEach]; ok {
@@ -1114,7 +1160,9 @@ func (s *Snapshot) updateSnapshotForPunish(signerMissing []common.Address, heade
 	if s.config.IsTrantor(headerNumber) && !s.config.IsTrantor(new(big.Int).Sub(headerNumber, big.NewInt(1))) {
 		s.Punished = make(map[common.Address]uint64)
 	}
-
+	if touched != nil {
+		s.punish.report(headerNumber, signerMissing, s.Punished, touched)
+	}
 }
 
 // inturn returns if a signer at a given block height is in-turn or not.
@@ -1137,6 +1185,16 @@ func (s *Snapshot) isSideChainExist(hash common.Hash) bool {
 	return false
 }
 
+// check if address belong to the signers of the current loop
+func (s *Snapshot) isSigner(address common.Address) bool {
+	for _, signer := range s.Signers {
+		if *signer == address {
+			return true
+		}
+	}
+	return false
+}
+
 // check if address belong to voter
 func (s *Snapshot) isVoter(address common.Address) bool {
 	if _, ok := s.Voters[address]; ok {
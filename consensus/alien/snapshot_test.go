@@ -14,8 +14,6 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package alien implements the delegated-proof-of-stake consensus engine.
-
 package alien
 
 import (
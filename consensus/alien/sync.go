@@ -0,0 +1,210 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/log"
+)
+
+var (
+	// errSnapshotCommitMissing is returned if a header is not expected to carry
+	// a commitment to its parent snapshot.
+	errSnapshotCommitMissing = errors.New("snapshot commitment missing")
+
+	// errInvalidSnapshotCommit is returned if the snapshot commitment in a header
+	// doesn't match the snapshot of its parent.
+	errInvalidSnapshotCommit = errors.New("invalid snapshot commitment")
+
+	// errSyncSnapshotMismatch is returned if a header or snapshot doesn't belong
+	// to the chain of the snapshot imported during fast sync.
+	errSyncSnapshotMismatch = errors.New("mismatched sync snapshot")
+
+	// errSyncSnapshotUnconfirmed is returned if the headers following an imported
+	// snapshot don't tie it to a trusted block.
+	errSyncSnapshotUnconfirmed = errors.New("unconfirmed sync snapshot")
+)
+
+// syncPivotKey is the database key of the hash of the imported fast sync pivot.
+var syncPivotKey = []byte("alien-sync-pivot")
+
+// verifySnapshotCommit checks the commitment of a header to its parent snapshot.
+func (a *Alien) verifySnapshotCommit(header *types.Header, parentSnap *Snapshot) error {
+	if !a.config.IsSnapshotCommit(header.Number) || header.Number.Uint64() <= 1 {
+		return nil
+	}
+	headerExtra := HeaderExtra{}
+	if err := decodeHeaderExtra(a.config, header.Number, header.Extra[extraVanity:len(header.Extra)-extraSeal], &headerExtra); err != nil {
+		return err
+	}
+	hash, err := parentSnap.commitHash()
+	if err != nil {
+		return err
+	}
+	if hash != headerExtra.SnapshotHash {
+		return errInvalidSnapshotCommit
+	}
+	return nil
+}
+
+// pivotSnapshot returns the snapshot imported during fast sync, as long as the
+// local header chain has not reached it yet.
+func (a *Alien) pivotSnapshot(chain consensus.ChainReader) *Snapshot {
+	a.lock.RLock()
+	snap := a.syncSnap
+	a.lock.RUnlock()
+
+	if snap == nil || chain.CurrentHeader().Number.Uint64() >= snap.Number {
+		return nil
+	}
+	return snap
+}
+
+// ExportSnapshot implements consensus.SnapshotSyncer, returning the snapshot at
+// the given block encoded for a fast syncing peer.
+func (a *Alien) ExportSnapshot(chain consensus.ChainReader, hash common.Hash, number uint64) ([]byte, error) {
+	// The genesis snapshot is never committed to, nor needed by peers
+	if number == 0 || chain.GetHeader(hash, number) == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := a.snapshot(chain, number, hash, nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snap.copy())
+}
+
+// ImportSnapshot implements consensus.SnapshotSyncer, verifying the snapshot of
+// the parent of the first given header against the header's commitment. As the
+// signers allowed to seal that header are only known from the snapshot itself,
+// the snapshot is only adopted once the headers following it tie it to a trusted
+// block, see confirmSnapshot. On success the snapshot is stored, and the headers
+// up to it are only checked against it by hash until the local header chain
+// reaches it.
+func (a *Alien) ImportSnapshot(chain consensus.ChainReader, headers []*types.Header, blob []byte) error {
+	if len(headers) == 0 {
+		return errSnapshotCommitMissing
+	}
+	header := headers[0]
+
+	number := header.Number.Uint64()
+	if chain.Config().Alien.SideChain || number <= 1 || !a.config.IsSnapshotCommit(header.Number) {
+		return errSnapshotCommitMissing
+	}
+	snap := new(Snapshot)
+	if err := json.Unmarshal(blob, snap); err != nil {
+		return err
+	}
+	snap.config = a.config
+	snap.sigcache = a.signatures
+	snap.punish = &a.punish
+
+	if snap.Number != number-1 || snap.Hash != header.ParentHash {
+		return errSyncSnapshotMismatch
+	}
+	if err := a.confirmSnapshot(snap, headers); err != nil {
+		return err
+	}
+	if err := snap.store(a.db); err != nil {
+		return err
+	}
+	if err := a.db.Put(syncPivotKey, snap.Hash[:]); err != nil {
+		return err
+	}
+	a.recents.Add(snap.Hash, snap)
+
+	a.lock.Lock()
+	a.syncSnap, a.syncPivot = snap, snap.Hash
+	a.lock.Unlock()
+
+	log.Info("Imported voting snapshot", "number", snap.Number, "hash", snap.Hash)
+	return nil
+}
+
+// confirmSnapshot checks that the headers following the block of an imported
+// snapshot tie it to something trusted. Every header must commit to the snapshot
+// derived from the imported one and be sealed by one of its signers, until the
+// headers either reach the trusted checkpoint, or have been sealed by more than
+// two thirds of the distinct signers of the imported snapshot.
+func (a *Alien) confirmSnapshot(snap *Snapshot, headers []*types.Header) error {
+	a.lock.RLock()
+	checkpoint := a.checkpoint
+	a.lock.RUnlock()
+
+	distinct := make(map[common.Address]bool)
+	for _, signer := range snap.Signers {
+		distinct[*signer] = true
+	}
+	var (
+		required = len(distinct)*2/3 + 1
+		sealers  = make(map[common.Address]bool)
+		parent   = snap
+	)
+	for _, header := range headers {
+		if header.Number.Uint64() != parent.Number+1 || header.ParentHash != parent.Hash {
+			return errSyncSnapshotMismatch
+		}
+		if len(header.Extra) < extraVanity+extraSeal {
+			return errMissingSignature
+		}
+		if err := a.verifySnapshotCommit(header, parent); err != nil {
+			return err
+		}
+		if checkpoint != nil && checkpoint.Number == header.Number.Uint64() {
+			if header.Hash() != checkpoint.Hash {
+				return errCheckpointMismatch
+			}
+			return nil
+		}
+		signer, err := ecrecover(header, a.signatures)
+		if err != nil {
+			return err
+		}
+		if !parent.isSigner(signer) {
+			return errUnauthorized
+		}
+		sealers[signer] = true
+		if len(sealers) >= required {
+			return nil
+		}
+		if parent, err = parent.apply([]*types.Header{header}); err != nil {
+			return err
+		}
+	}
+	return errSyncSnapshotUnconfirmed
+}
+
+// storedSnapshot returns whether the snapshot of the given block may be found on
+// disk: the ones stored at checkpoint intervals, at the trusted checkpoint and at
+// the imported fast sync pivot.
+func (a *Alien) storedSnapshot(number uint64, hash common.Hash) bool {
+	if number%checkpointInterval == 0 {
+		return true
+	}
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if a.checkpoint != nil && a.checkpoint.Hash == hash {
+		return true
+	}
+	return a.syncPivot == hash
+}
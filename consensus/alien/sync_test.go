@@ -0,0 +1,136 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+func TestHeaderExtraSnapshotCommit(t *testing.T) {
+	config := &params.AlienConfig{SnapshotCommitBlock: big.NewInt(10)}
+	extra := HeaderExtra{LoopStartTime: 100, ConfirmedBlockNumber: 8, SnapshotHash: common.HexToHash("0x01")}
+
+	// Before the fork the commitment is not part of the encoding
+	legacy, err := encodeHeaderExtra(config, big.NewInt(9), extra)
+	if err != nil {
+		t.Fatalf("failed to encode legacy extra: %v", err)
+	}
+	noCommit := extra
+	noCommit.SnapshotHash = common.Hash{}
+	if enc, _ := encodeHeaderExtra(config, big.NewInt(9), noCommit); !bytes.Equal(enc, legacy) {
+		t.Errorf("legacy encoding depends on snapshot hash")
+	}
+	// After the fork it round trips
+	committed, err := encodeHeaderExtra(config, big.NewInt(10), extra)
+	if err != nil {
+		t.Fatalf("failed to encode committed extra: %v", err)
+	}
+	var decoded HeaderExtra
+	if err := decodeHeaderExtra(config, big.NewInt(10), committed, &decoded); err != nil {
+		t.Fatalf("failed to decode committed extra: %v", err)
+	}
+	if decoded.SnapshotHash != extra.SnapshotHash || decoded.LoopStartTime != extra.LoopStartTime || decoded.ConfirmedBlockNumber != extra.ConfirmedBlockNumber {
+		t.Errorf("committed extra mismatch: have %+v, want %+v", decoded, extra)
+	}
+	if err := decodeHeaderExtra(config, big.NewInt(10), legacy, &decoded); err == nil {
+		t.Errorf("legacy extra accepted after the fork")
+	}
+}
+
+func TestAlien_ImportSnapshot(t *testing.T) {
+	sim := newSimulator(t, []string{"A", "B", "C"}, func(config *params.AlienConfig) {
+		config.SnapshotCommitBlock = big.NewInt(2)
+	})
+	sim.run(12)
+
+	// Export the snapshot at the pivot along with the headers following it
+	var (
+		source  = sim.node("A")
+		pivot   = source.chain.GetHeaderByNumber(5)
+		headers []*types.Header
+	)
+	for number := uint64(6); number <= 12; number++ {
+		headers = append(headers, source.chain.GetHeaderByNumber(number))
+	}
+	blob, err := source.engine.ExportSnapshot(source.chain, pivot.Hash(), 5)
+	if err != nil {
+		t.Fatalf("failed to export snapshot: %v", err)
+	}
+	snap := new(Snapshot)
+	if err := json.Unmarshal(blob, snap); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	snap.config = source.engine.config
+
+	tampered := snap.copy()
+	tampered.Tally[source.addr] = new(big.Int).Add(tampered.Tally[source.addr], big.NewInt(1))
+	tamperedBlob, _ := json.Marshal(tampered)
+
+	tests := []struct {
+		headers    []*types.Header
+		blob       []byte
+		checkpoint *params.AlienCheckpoint
+		err        error
+	}{
+		{headers: headers, blob: tamperedBlob, err: errInvalidSnapshotCommit},
+		{headers: headers[1:], blob: blob, err: errSyncSnapshotMismatch},
+		{headers: []*types.Header{headers[0], headers[2]}, blob: blob, err: errSyncSnapshotMismatch},
+		// A single signer building on the snapshot doesn't confirm it
+		{headers: headers[:1], blob: blob, err: errSyncSnapshotUnconfirmed},
+		{headers: headers[:1], blob: blob, checkpoint: &params.AlienCheckpoint{Number: 6}, err: errCheckpointMismatch},
+		// Reaching the trusted checkpoint or enough distinct signers does
+		{headers: headers[:1], blob: blob, checkpoint: &params.AlienCheckpoint{Number: 6, Hash: headers[0].Hash()}},
+		{headers: headers, blob: blob},
+	}
+	for i, tt := range tests {
+		db := ethdb.NewMemDatabase()
+		alien := New(sim.config.Alien, db)
+		if tt.checkpoint != nil {
+			alien.SetCheckpoint(tt.checkpoint)
+		}
+		if err := alien.ImportSnapshot(source.chain, tt.headers, tt.blob); err != tt.err {
+			t.Errorf("test %d: error mismatch, have %v, want %v", i, err, tt.err)
+		}
+		if tt.err != nil {
+			if alien.storedSnapshot(5, pivot.Hash()) {
+				t.Errorf("test %d: rejected snapshot recorded as stored", i)
+			}
+			continue
+		}
+		// The imported snapshot must be found on disk, even after a restart
+		restarted := New(sim.config.Alien, db)
+		if !restarted.storedSnapshot(5, pivot.Hash()) {
+			t.Errorf("test %d: imported pivot not recorded", i)
+		}
+		loaded, err := loadSnapshot(restarted.config, restarted.signatures, db, pivot.Hash())
+		if err != nil {
+			t.Fatalf("test %d: imported snapshot not stored: %v", i, err)
+		}
+		want, _ := snap.commitHash()
+		if hash, _ := loaded.commitHash(); hash != want {
+			t.Errorf("test %d: stored snapshot hash mismatch: have %x, want %x", i, hash, want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/state"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+// testerHeaderChain implements consensus.ChainReader on top of a slice of
+// canonical headers.
+type testerHeaderChain struct {
+	config  *params.ChainConfig
+	headers []*types.Header
+	hashes  map[common.Hash]*types.Header
+}
+
+func newTesterHeaderChain(config *params.ChainConfig, genesis *types.Header) *testerHeaderChain {
+	chain := &testerHeaderChain{config: config, hashes: make(map[common.Hash]*types.Header)}
+	chain.insert(genesis)
+	return chain
+}
+
+func (c *testerHeaderChain) insert(header *types.Header) {
+	c.headers = append(c.headers, header)
+	c.hashes[header.Hash()] = header
+}
+
+func (c *testerHeaderChain) Config() *params.ChainConfig               { return c.config }
+func (c *testerHeaderChain) CurrentHeader() *types.Header              { return c.headers[len(c.headers)-1] }
+func (c *testerHeaderChain) GetBlock(common.Hash, uint64) *types.Block { return nil }
+func (c *testerHeaderChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return c.hashes[hash]
+}
+func (c *testerHeaderChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.hashes[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+func (c *testerHeaderChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number < uint64(len(c.headers)) {
+		return c.headers[number]
+	}
+	return nil
+}
+
+// newTesterHeaders generates a chain of empty headers sealed in turn by the
+// given number of self voting signers. It returns the chain config, the genesis
+// snapshot and the generated chain.
+func newTesterHeaders(t testing.TB, signers int, blocks int) (*params.ChainConfig, *Snapshot, *testerHeaderChain) {
+	accounts := newTesterAccountPool()
+	names := make(map[common.Address]string)
+
+	period := uint64(1)
+	genesisTime := uint64(time.Now().Unix()) - uint64(blocks)*period - 10
+	alienConfig := &params.AlienConfig{
+		Period:           period,
+		Epoch:            30000,
+		MaxSignerCount:   uint64(signers),
+		MinVoterBalance:  big.NewInt(100),
+		GenesisTimestamp: genesisTime,
+	}
+	db := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	for i := 0; i < signers; i++ {
+		name := string(rune('A' + i))
+		addr := accounts.address(name)
+		names[addr] = name
+		alienConfig.SelfVoteSigners = append(alienConfig.SelfVoteSigners, common.UnprefixedAddress(addr))
+		statedb.AddBalance(addr, big.NewInt(1000))
+	}
+	config := *params.AllAlienProtocolChanges
+	config.Alien = alienConfig
+
+	chain := newTesterHeaderChain(&config, &types.Header{
+		Number:     big.NewInt(0),
+		Time:       new(big.Int).SetUint64(genesisTime),
+		Extra:      make([]byte, extraVanity+extraSeal),
+		UncleHash:  uncleHash,
+		Difficulty: big.NewInt(1),
+		GasLimit:   params.GenesisGasLimit,
+	})
+	engine := New(alienConfig, db)
+	for i := 1; i <= blocks; i++ {
+		parent := chain.CurrentHeader()
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(int64(i)),
+			GasLimit:   parent.GasLimit,
+			Time:       new(big.Int).SetUint64(genesisTime + uint64(i-1)*period),
+			Extra:      make([]byte, extraVanity),
+		}
+		// Pick the in-turn signer from the parent snapshot
+		header.Coinbase = common.Address(alienConfig.SelfVoteSigners[0])
+		if i > 1 {
+			snap, err := engine.snapshot(chain, parent.Number.Uint64(), parent.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+			if err != nil {
+				t.Fatalf("block %d: failed to retrieve snapshot: %v", i, err)
+			}
+			header.Coinbase = *snap.Signers[(header.Time.Uint64()-snap.LoopStartTime)/period%uint64(len(snap.Signers))]
+		}
+		block, err := engine.Finalize(chain, header, statedb, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("block %d: failed to finalize: %v", i, err)
+		}
+		header = block.Header()
+		accounts.sign(header, names[header.Coinbase])
+		chain.insert(header)
+	}
+	genesis, err := loadSnapshot(engine.config, engine.signatures, db, chain.headers[0].Hash())
+	if err != nil {
+		t.Fatalf("failed to load genesis snapshot: %v", err)
+	}
+	return &config, genesis, chain
+}
+
+// newTesterVerifier creates an engine and a chain holding only the genesis of
+// the given generated chain, ready to verify the generated headers.
+func newTesterVerifier(config *params.ChainConfig, genesis *Snapshot, generated *testerHeaderChain) (*Alien, *testerHeaderChain) {
+	db := ethdb.NewMemDatabase()
+	genesis.store(db)
+	return New(config.Alien, db), newTesterHeaderChain(config, generated.headers[0])
+}
+
+// Tests that the concurrent batch verification reports the same results, in the
+// same order, as verifying the headers one by one.
+func TestAlien_VerifyHeaders(t *testing.T) {
+	config, genesis, generated := newTesterHeaders(t, 3, 60)
+
+	headers := make([]*types.Header, len(generated.headers)-1)
+	for i, header := range generated.headers[1:] {
+		headers[i] = types.CopyHeader(header)
+	}
+	headers[20].MixDigest = common.HexToHash("0x01")
+	headers[40].Time = new(big.Int).SetUint64(uint64(time.Now().Unix()) + 60)
+	headers[50].Extra = headers[50].Extra[:extraVanity]
+
+	engine, chain := newTesterVerifier(config, genesis, generated)
+	want := make([]error, len(headers))
+	for i, header := range headers {
+		want[i] = engine.verifyHeader(chain, header, headers[:i])
+	}
+	for i, header := range generated.headers[1:20] {
+		if want[i] != nil {
+			t.Fatalf("header %d: valid header rejected: %v", header.Number, want[i])
+		}
+	}
+	for _, i := range []int{20, 40, 50} {
+		if want[i] == nil {
+			t.Fatalf("header %d: invalid header accepted", headers[i].Number)
+		}
+	}
+	engine, chain = newTesterVerifier(config, genesis, generated)
+	_, results := engine.VerifyHeaders(chain, headers, make([]bool, len(headers)))
+	for i := range headers {
+		if err := <-results; err != want[i] {
+			t.Errorf("header %d: error mismatch: have %v, want %v", headers[i].Number, err, want[i])
+		}
+	}
+}
+
+var (
+	benchHeadersOnce    sync.Once
+	benchHeadersConfig  *params.ChainConfig
+	benchHeadersGenesis *Snapshot
+	benchHeaders        *testerHeaderChain
+)
+
+// benchmarkVerifyHeaders measures verifying a generated 100k header chain in a
+// batch, either via VerifyHeaders or by verifying the headers one by one.
+func benchmarkVerifyHeaders(b *testing.B, concurrent bool) {
+	benchHeadersOnce.Do(func() {
+		benchHeadersConfig, benchHeadersGenesis, benchHeaders = newTesterHeaders(b, 3, 100000)
+	})
+	headers := benchHeaders.headers[1:]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		engine, chain := newTesterVerifier(benchHeadersConfig, benchHeadersGenesis, benchHeaders)
+		b.StartTimer()
+
+		if concurrent {
+			_, results := engine.VerifyHeaders(chain, headers, make([]bool, len(headers)))
+			for range headers {
+				if err := <-results; err != nil {
+					b.Fatalf("failed to verify headers: %v", err)
+				}
+			}
+		} else {
+			for j, header := range headers {
+				if err := engine.verifyHeader(chain, header, headers[:j]); err != nil {
+					b.Fatalf("failed to verify header %d: %v", header.Number, err)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyHeadersSequential(b *testing.B) { benchmarkVerifyHeaders(b, false) }
+func BenchmarkVerifyHeadersConcurrent(b *testing.B) { benchmarkVerifyHeaders(b, true) }
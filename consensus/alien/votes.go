@@ -0,0 +1,86 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core/types"
+)
+
+// ExpiringVote is a vote along with the block it expires at. A vote expires once
+// more than an epoch of blocks passed since it was cast, unless too few voters
+// would be left.
+type ExpiringVote struct {
+	Voter        common.Address `json:"voter"`
+	Candidate    common.Address `json:"candidate"`
+	Stake        *big.Int       `json:"stake"`
+	VotedNumber  uint64         `json:"votedNumber"`  // Block the vote was cast at
+	ExpiryNumber uint64         `json:"expiryNumber"` // First block the vote may be expired at
+	BlocksLeft   uint64         `json:"blocksLeft"`   // Blocks left before expiry, 0 if overdue
+}
+
+// ExpiringVotes lists the votes cast by or for the given address which expire
+// within the given number of blocks after the header.
+func (a *Alien) ExpiringVotes(chain consensus.ChainReader, header *types.Header, address common.Address, within uint64) ([]*ExpiringVote, error) {
+	snap, err := a.snapshot(chain, header.Number.Uint64(), header.Hash(), nil, nil, defaultLoopCntRecalculateSigners)
+	if err != nil {
+		return nil, err
+	}
+	return snap.ExpiringVotes(address, within), nil
+}
+
+// ExpiringVotes lists the votes cast by or for the given address which expire
+// within the given number of blocks after the snapshot, soonest first.
+func (s *Snapshot) ExpiringVotes(address common.Address, within uint64) []*ExpiringVote {
+	var votes []*ExpiringVote
+	for voter, vote := range s.Votes {
+		if voter != address && vote.Candidate != address {
+			continue
+		}
+		voted, ok := s.Voters[voter]
+		if !ok {
+			continue
+		}
+		expiry := voted.Uint64() + s.config.Epoch + 1
+		if expiry > s.Number+within {
+			continue
+		}
+		expiring := &ExpiringVote{
+			Voter:        voter,
+			Candidate:    vote.Candidate,
+			Stake:        new(big.Int).Set(vote.Stake),
+			VotedNumber:  voted.Uint64(),
+			ExpiryNumber: expiry,
+		}
+		if expiry > s.Number {
+			expiring.BlocksLeft = expiry - s.Number
+		}
+		votes = append(votes, expiring)
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if votes[i].ExpiryNumber != votes[j].ExpiryNumber {
+			return votes[i].ExpiryNumber < votes[j].ExpiryNumber
+		}
+		return bytes.Compare(votes[i].Voter[:], votes[j].Voter[:]) < 0
+	})
+	return votes
+}
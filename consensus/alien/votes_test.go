@@ -0,0 +1,93 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package alien
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/params"
+)
+
+// newVoteSnapshot creates a snapshot at the given number where every voter voted
+// for itself at the given block.
+func newVoteSnapshot(number uint64, voted map[common.Address]uint64) *Snapshot {
+	snap := &Snapshot{
+		config: &params.AlienConfig{Epoch: 100, MaxSignerCount: 2},
+		Number: number,
+		Votes:  make(map[common.Address]*Vote),
+		Voters: make(map[common.Address]*big.Int),
+		Tally:  make(map[common.Address]*big.Int),
+		MinVB:  big.NewInt(100),
+	}
+	for voter, at := range voted {
+		snap.Votes[voter] = &Vote{Voter: voter, Candidate: voter, Stake: big.NewInt(1000)}
+		snap.Voters[voter] = new(big.Int).SetUint64(at)
+		snap.Tally[voter] = big.NewInt(1000)
+	}
+	return snap
+}
+
+// Tests that the votes expiring within a number of blocks are listed soonest
+// first, for their voter and their candidate.
+func TestExpiringVotes(t *testing.T) {
+	a, b, c := common.Address{0x0a}, common.Address{0x0b}, common.Address{0x0c}
+	snap := newVoteSnapshot(150, map[common.Address]uint64{a: 40, b: 60, c: 120})
+	snap.Votes[b].Candidate = a
+
+	// Votes expire at the vote number + epoch + 1
+	votes := snap.ExpiringVotes(a, 20)
+	if len(votes) != 2 {
+		t.Fatalf("expiring vote count mismatch: have %d, want 2", len(votes))
+	}
+	if v := votes[0]; v.Voter != a || v.ExpiryNumber != 141 || v.BlocksLeft != 0 {
+		t.Errorf("overdue vote mismatch: have %+v", v)
+	}
+	if v := votes[1]; v.Voter != b || v.Candidate != a || v.ExpiryNumber != 161 || v.BlocksLeft != 11 {
+		t.Errorf("expiring vote mismatch: have %+v", v)
+	}
+	if votes := snap.ExpiringVotes(a, 10); len(votes) != 1 || votes[0].Voter != a {
+		t.Errorf("votes expiring within 10 blocks mismatch: have %v", votes)
+	}
+	if votes := snap.ExpiringVotes(c, 70); len(votes) != 0 {
+		t.Errorf("votes expiring at 221 listed within 70 blocks: %v", votes)
+	}
+	if votes := snap.ExpiringVotes(c, 71); len(votes) != 1 || votes[0].BlocksLeft != 71 {
+		t.Errorf("votes expiring at 221 not listed within 71 blocks: %v", votes)
+	}
+}
+
+// Tests that expired votes are recorded only when they are removed.
+func TestExpiredVotesRecorded(t *testing.T) {
+	a, b, c := common.Address{0x0a}, common.Address{0x0b}, common.Address{0x0c}
+
+	snap := newVoteSnapshot(150, map[common.Address]uint64{a: 40, b: 60, c: 120})
+	snap.updateSnapshotForExpired(big.NewInt(155))
+	if len(snap.expired) != 1 || snap.expired[0].Voter != a {
+		t.Fatalf("expired votes mismatch: have %v, want vote of %x", snap.expired, a)
+	}
+	if _, ok := snap.Votes[a]; ok {
+		t.Errorf("expired vote of %x not removed", a)
+	}
+	// Expired votes are kept while too few voters would be left
+	snap = newVoteSnapshot(150, map[common.Address]uint64{a: 40, b: 40})
+	snap.updateSnapshotForExpired(big.NewInt(161))
+	if len(snap.expired) != 0 || len(snap.Votes) != 2 {
+		t.Errorf("votes expired below the signer count: expired %v, left %d", snap.expired, len(snap.Votes))
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/core/state"
 	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/event"
 	"github.com/TTCECO/gttc/params"
 	"github.com/TTCECO/gttc/rpc"
 )
@@ -49,6 +50,14 @@ type ChainReader interface {
 	GetBlock(hash common.Hash, number uint64) *types.Block
 }
 
+// ChainHeadSubscriber is implemented by the chains announcing their new
+// canonical heads, for engines following the chain.
+type ChainHeadSubscriber interface {
+	// SubscribeChainHeaders registers a subscription for the headers of the new
+	// canonical heads.
+	SubscribeChainHeaders(ch chan<- *types.Header) event.Subscription
+}
+
 // Engine is an algorithm agnostic consensus engine.
 type Engine interface {
 	// Author retrieves the Ethereum address of the account that minted the given
@@ -105,3 +114,54 @@ type PoW interface {
 	// Hashrate returns the current mining hashrate of a PoW consensus engine.
 	Hashrate() float64
 }
+
+// TxValidator is an optional interface a consensus engine may implement to
+// reject transactions carrying engine specific payloads before they enter the
+// transaction pool. The admission rule may be stricter than the consensus rules,
+// refusing transactions that would be valid in a block but have no effect.
+type TxValidator interface {
+	// PrepareTxValidation is called whenever the transaction pool moves to a new
+	// chain head, letting the engine compute the data its transaction hooks need
+	// on top of that head once, instead of for every pooled transaction.
+	PrepareTxValidation(chain ChainReader, head *types.Header)
+
+	// ValidateTx checks whether a transaction sent by the given account would be
+	// honoured by the consensus rules on top of the current chain head. The state
+	// is the one the transaction pool validates against.
+	ValidateTx(chain ChainReader, state *state.StateDB, tx *types.Transaction, sender common.Address) error
+}
+
+// TxPrioritizer is an optional interface a consensus engine may implement to
+// single out the transactions the consensus itself relies on, so that they can
+// be prioritized over user traffic.
+type TxPrioritizer interface {
+	// IsConsensusTx returns whether a transaction sent by the given account is a
+	// consensus transaction on top of the current chain head.
+	IsConsensusTx(chain ChainReader, tx *types.Transaction, sender common.Address) bool
+}
+
+// SnapshotSyncer is an optional interface a consensus engine may implement to
+// let fast syncing nodes download its consensus state at the sync pivot from
+// remote peers, instead of rebuilding it from the genesis block.
+type SnapshotSyncer interface {
+	// ExportSnapshot returns the encoded consensus snapshot at the given block.
+	ExportSnapshot(chain ChainReader, hash common.Hash, number uint64) ([]byte, error)
+
+	// ImportSnapshot verifies an encoded snapshot against the commitment carried
+	// by the header of its child block, the first of the given consecutive
+	// headers, and adopts it as the base for verifying the headers that follow
+	// once the rest of the headers confirm it.
+	ImportSnapshot(chain ChainReader, headers []*types.Header, blob []byte) error
+}
+
+// CheckpointSyncer is an optional interface a consensus engine may implement to
+// start syncing from a trusted checkpoint, refusing any chain conflicting with it.
+type CheckpointSyncer interface {
+	// Checkpoint returns the number and hash of the trusted checkpoint block, or
+	// a zero number if there is none.
+	Checkpoint() (uint64, common.Hash)
+
+	// ImportCheckpoint verifies an encoded consensus snapshot against the trusted
+	// checkpoint, and adopts it as the base for verifying the headers that follow.
+	ImportCheckpoint(chain ChainReader, blob []byte) error
+}
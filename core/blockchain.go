@@ -99,6 +99,7 @@ type BlockChain struct {
 	chainFeed     event.Feed
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
+	headerFeed    event.Feed
 	logsFeed      event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
@@ -1367,6 +1368,7 @@ func (bc *BlockChain) PostChainEvents(events []interface{}, logs []*types.Log) {
 
 		case ChainHeadEvent:
 			bc.chainHeadFeed.Send(ev)
+			bc.headerFeed.Send(ev.Block.Header())
 
 		case ChainSideEvent:
 			bc.chainSideFeed.Send(ev)
@@ -1552,6 +1554,12 @@ func (bc *BlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Su
 	return bc.scope.Track(bc.chainHeadFeed.Subscribe(ch))
 }
 
+// SubscribeChainHeaders registers a subscription of the headers of the new
+// canonical heads.
+func (bc *BlockChain) SubscribeChainHeaders(ch chan<- *types.Header) event.Subscription {
+	return bc.scope.Track(bc.headerFeed.Subscribe(ch))
+}
+
 // SubscribeChainSideEvent registers a subscription of ChainSideEvent.
 func (bc *BlockChain) SubscribeChainSideEvent(ch chan<- ChainSideEvent) event.Subscription {
 	return bc.scope.Track(bc.chainSideFeed.Subscribe(ch))
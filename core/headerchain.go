@@ -427,6 +427,14 @@ func (hc *HeaderChain) SetHead(head uint64, delFn DeleteCallback) {
 	for i := height; i > head; i-- {
 		rawdb.DeleteCanonicalHash(hc.chainDb, i)
 	}
+	// Discard any frozen blocks above the new head from the ancient store
+	if ancients, ok := hc.chainDb.(rawdb.AncientStore); ok {
+		if frozen, _ := ancients.Ancients(); frozen > head+1 {
+			if err := ancients.TruncateAncients(head + 1); err != nil {
+				log.Crit("Failed to truncate ancient store", "head", head, "err", err)
+			}
+		}
+	}
 	// Clear out any stale content from the caches
 	hc.headerCache.Purge()
 	hc.tdCache.Purge()
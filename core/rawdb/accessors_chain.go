@@ -30,6 +30,12 @@ import (
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	data, _ := db.Get(append(append(headerPrefix, encodeBlockNumber(number)...), headerHashSuffix...))
+	if len(data) == 0 {
+		// Blocks moved into the ancient store are canonical by definition
+		if ancients, ok := db.(AncientReader); ok {
+			data, _ = ancients.Ancient(freezerHashTable, number)
+		}
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
@@ -130,14 +136,17 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(append(append(headerPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+	if len(data) == 0 {
+		data = readAncient(db, freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
-	key := append(append(append(headerPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+	key := append(append(headerPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 	if has, err := db.Has(key); !has || err != nil {
-		return false
+		return hasAncient(db, hash, number)
 	}
 	return true
 }
@@ -180,6 +189,14 @@ func WriteHeader(db DatabaseWriter, header *types.Header) {
 	}
 }
 
+// deleteHeaderRLP removes the block header, but leaves the hash to number
+// mapping in place.
+func deleteHeaderRLP(db DatabaseDeleter, hash common.Hash, number uint64) {
+	if err := db.Delete(append(append(headerPrefix, encodeBlockNumber(number)...), hash.Bytes()...)); err != nil {
+		log.Crit("Failed to delete header", "err", err)
+	}
+}
+
 // DeleteHeader removes all block header data associated with a hash.
 func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 	if err := db.Delete(append(append(headerPrefix, encodeBlockNumber(number)...), hash.Bytes()...)); err != nil {
@@ -193,6 +210,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 // ReadBodyRLP retrieves the block body (transactions and uncles) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(append(append(blockBodyPrefix, encodeBlockNumber(number)...), hash.Bytes()...))
+	if len(data) == 0 {
+		data = readAncient(db, freezerBodiesTable, hash, number)
+	}
 	return data
 }
 
@@ -208,7 +228,7 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
 	key := append(append(blockBodyPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 	if has, err := db.Has(key); !has || err != nil {
-		return false
+		return hasAncient(db, hash, number)
 	}
 	return true
 }
@@ -243,9 +263,18 @@ func DeleteBody(db DatabaseDeleter, hash common.Hash, number uint64) {
 	}
 }
 
+// readTdRLP retrieves a block's total difficulty in its raw RLP database encoding.
+func readTdRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(append(append(append(headerPrefix, encodeBlockNumber(number)...), hash[:]...), headerTDSuffix...))
+	if len(data) == 0 {
+		data = readAncient(db, freezerDifficultyTable, hash, number)
+	}
+	return data
+}
+
 // ReadTd retrieves a block's total difficulty corresponding to the hash.
 func ReadTd(db DatabaseReader, hash common.Hash, number uint64) *big.Int {
-	data, _ := db.Get(append(append(append(headerPrefix, encodeBlockNumber(number)...), hash[:]...), headerTDSuffix...))
+	data := readTdRLP(db, hash, number)
 	if len(data) == 0 {
 		return nil
 	}
@@ -276,10 +305,20 @@ func DeleteTd(db DatabaseDeleter, hash common.Hash, number uint64) {
 	}
 }
 
+// readReceiptsRLP retrieves the flattened receipts of a block in their raw RLP
+// storage encoding.
+func readReceiptsRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash[:]...))
+	if len(data) == 0 {
+		data = readAncient(db, freezerReceiptTable, hash, number)
+	}
+	return data
+}
+
 // ReadReceipts retrieves all the transaction receipts belonging to a block.
 func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
 	// Retrieve the flattened receipt slice
-	data, _ := db.Get(append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash[:]...))
+	data := readReceiptsRLP(db, hash, number)
 	if len(data) == 0 {
 		return nil
 	}
@@ -353,6 +392,27 @@ func DeleteBlock(db DatabaseDeleter, hash common.Hash, number uint64) {
 	DeleteTd(db, hash, number)
 }
 
+// hasAncient checks whether the block with the given hash and number has been
+// moved into the ancient store backing the database, if any.
+func hasAncient(db DatabaseReader, hash common.Hash, number uint64) bool {
+	ancients, ok := db.(AncientReader)
+	if !ok {
+		return false
+	}
+	data, _ := ancients.Ancient(freezerHashTable, number)
+	return len(data) != 0 && common.BytesToHash(data) == hash
+}
+
+// readAncient retrieves a blob of the given kind from the ancient store backing
+// the database, if any, provided the frozen block matches the requested hash.
+func readAncient(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	if !hasAncient(db, hash, number) {
+		return nil
+	}
+	data, _ := db.(AncientReader).Ancient(kind, number)
+	return data
+}
+
 // FindCommonAncestor returns the last common ancestor of two block headers
 func FindCommonAncestor(db DatabaseReader, a, b *types.Header) *types.Header {
 	for bn := b.Number.Uint64(); a.Number.Uint64() > bn; {
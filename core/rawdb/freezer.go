@@ -0,0 +1,486 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/log"
+	"github.com/prometheus/prometheus/util/flock"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	// freezerHeaderTable indicates the name of the freezer header table.
+	freezerHeaderTable = "headers"
+
+	// freezerHashTable indicates the name of the freezer canonical hash table.
+	freezerHashTable = "hashes"
+
+	// freezerBodiesTable indicates the name of the freezer block body table.
+	freezerBodiesTable = "bodies"
+
+	// freezerReceiptTable indicates the name of the freezer receipts table.
+	freezerReceiptTable = "receipts"
+
+	// freezerDifficultyTable indicates the name of the freezer total difficulty table.
+	freezerDifficultyTable = "diffs"
+)
+
+// freezerNoSnappy configures whether compression is disabled for the ancient
+// tables. Hashes and difficulties don't compress well.
+var freezerNoSnappy = map[string]bool{
+	freezerHeaderTable:     false,
+	freezerHashTable:       true,
+	freezerBodiesTable:     false,
+	freezerReceiptTable:    false,
+	freezerDifficultyTable: true,
+}
+
+const (
+	// DefaultFreezerThreshold is the number of recent blocks that are never moved
+	// into the ancient store, as they may still be reorganised.
+	DefaultFreezerThreshold = 90000
+
+	// freezerRecheckInterval is the frequency to check the key-value database for
+	// chain progression that might permit new blocks to be frozen into immutable
+	// storage.
+	freezerRecheckInterval = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks to freeze in one batch
+	// before doing an fsync and deleting it from the key-value store.
+	freezerBatchLimit = 30000
+)
+
+var (
+	// errUnknownTable is returned if the user attempts to read from a table that is
+	// not tracked by the freezer.
+	errUnknownTable = errors.New("unknown table")
+
+	// errSymlinkDatadir is returned if the ancient directory is a symbolic link.
+	errSymlinkDatadir = errors.New("symbolic link datadir is not supported")
+)
+
+// freezer is an append-only database to store immutable chain data into flat
+// files. Blocks older than the threshold are moved out of the key-value store,
+// keeping it small and avoiding the compaction overhead of data that is never
+// going to change again.
+type freezer struct {
+	frozen uint64 // Number of blocks already frozen, accessed atomically
+
+	threshold uint64 // Number of recent blocks not to freeze, 0 disables freezing
+
+	tables       map[string]*freezerTable // Data tables for storing everything
+	instanceLock flock.Releaser           // File-system lock to prevent double opens
+	lock         sync.Mutex               // Serializes appends against truncations
+
+	quit      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// newFreezer creates a chain freezer that moves ancient chain data into
+// append-only flat file containers.
+func newFreezer(datadir string, threshold uint64) (*freezer, error) {
+	if info, err := os.Lstat(datadir); !os.IsNotExist(err) {
+		if info.Mode()&os.ModeSymlink != 0 {
+			log.Warn("Symbolic link ancient database is not supported", "path", datadir)
+			return nil, errSymlinkDatadir
+		}
+	}
+	if err := os.MkdirAll(datadir, 0755); err != nil {
+		return nil, err
+	}
+	// Leveldb uses LOCK as the filelock filename. To prevent the
+	// name collision, we use FLOCK as the lock name.
+	lock, _, err := flock.New(filepath.Join(datadir, "FLOCK"))
+	if err != nil {
+		return nil, err
+	}
+	// Open all the supported data tables
+	freezer := &freezer{
+		threshold:    threshold,
+		tables:       make(map[string]*freezerTable),
+		instanceLock: lock,
+		quit:         make(chan struct{}),
+	}
+	for name, disableSnappy := range freezerNoSnappy {
+		table, err := newTable(datadir, name, disableSnappy)
+		if err != nil {
+			for _, table := range freezer.tables {
+				table.Close()
+			}
+			lock.Release()
+			return nil, err
+		}
+		freezer.tables[name] = table
+	}
+	if err := freezer.repair(); err != nil {
+		for _, table := range freezer.tables {
+			table.Close()
+		}
+		lock.Release()
+		return nil, err
+	}
+	log.Info("Opened ancient database", "database", datadir, "frozen", freezer.frozen)
+	return freezer, nil
+}
+
+// Close terminates the chain freezer, unmapping all the data files.
+func (f *freezer) Close() error {
+	var errs []error
+	f.closeOnce.Do(func() {
+		close(f.quit)
+		f.wg.Wait()
+
+		for _, table := range f.tables {
+			if err := table.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := f.instanceLock.Release(); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists
+// in the freezer.
+func (f *freezer) HasAncient(kind string, number uint64) (bool, error) {
+	if table := f.tables[kind]; table != nil {
+		return number < atomic.LoadUint64(&f.frozen), nil
+	}
+	return false, errUnknownTable
+}
+
+// Ancient retrieves an ancient binary blob from the append-only immutable files.
+func (f *freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	if table := f.tables[kind]; table != nil {
+		return table.Retrieve(number)
+	}
+	return nil, errUnknownTable
+}
+
+// Ancients returns the length of the frozen items.
+func (f *freezer) Ancients() (uint64, error) {
+	return atomic.LoadUint64(&f.frozen), nil
+}
+
+// appendAncient injects all binary blobs belong to block at the end of the
+// append-only immutable table files. All out-of-order injection will be rejected.
+func (f *freezer) appendAncient(number uint64, hash, header, body, receipts, td []byte) (err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	// Ensure the binary blobs we are appending is continuous with freezer.
+	if atomic.LoadUint64(&f.frozen) != number {
+		return errOutOrderInsertion
+	}
+	// Rollback all inserted data if any insertion below failed to ensure
+	// the tables won't out of sync.
+	defer func() {
+		if err != nil {
+			rerr := f.repair()
+			if rerr != nil {
+				log.Crit("Failed to repair freezer", "err", rerr)
+			}
+			log.Info("Append ancient failed", "number", number, "err", err)
+		}
+	}()
+	// Inject all the components into the relevant data tables
+	if err := f.tables[freezerHashTable].Append(f.frozen, hash); err != nil {
+		log.Error("Failed to append ancient hash", "number", f.frozen, "hash", common.BytesToHash(hash), "err", err)
+		return err
+	}
+	if err := f.tables[freezerHeaderTable].Append(f.frozen, header); err != nil {
+		log.Error("Failed to append ancient header", "number", f.frozen, "hash", common.BytesToHash(hash), "err", err)
+		return err
+	}
+	if err := f.tables[freezerBodiesTable].Append(f.frozen, body); err != nil {
+		log.Error("Failed to append ancient body", "number", f.frozen, "hash", common.BytesToHash(hash), "err", err)
+		return err
+	}
+	if err := f.tables[freezerReceiptTable].Append(f.frozen, receipts); err != nil {
+		log.Error("Failed to append ancient receipts", "number", f.frozen, "hash", common.BytesToHash(hash), "err", err)
+		return err
+	}
+	if err := f.tables[freezerDifficultyTable].Append(f.frozen, td); err != nil {
+		log.Error("Failed to append ancient difficulty", "number", f.frozen, "hash", common.BytesToHash(hash), "err", err)
+		return err
+	}
+	atomic.AddUint64(&f.frozen, 1) // Only modify atomically
+	return nil
+}
+
+// TruncateAncients discards any recent data above the provided threshold number.
+func (f *freezer) TruncateAncients(items uint64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if atomic.LoadUint64(&f.frozen) <= items {
+		return nil
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, items)
+	return nil
+}
+
+// sync flushes all data tables to disk.
+func (f *freezer) sync() error {
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// repair truncates all data tables to the same length. The caller must either
+// hold the freezer lock or have exclusive access to the freezer.
+func (f *freezer) repair() error {
+	min := uint64(math.MaxUint64)
+	for _, table := range f.tables {
+		if min > table.items {
+			min = table.items
+		}
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(min); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, min)
+	return nil
+}
+
+// start launches the background thread that keeps moving ancient chain segments
+// from the key-value database into the freezer. It is a noop if freezing was
+// disabled with a zero threshold.
+func (f *freezer) start(db ethdb.Database) {
+	if f.threshold == 0 {
+		return
+	}
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.freeze(db)
+	}()
+}
+
+// freeze is a background thread that periodically checks the blockchain for any
+// import progress and moves ancient data from the fast database into the freezer.
+//
+// This functionality is deliberately broken off from block importing to avoid
+// incurring additional data shuffling delays on block propagation.
+func (f *freezer) freeze(db ethdb.Database) {
+	backoff := false
+	for {
+		if backoff {
+			select {
+			case <-time.After(freezerRecheckInterval):
+			case <-f.quit:
+				return
+			}
+		} else {
+			select {
+			case <-f.quit:
+				return
+			default:
+			}
+		}
+		backoff = true
+
+		// Retrieve the freezing threshold.
+		hash := ReadHeadBlockHash(db)
+		if hash == (common.Hash{}) {
+			log.Debug("Current full block hash unavailable") // new chain, empty database
+			continue
+		}
+		number := ReadHeaderNumber(db, hash)
+		switch {
+		case number == nil:
+			log.Error("Current full block number unavailable", "hash", hash)
+			continue
+
+		case *number <= f.threshold:
+			log.Debug("Current full block not old enough", "number", *number, "hash", hash, "delay", f.threshold)
+			continue
+		}
+		// Seems we have data ready to be frozen, process in usable batches
+		var (
+			start = time.Now()
+			first = atomic.LoadUint64(&f.frozen)
+			limit = *number - f.threshold
+		)
+		if limit <= first {
+			continue
+		}
+		if limit-first > freezerBatchLimit {
+			limit = first + freezerBatchLimit
+			backoff = false
+		}
+		ancients := make([]common.Hash, 0, limit-first)
+		for atomic.LoadUint64(&f.frozen) < limit {
+			// Retrieves all the components of the canonical block
+			n := atomic.LoadUint64(&f.frozen)
+			hash := ReadCanonicalHash(db, n)
+			if hash == (common.Hash{}) {
+				log.Error("Canonical hash missing, can't freeze", "number", n)
+				break
+			}
+			header := ReadHeaderRLP(db, hash, n)
+			if len(header) == 0 {
+				log.Error("Block header missing, can't freeze", "number", n, "hash", hash)
+				break
+			}
+			body := ReadBodyRLP(db, hash, n)
+			if len(body) == 0 {
+				log.Error("Block body missing, can't freeze", "number", n, "hash", hash)
+				break
+			}
+			receipts := readReceiptsRLP(db, hash, n)
+			if len(receipts) == 0 {
+				log.Error("Block receipts missing, can't freeze", "number", n, "hash", hash)
+				break
+			}
+			td := readTdRLP(db, hash, n)
+			if len(td) == 0 {
+				log.Error("Total difficulty missing, can't freeze", "number", n, "hash", hash)
+				break
+			}
+			log.Trace("Deep froze ancient block", "number", n, "hash", hash)
+			// Inject all the components into the relevant data tables
+			if err := f.appendAncient(n, hash[:], header, body, receipts, td); err != nil {
+				break
+			}
+			ancients = append(ancients, hash)
+		}
+		if atomic.LoadUint64(&f.frozen) < limit {
+			backoff = true // Missing data, don't spin until the chain progresses
+		}
+		if len(ancients) == 0 {
+			continue
+		}
+		// Batch of blocks have been frozen, flush them before wiping from the key-value store
+		if err := f.sync(); err != nil {
+			log.Crit("Failed to flush frozen tables", "err", err)
+		}
+		// Wipe out all data from the active database, keeping the genesis around
+		for i, hash := range ancients {
+			n := first + uint64(i)
+			if n == 0 {
+				continue
+			}
+			// Side chain blocks at a frozen height can never become canonical
+			for _, side := range readAllHashes(db, n) {
+				if side != hash {
+					DeleteBlock(db, side, n)
+				}
+			}
+			DeleteCanonicalHash(db, n)
+			deleteHeaderRLP(db, hash, n)
+			DeleteBody(db, hash, n)
+			DeleteReceipts(db, hash, n)
+			DeleteTd(db, hash, n)
+		}
+		// Migrating a large backlog of blocks leaves plenty of tombstones behind,
+		// compact the wiped ranges to actually release the disk space
+		if !backoff {
+			compactAncientRange(db, first, first+uint64(len(ancients)))
+		}
+		// Log something friendly for the user
+		log.Info("Deep froze chain segment", "blocks", len(ancients), "elapsed", common.PrettyDuration(time.Since(start)),
+			"number", first+uint64(len(ancients))-1, "hash", ancients[len(ancients)-1])
+	}
+}
+
+// compactAncientRange compacts the key-value store over the chain segment
+// [first, limit) that has been moved into the freezer, if the store supports it.
+func compactAncientRange(db ethdb.Database, first, limit uint64) {
+	ldb, ok := db.(interface{ LDB() *leveldb.DB })
+	if !ok {
+		return
+	}
+	start := time.Now()
+	for _, prefix := range [][]byte{headerPrefix, blockBodyPrefix, blockReceiptsPrefix} {
+		r := util.Range{
+			Start: append(append([]byte{}, prefix...), encodeBlockNumber(first)...),
+			Limit: append(append([]byte{}, prefix...), encodeBlockNumber(limit)...),
+		}
+		if err := ldb.LDB().CompactRange(r); err != nil {
+			log.Error("Failed to compact frozen segment", "prefix", string(prefix), "err", err)
+			return
+		}
+	}
+	log.Info("Compacted frozen chain segment", "first", first, "limit", limit, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// readAllHashes retrieves the hashes of all the headers stored in the key-value
+// database at the given height, canonical or not.
+func readAllHashes(db ethdb.Database, number uint64) []common.Hash {
+	prefix := append(append([]byte{}, headerPrefix...), encodeBlockNumber(number)...)
+
+	var keys [][]byte
+	switch db := db.(type) {
+	case interface {
+		NewIteratorWithPrefix(prefix []byte) iterator.Iterator
+	}:
+		it := db.NewIteratorWithPrefix(prefix)
+		for it.Next() {
+			keys = append(keys, common.CopyBytes(it.Key()))
+		}
+		it.Release()
+	case interface{ Keys() [][]byte }:
+		for _, key := range db.Keys() {
+			if bytes.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	// Skip the total difficulties and the canonical hash sharing the prefix
+	var hashes []common.Hash
+	for _, key := range keys {
+		if len(key) == len(prefix)+common.HashLength {
+			hashes = append(hashes, common.BytesToHash(key[len(prefix):]))
+		}
+	}
+	return hashes
+}
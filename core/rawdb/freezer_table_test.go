@@ -0,0 +1,221 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// getChunk returns a chunk of data of the given length, filled with b.
+func getChunk(size int, b int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(b)
+	}
+	return data
+}
+
+// Tests that items appended to a freezer table can be retrieved, also across
+// data file boundaries and reopening the table.
+func TestFreezerTableBasics(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, noCompression := range []bool{false, true} {
+		name := fmt.Sprintf("basics-%v", noCompression)
+
+		// Use a small file size to force several data files
+		f, err := newCustomTable(dir, name, 50, noCompression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for x := 0; x < 255; x++ {
+			if err := f.Append(uint64(x), getChunk(15, x)); err != nil {
+				t.Fatalf("append %d failed: %v", x, err)
+			}
+		}
+		if err := f.Append(1, getChunk(15, 1)); err != errOutOrderInsertion {
+			t.Fatalf("out of order append error mismatch: have %v, want %v", err, errOutOrderInsertion)
+		}
+		f.Close()
+
+		// Reopen the table and check all the contents
+		if f, err = newCustomTable(dir, name, 50, noCompression); err != nil {
+			t.Fatal(err)
+		}
+		if f.items != 255 {
+			t.Fatalf("item count mismatch: have %d, want %d", f.items, 255)
+		}
+		for y := 0; y < 255; y++ {
+			got, err := f.Retrieve(uint64(y))
+			if err != nil {
+				t.Fatalf("retrieve %d failed: %v", y, err)
+			}
+			if !bytes.Equal(got, getChunk(15, y)) {
+				t.Fatalf("item %d mismatch: have %x", y, got)
+			}
+		}
+		if _, err := f.Retrieve(255); err != errOutOfBounds {
+			t.Fatalf("out of bounds retrieval error mismatch: have %v, want %v", err, errOutOfBounds)
+		}
+		f.Close()
+		if _, err := f.Retrieve(0); err != errClosed {
+			t.Fatalf("closed retrieval error mismatch: have %v, want %v", err, errClosed)
+		}
+	}
+}
+
+// Tests that a freezer table repairs itself on open after data was lost from
+// the end of either the data files or the index.
+func TestFreezerTableRepair(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Fill a table with 255 items of 15 bytes, 3 items per data file
+	f, err := newCustomTable(dir, "repair", 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 255; x++ {
+		f.Append(uint64(x), getChunk(15, x))
+	}
+	f.Close()
+
+	// Chop a few bytes off the head data file, the last item must be dropped
+	head := filepath.Join(dir, fmt.Sprintf("repair.%04d.rdat", 84))
+	if err := os.Truncate(head, 20); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = newCustomTable(dir, "repair", 50, true); err != nil {
+		t.Fatal(err)
+	}
+	if f.items != 253 {
+		t.Fatalf("item count mismatch after data loss: have %d, want %d", f.items, 253)
+	}
+	f.Close()
+
+	// Remove the head data file entirely, the table must step back a file
+	if err := os.Remove(head); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = newCustomTable(dir, "repair", 50, true); err != nil {
+		t.Fatal(err)
+	}
+	if f.items != 252 {
+		t.Fatalf("item count mismatch after file loss: have %d, want %d", f.items, 252)
+	}
+	f.Close()
+
+	// Chop a partial entry off the index, the last item must be dropped
+	index := filepath.Join(dir, "repair.ridx")
+	stat, err := os.Stat(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(index, stat.Size()-4); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = newCustomTable(dir, "repair", 50, true); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if f.items != 251 {
+		t.Fatalf("item count mismatch after index loss: have %d, want %d", f.items, 251)
+	}
+	for y := 0; y < 251; y++ {
+		got, err := f.Retrieve(uint64(y))
+		if err != nil {
+			t.Fatalf("retrieve %d failed: %v", y, err)
+		}
+		if !bytes.Equal(got, getChunk(15, y)) {
+			t.Fatalf("item %d mismatch: have %x", y, got)
+		}
+	}
+	// The repaired table must accept new items in place of the lost ones
+	if err := f.Append(251, getChunk(15, 0xff)); err != nil {
+		t.Fatalf("append after repair failed: %v", err)
+	}
+	if got, _ := f.Retrieve(251); !bytes.Equal(got, getChunk(15, 0xff)) {
+		t.Fatalf("item 251 mismatch after repair: have %x", got)
+	}
+}
+
+// Tests that truncating a freezer table discards the items and data files above
+// the limit, and that new items can be appended afterwards.
+func TestFreezerTableTruncate(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f, err := newCustomTable(dir, "truncate", 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 30; x++ {
+		f.Append(uint64(x), getChunk(15, x))
+	}
+	if err := f.truncate(10); err != nil {
+		t.Fatalf("truncate failed: %v", err)
+	}
+	if _, err := f.Retrieve(10); err != errOutOfBounds {
+		t.Fatalf("truncated item retrievable: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("truncate.%04d.rdat", 9))); !os.IsNotExist(err) {
+		t.Fatalf("data file above the limit not removed: %v", err)
+	}
+	for x := 10; x < 20; x++ {
+		if err := f.Append(uint64(x), getChunk(15, 0xff-x)); err != nil {
+			t.Fatalf("append %d after truncate failed: %v", x, err)
+		}
+	}
+	f.Close()
+
+	if f, err = newCustomTable(dir, "truncate", 50, true); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for y := 0; y < 20; y++ {
+		want := getChunk(15, y)
+		if y >= 10 {
+			want = getChunk(15, 0xff-y)
+		}
+		if got, err := f.Retrieve(uint64(y)); err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("item %d mismatch: have %x, want %x, err %v", y, got, want, err)
+		}
+	}
+}
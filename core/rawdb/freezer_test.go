@@ -0,0 +1,199 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/ethdb"
+)
+
+// writeTestChain stores a canonical chain of n blocks, complete with total
+// difficulties and receipts, into the database and marks it as the head.
+func writeTestChain(db ethdb.Database, n int) []*types.Block {
+	blocks := make([]*types.Block, n)
+	parent := common.Hash{}
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			Number:     big.NewInt(int64(i)),
+			ParentHash: parent,
+			Difficulty: big.NewInt(2),
+			Extra:      []byte("test block"),
+		}
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(i), Logs: []*types.Log{}}
+		block := types.NewBlockWithHeader(header).WithBody(nil, []*types.Header{{Number: big.NewInt(int64(i))}})
+
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		WriteTd(db, block.Hash(), block.NumberU64(), big.NewInt(int64(2*i+2)))
+		WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{receipt})
+
+		blocks[i], parent = block, block.Hash()
+	}
+	WriteHeadHeaderHash(db, parent)
+	WriteHeadBlockHash(db, parent)
+	return blocks
+}
+
+// Tests that ancient blocks are moved out of the key-value store into the
+// freezer and that the accessors keep serving them transparently.
+func TestFreezerMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb := ethdb.NewMemDatabase()
+	blocks := writeTestChain(kvdb, 100)
+
+	// Add side chain blocks below and above the freezing limit
+	var sides []*types.Block
+	for _, number := range []int64{5, 95} {
+		header := &types.Header{Number: big.NewInt(number), ParentHash: blocks[number-1].Hash(), Difficulty: big.NewInt(1), Extra: []byte("side block")}
+		side := types.NewBlockWithHeader(header)
+		WriteBlock(kvdb, side)
+		WriteTd(kvdb, side.Hash(), side.NumberU64(), big.NewInt(2*number+1))
+		WriteReceipts(kvdb, side.Hash(), side.NumberU64(), types.Receipts{})
+		sides = append(sides, side)
+	}
+	db, err := NewDatabaseWithFreezer(kvdb, dir, 10)
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	ancients := db.(AncientStore)
+
+	// Everything below head minus the threshold should get frozen
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if frozen, _ := ancients.Ancients(); frozen == 89 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			frozen, _ := ancients.Ancients()
+			t.Fatalf("frozen block count mismatch: have %d, want %d", frozen, 89)
+		}
+	}
+	db.Close()
+
+	// Reopen with freezing disabled, the data must have been wiped from the
+	// key-value store but still be readable through the accessors
+	if db, err = NewDatabaseWithFreezer(kvdb, dir, 0); err != nil {
+		t.Fatalf("failed to reopen freezer: %v", err)
+	}
+	defer db.Close()
+
+	for i, block := range blocks {
+		hash, number := block.Hash(), block.NumberU64()
+
+		inKV := HasHeader(kvdb, hash, number)
+		if want := i == 0 || i >= 89; inKV != want {
+			t.Errorf("block %d: key-value presence mismatch: have %v, want %v", i, inKV, want)
+		}
+		if have := ReadCanonicalHash(db, number); have != hash {
+			t.Errorf("block %d: canonical hash mismatch: have %x, want %x", i, have, hash)
+		}
+		if have := ReadHeaderNumber(db, hash); have == nil || *have != number {
+			t.Errorf("block %d: hash to number mapping missing", i)
+		}
+		if !HasHeader(db, hash, number) || !HasBody(db, hash, number) {
+			t.Errorf("block %d: header or body reported missing", i)
+		}
+		if have := ReadBlock(db, hash, number); have == nil || have.Hash() != hash || len(have.Uncles()) != 1 {
+			t.Errorf("block %d: block mismatch: have %v", i, have)
+		}
+		if have := ReadTd(db, hash, number); have == nil || have.Int64() != int64(2*i+2) {
+			t.Errorf("block %d: total difficulty mismatch: have %v, want %d", i, have, 2*i+2)
+		}
+		if have := ReadReceipts(db, hash, number); len(have) != 1 || have[0].CumulativeGasUsed != uint64(i) {
+			t.Errorf("block %d: receipts mismatch: have %v", i, have)
+		}
+		// Lookups with a non-matching hash must not be served from the freezer
+		if ReadHeader(db, common.Hash{0x01}, number) != nil || HasBody(db, common.Hash{0x01}, number) {
+			t.Errorf("block %d: ancient data served for wrong hash", i)
+		}
+	}
+	// Side chain blocks are wiped along with the frozen canonical ones
+	for i, side := range sides {
+		hash, number := side.Hash(), side.NumberU64()
+		if want := i == 1; HasHeader(kvdb, hash, number) != want || HasBody(kvdb, hash, number) != want || (ReadTd(kvdb, hash, number) != nil) != want {
+			t.Errorf("side block %d: key-value presence mismatch, want %v", number, want)
+		}
+		if want := i == 1; (ReadHeaderNumber(kvdb, hash) != nil) != want {
+			t.Errorf("side block %d: hash to number mapping presence mismatch, want %v", number, want)
+		}
+	}
+	// Truncating the ancients discards the frozen blocks above the limit
+	if err := db.(AncientStore).TruncateAncients(50); err != nil {
+		t.Fatalf("failed to truncate ancients: %v", err)
+	}
+	if hash := ReadCanonicalHash(db, 60); hash != (common.Hash{}) {
+		t.Errorf("truncated canonical hash still served: %x", hash)
+	}
+	if hash := ReadCanonicalHash(db, 40); hash != blocks[40].Hash() {
+		t.Errorf("retained canonical hash mismatch: have %x, want %x", hash, blocks[40].Hash())
+	}
+}
+
+// Tests that a database whose ancient blocks were already moved out refuses to
+// start with a missing or foreign freezer.
+func TestFreezerConsistencyCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb := ethdb.NewMemDatabase()
+	writeTestChain(kvdb, 20)
+
+	db, err := NewDatabaseWithFreezer(kvdb, dir+"/ancient", 5)
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if frozen, _ := db.(AncientReader).Ancients(); frozen == 14 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("blocks not frozen")
+		}
+	}
+	db.Close()
+
+	// An empty freezer in a different location must be rejected
+	if _, err := NewDatabaseWithFreezer(kvdb, dir+"/other", 5); err == nil {
+		t.Fatalf("missing ancient segments not detected")
+	}
+	// A freezer belonging to a different chain must be rejected
+	if _, err := NewDatabaseWithFreezer(ethdbWithGenesis(common.Hash{0x01}), dir+"/ancient", 5); err == nil {
+		t.Fatalf("genesis mismatch not detected")
+	}
+}
+
+// ethdbWithGenesis creates a key-value store only containing the given canonical
+// genesis hash.
+func ethdbWithGenesis(hash common.Hash) ethdb.Database {
+	db := ethdb.NewMemDatabase()
+	WriteCanonicalHash(db, hash, 0)
+	return db
+}
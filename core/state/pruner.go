@@ -0,0 +1,393 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/log"
+	"github.com/TTCECO/gttc/rlp"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+var (
+	// pruneRootsKey tracks the state roots retained by an ongoing pruning.
+	pruneRootsKey = []byte("PruneRoots")
+
+	// pruneMarkKey tracks the last state root and account the marking finished.
+	pruneMarkKey = []byte("PruneMark")
+
+	// pruneMarkedKey flags that all nodes reachable from the retained roots have
+	// been recorded and sweeping may start.
+	pruneMarkedKey = []byte("PruneMarked")
+
+	// pruneSweepKey tracks the last chain database key the sweep passed.
+	pruneSweepKey = []byte("PruneSweep")
+
+	// pruneLogInterval is the frequency of the pruning progress reports.
+	pruneLogInterval = 8 * time.Second
+
+	// pruneMarkBatchSize is the size of the marks written to the marker database
+	// at once, each write recording the marking progress too.
+	pruneMarkBatchSize = ethdb.IdealBatchSize
+)
+
+// errPruneInProgress is returned if a pruning of a different set of state roots
+// was interrupted and needs to be finished first.
+var errPruneInProgress = errors.New("pruning of other state roots in progress")
+
+// markProgress is the position an interrupted marking resumes from: the index
+// of the state root being marked and the key of the last account whose subtries
+// were all marked.
+type markProgress struct {
+	Root    uint64
+	Account []byte
+}
+
+// Pruner deletes all the state trie nodes and contract codes from the chain
+// database that are not reachable from a set of retained state roots. Pruning
+// is done offline in two phases: all the reachable nodes are first marked in a
+// separate marker database, after which every unmarked state entry is swept from
+// the chain database. The marker database makes an interrupted pruning
+// resumable, the marking from where it stopped; once sweeping started, the pruning must be finished with the same
+// roots as the state of any other root may be partially deleted already.
+type Pruner struct {
+	db      *ethdb.LDBDatabase // Chain database to prune
+	markers *ethdb.LDBDatabase // Marker database of the reachable state entries
+	path    string             // Location of the marker database
+}
+
+// NewPruner creates a state pruner for the chain database, keeping its marker
+// database at path.
+func NewPruner(db *ethdb.LDBDatabase, path string) (*Pruner, error) {
+	markers, err := ethdb.NewLDBDatabase(path, 16, 16)
+	if err != nil {
+		return nil, err
+	}
+	return &Pruner{db: db, markers: markers, path: path}, nil
+}
+
+// Pending returns the state roots retained by an interrupted pruning that has
+// to be finished, or nil if there is nothing to resume.
+func (p *Pruner) Pending() []common.Hash {
+	if marked, _ := p.markers.Has(pruneMarkedKey); !marked {
+		return nil
+	}
+	return p.roots()
+}
+
+// roots returns the state roots recorded for the marking, or nil if none.
+func (p *Pruner) roots() []common.Hash {
+	blob, err := p.markers.Get(pruneRootsKey)
+	if err != nil || len(blob) == 0 {
+		return nil
+	}
+	var roots []common.Hash
+	if err := rlp.DecodeBytes(blob, &roots); err != nil {
+		log.Error("Invalid prune roots", "err", err)
+		return nil
+	}
+	return roots
+}
+
+// Close releases the marker database, keeping it around for resuming.
+func (p *Pruner) Close() {
+	p.markers.Close()
+}
+
+// Prune deletes every state entry not reachable from the given roots from the
+// chain database, resuming any interrupted pruning of the same roots. On success
+// the marker database is closed and removed.
+func (p *Pruner) Prune(roots []common.Hash) error {
+	if pending := p.Pending(); pending != nil {
+		if !sameRoots(pending, roots) {
+			return errPruneInProgress
+		}
+		log.Info("Resuming interrupted state pruning", "roots", len(roots))
+	} else {
+		// Resume an interrupted marking of the same roots, or start over
+		if marking := p.roots(); marking != nil && sameRoots(marking, roots) {
+			log.Info("Resuming interrupted state marking", "roots", len(roots))
+		} else {
+			if err := p.reset(); err != nil {
+				return err
+			}
+			blob, err := rlp.EncodeToBytes(roots)
+			if err != nil {
+				return err
+			}
+			if err := p.markers.Put(pruneRootsKey, blob); err != nil {
+				return err
+			}
+		}
+		if err := p.mark(roots); err != nil {
+			return err
+		}
+		if err := p.markers.Put(pruneMarkedKey, []byte{0x01}); err != nil {
+			return err
+		}
+	}
+	// Delete everything else and reclaim the disk space
+	if err := p.sweep(); err != nil {
+		return err
+	}
+	start := time.Now()
+	log.Info("Compacting chain database")
+	if err := p.db.LDB().CompactRange(util.Range{}); err != nil {
+		return err
+	}
+	log.Info("Compacted chain database", "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Pruning done, drop the markers
+	p.markers.Close()
+	return os.RemoveAll(p.path)
+}
+
+// reset wipes the marker database.
+func (p *Pruner) reset() error {
+	p.markers.Close()
+	if err := os.RemoveAll(p.path); err != nil {
+		return err
+	}
+	markers, err := ethdb.NewLDBDatabase(p.path, 16, 16)
+	if err != nil {
+		return err
+	}
+	p.markers = markers
+	return nil
+}
+
+// mark records all the trie nodes and contract codes reachable from the roots in
+// the marker database, resuming from the recorded progress. A failure leaves the
+// chain database untouched, the marking is continued on the next run.
+func (p *Pruner) mark(roots []common.Hash) error {
+	var progress markProgress
+	if blob, _ := p.markers.Get(pruneMarkKey); len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, &progress); err != nil {
+			return fmt.Errorf("invalid marking progress: %v", err)
+		}
+	}
+	var (
+		sdb   = NewDatabase(p.db)
+		batch = p.markers.NewBatch()
+
+		marks, nodes, codes int
+		start               = time.Now()
+		logged              = time.Now()
+	)
+	// put batches a mark, keys are counted as the marks have no values
+	put := func(key []byte) error {
+		marks++
+		return batch.Put(key, nil)
+	}
+	// flush writes the batched marks along with the progress they reach
+	flush := func() error {
+		blob, err := rlp.EncodeToBytes(&progress)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(pruneMarkKey, blob); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		marks = 0
+		return nil
+	}
+	// markTrie iterates the trie from the origin key, skipping any subtrie marked
+	// already. Nodes are only marked once their whole subtrie is, so a marked node
+	// implies all its descendants are marked even if the marking was interrupted.
+	var markTrie func(tr Trie, origin []byte, accounts bool) error
+	markTrie = func(tr Trie, origin []byte, accounts bool) error {
+		type pendingNode struct {
+			hash common.Hash
+			path []byte
+		}
+		var stack []pendingNode
+
+		// complete marks the pending nodes whose subtrie does not contain path,
+		// or all of them if path is nil
+		complete := func(path []byte) error {
+			for len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if path != nil && len(path) > len(top.path) && bytes.HasPrefix(path, top.path) {
+					return nil
+				}
+				if err := put(top.hash[:]); err != nil {
+					return err
+				}
+				stack = stack[:len(stack)-1]
+				nodes++
+			}
+			return nil
+		}
+		it := tr.NodeIterator(origin)
+		for descend := true; it.Next(descend); {
+			descend = true
+
+			if err := complete(it.Path()); err != nil {
+				return err
+			}
+			if hash := it.Hash(); hash != (common.Hash{}) {
+				if marked, _ := p.markers.Has(hash[:]); marked {
+					descend = false
+					continue
+				}
+				stack = append(stack, pendingNode{hash, common.CopyBytes(it.Path())})
+			}
+			if accounts && it.Leaf() {
+				var account Account
+				if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+					return err
+				}
+				storage, err := sdb.OpenStorageTrie(common.BytesToHash(it.LeafKey()), account.Root)
+				if err != nil {
+					return err
+				}
+				if err := markTrie(storage, nil, false); err != nil {
+					return err
+				}
+				if !bytes.Equal(account.CodeHash, emptyCodeHash) {
+					if err := put(account.CodeHash); err != nil {
+						return err
+					}
+					codes++
+				}
+				progress.Account = common.CopyBytes(it.LeafKey())
+			}
+			if marks >= pruneMarkBatchSize/common.HashLength {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			if time.Since(logged) > pruneLogInterval {
+				log.Info("Marking reachable state", "nodes", nodes, "codes", codes, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+		if err := it.Error(); err != nil {
+			return err
+		}
+		return complete(nil)
+	}
+	for progress.Root < uint64(len(roots)) {
+		root := roots[progress.Root]
+		tr, err := sdb.OpenTrie(root)
+		if err != nil {
+			return fmt.Errorf("state %x unavailable: %v", root, err)
+		}
+		// A resumed iteration skips the nodes above its origin, so it's followed
+		// by a walk from the start, cheap as it skips all the marked subtries
+		if progress.Account != nil {
+			log.Info("Resuming state marking", "root", root, "account", common.BytesToHash(progress.Account))
+			if err := markTrie(tr, progress.Account, true); err != nil {
+				return fmt.Errorf("state %x incomplete: %v", root, err)
+			}
+		}
+		if err := markTrie(tr, nil, true); err != nil {
+			return fmt.Errorf("state %x incomplete: %v", root, err)
+		}
+		// Flush the root's nodes so that later roots can skip shared subtries
+		progress = markProgress{Root: progress.Root + 1}
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	log.Info("Marked reachable state", "roots", len(roots), "nodes", nodes, "codes", codes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// sweep deletes all the unmarked state entries from the chain database, starting
+// from where an interrupted sweep left off.
+func (p *Pruner) sweep() error {
+	origin, _ := p.markers.Get(pruneSweepKey)
+
+	var (
+		it    = p.db.LDB().NewIterator(&util.Range{Start: origin}, nil)
+		batch = new(leveldb.Batch)
+
+		kept, deleted int
+		size          common.StorageSize
+		start         = time.Now()
+		logged        = time.Now()
+	)
+	defer it.Release()
+
+	// flush deletes the batched entries and records the sweep position
+	flush := func(last []byte) error {
+		if err := p.db.LDB().Write(batch, nil); err != nil {
+			return err
+		}
+		batch.Reset()
+		return p.markers.Put(pruneSweepKey, last)
+	}
+	for it.Next() {
+		// Only trie nodes and contract codes are keyed by their bare hash
+		key := it.Key()
+		if len(key) != common.HashLength {
+			continue
+		}
+		if marked, _ := p.markers.Has(key); marked {
+			kept++
+			continue
+		}
+		batch.Delete(common.CopyBytes(key))
+		deleted++
+		size += common.StorageSize(len(key) + len(it.Value()))
+
+		if batch.Len() >= ethdb.IdealBatchSize/common.HashLength {
+			if err := flush(common.CopyBytes(key)); err != nil {
+				return err
+			}
+		}
+		if time.Since(logged) > pruneLogInterval {
+			log.Info("Pruning state data", "kept", kept, "deleted", deleted, "size", size,
+				"progress", fmt.Sprintf("%.2f%%", float64(key[0])*100/256), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := flush(nil); err != nil {
+		return err
+	}
+	log.Info("Pruned state data", "kept", kept, "deleted", deleted, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// sameRoots reports whether the two root lists are identical.
+func sameRoots(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
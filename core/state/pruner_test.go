@@ -0,0 +1,291 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/rlp"
+)
+
+// makePrunerState creates a chain database with two generations of state, the
+// second one modifying the first, returning both roots.
+func makePrunerState(t *testing.T, dir string) (*ethdb.LDBDatabase, common.Hash, common.Hash) {
+	diskdb, err := ethdb.NewLDBDatabase(filepath.Join(dir, "chaindata"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdb := NewDatabase(diskdb)
+
+	commit := func(state *StateDB) common.Hash {
+		root, err := state.Commit(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sdb.TrieDB().Commit(root, false); err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+	state, _ := New(common.Hash{}, sdb)
+	for i := byte(0); i < 64; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		state.AddBalance(addr, big.NewInt(int64(i)))
+		if i%4 == 0 {
+			state.SetCode(addr, []byte{i, i, i})
+			state.SetState(addr, common.Hash{i}, common.Hash{i, i})
+			state.SetState(addr, common.Hash{i + 1}, common.Hash{i, i})
+		}
+	}
+	oldRoot := commit(state)
+
+	state, _ = New(oldRoot, sdb)
+	for i := byte(0); i < 64; i += 8 {
+		addr := common.BytesToAddress([]byte{i})
+		state.AddBalance(addr, big.NewInt(1))
+		state.SetState(addr, common.Hash{i}, common.Hash{0xff})
+		state.SetCode(addr, []byte{0xff, i})
+	}
+	newRoot := commit(state)
+
+	return diskdb, oldRoot, newRoot
+}
+
+// checkPrunedState verifies that the entire state of root is still available
+// and returns the number of state entries in the database.
+func checkPrunedState(t *testing.T, db *ethdb.LDBDatabase, root common.Hash) int {
+	state, err := New(root, NewDatabase(db))
+	if err != nil {
+		t.Fatalf("retained state missing: %v", err)
+	}
+	it := NewNodeIterator(state)
+	for it.Next() {
+	}
+	if it.Error != nil {
+		t.Fatalf("retained state incomplete: %v", it.Error)
+	}
+	if balance := state.GetBalance(common.BytesToAddress([]byte{8})); balance.Int64() != 9 {
+		t.Errorf("balance mismatch: have %v, want %v", balance, 9)
+	}
+	if value := state.GetState(common.BytesToAddress([]byte{8}), common.Hash{9}); value != (common.Hash{8, 8}) {
+		t.Errorf("storage mismatch: have %x, want %x", value, common.Hash{8, 8})
+	}
+	entries := 0
+	for it := db.NewIterator(); it.Next(); {
+		if len(it.Key()) == common.HashLength {
+			entries++
+		}
+	}
+	return entries
+}
+
+// Tests that pruning removes all the state entries only reachable from stale
+// roots, while leaving the retained state and any other data intact.
+func TestPrunerPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, oldRoot, newRoot := makePrunerState(t, dir)
+	defer db.Close()
+
+	garbage := crypto.Keccak256([]byte("garbage"))
+	db.Put(garbage, []byte("garbage"))
+	db.Put([]byte("LastBlock"), newRoot[:])
+
+	before := checkPrunedState(t, db, newRoot)
+
+	pruner, err := NewPruner(db, filepath.Join(dir, "prunestate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pruner.Prune([]common.Hash{newRoot}); err != nil {
+		t.Fatalf("failed to prune state: %v", err)
+	}
+	after := checkPrunedState(t, db, newRoot)
+	if after >= before {
+		t.Errorf("no state pruned: %d entries before, %d after", before, after)
+	}
+	if has, _ := db.Has(oldRoot[:]); has {
+		t.Errorf("stale state root not pruned")
+	}
+	if has, _ := db.Has(garbage); has {
+		t.Errorf("unreachable entry not pruned")
+	}
+	if has, _ := db.Has(crypto.Keccak256([]byte{4, 4, 4})); !has {
+		t.Errorf("retained contract code pruned")
+	}
+	if has, _ := db.Has(crypto.Keccak256([]byte{8, 8, 8})); has {
+		t.Errorf("replaced contract code not pruned")
+	}
+	if blob, _ := db.Get([]byte("LastBlock")); len(blob) == 0 {
+		t.Errorf("non-state data pruned")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "prunestate")); !os.IsNotExist(err) {
+		t.Errorf("marker database not removed: %v", err)
+	}
+}
+
+// Tests that an interrupted pruning is resumed, and that it can't be continued
+// with a different set of roots once the sweeping could have started.
+func TestPrunerResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, oldRoot, newRoot := makePrunerState(t, dir)
+	defer db.Close()
+
+	// Interrupt a marking, a new pruning may pick other roots
+	path := filepath.Join(dir, "prunestate")
+	pruner, err := NewPruner(db, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pruner.markers.Put(oldRoot[:], nil)
+	pruner.Close()
+
+	if pruner, err = NewPruner(db, path); err != nil {
+		t.Fatal(err)
+	}
+	if pending := pruner.Pending(); pending != nil {
+		t.Fatalf("unfinished marking reported pending: %x", pending)
+	}
+	// Interrupt right after the marking completed
+	roots := []common.Hash{oldRoot, newRoot}
+	blob, _ := rlp.EncodeToBytes(roots)
+
+	if err := pruner.reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pruner.mark(roots); err != nil {
+		t.Fatal(err)
+	}
+	pruner.markers.Put(pruneRootsKey, blob)
+	pruner.markers.Put(pruneMarkedKey, []byte{0x01})
+	pruner.Close()
+
+	if pruner, err = NewPruner(db, path); err != nil {
+		t.Fatal(err)
+	}
+	if pending := pruner.Pending(); !sameRoots(pending, roots) {
+		t.Fatalf("pending roots mismatch: have %x, want %x", pending, roots)
+	}
+	if err := pruner.Prune([]common.Hash{newRoot}); err != errPruneInProgress {
+		t.Fatalf("pruning with other roots error mismatch: have %v, want %v", err, errPruneInProgress)
+	}
+	if err := pruner.Prune(roots); err != nil {
+		t.Fatalf("failed to resume pruning: %v", err)
+	}
+	checkPrunedState(t, db, newRoot)
+
+	state, err := New(oldRoot, NewDatabase(db))
+	if err != nil {
+		t.Fatalf("retained old state missing: %v", err)
+	}
+	it := NewNodeIterator(state)
+	for it.Next() {
+	}
+	if it.Error != nil {
+		t.Fatalf("retained old state incomplete: %v", it.Error)
+	}
+}
+
+// Tests that an interrupted marking is resumed from its recorded progress
+// instead of being started over.
+func TestPrunerResumeMarking(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, oldRoot, newRoot := makePrunerState(t, dir)
+	defer db.Close()
+
+	// Break the last storage trie of the state, failing the marking near its end
+	tr, err := NewDatabase(db).OpenTrie(newRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var broken common.Hash
+	for it := tr.NodeIterator(nil); it.Next(true); {
+		if it.Leaf() {
+			var account Account
+			if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+				t.Fatal(err)
+			}
+			if has, _ := db.Has(account.Root[:]); has {
+				broken = account.Root
+			}
+		}
+	}
+	blob, _ := db.Get(broken[:])
+	db.Delete(broken[:])
+
+	defer func(size int) { pruneMarkBatchSize = size }(pruneMarkBatchSize)
+	pruneMarkBatchSize = 1
+
+	path := filepath.Join(dir, "prunestate")
+	pruner, err := NewPruner(db, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := []common.Hash{newRoot}
+	if err := pruner.Prune(roots); err == nil {
+		t.Fatalf("pruning succeeded with missing state")
+	}
+	if pending := pruner.Pending(); pending != nil {
+		t.Fatalf("interrupted marking reported pending: %x", pending)
+	}
+	var progress markProgress
+	if enc, _ := pruner.markers.Get(pruneMarkKey); rlp.DecodeBytes(enc, &progress) != nil || progress.Account == nil {
+		t.Fatalf("marking progress not recorded: %x", enc)
+	}
+	// Mark an unreachable entry, it is only retained if the marks are reused
+	garbage := crypto.Keccak256([]byte("garbage"))
+	db.Put(garbage, []byte("garbage"))
+	pruner.markers.Put(garbage, nil)
+	pruner.Close()
+
+	db.Put(broken[:], blob)
+	if pruner, err = NewPruner(db, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := pruner.Prune(roots); err != nil {
+		t.Fatalf("failed to resume pruning: %v", err)
+	}
+	checkPrunedState(t, db, newRoot)
+
+	if has, _ := db.Has(garbage); !has {
+		t.Errorf("marking started over")
+	}
+	if has, _ := db.Has(oldRoot[:]); has {
+		t.Errorf("stale state root not pruned")
+	}
+}
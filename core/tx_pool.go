@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
 	"github.com/TTCECO/gttc/core/state"
 	"github.com/TTCECO/gttc/core/types"
 	"github.com/TTCECO/gttc/event"
@@ -121,6 +122,13 @@ type blockChain interface {
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
 
+// engineChain is implemented by chains that expose their consensus engine, used
+// by the pool to discover the optional consensus transaction hooks.
+type engineChain interface {
+	consensus.ChainReader
+	Engine() consensus.Engine
+}
+
 // TxPoolConfig are the configuration parameters of the transaction pool.
 type TxPoolConfig struct {
 	NoLocals  bool          // Whether local transaction handling should be disabled
@@ -135,6 +143,8 @@ type TxPoolConfig struct {
 	AccountQueue uint64 // Maximum number of non-executable transaction slots permitted per account
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
+	ConsensusSlots uint64 // Maximum number of pooled transactions per account admitted as consensus transactions
+
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 }
 
@@ -152,6 +162,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	AccountQueue: 64,
 	GlobalQueue:  1024,
 
+	ConsensusSlots: 4,
+
 	Lifetime: 3 * time.Hour,
 }
 
@@ -193,6 +205,10 @@ type TxPool struct {
 	signer       types.Signer
 	mu           sync.RWMutex
 
+	reader      consensus.ChainReader   // Chain reader handed to the consensus hooks
+	validator   consensus.TxValidator   // Consensus engine hook for engine specific transactions
+	prioritizer consensus.TxPrioritizer // Consensus engine hook to single out consensus transactions
+
 	currentState  *state.StateDB      // Current state in the blockchain head
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	currentMaxGas uint64              // Current gas limit for transaction caps
@@ -230,6 +246,15 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
 		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
 	}
+	if ec, ok := chain.(engineChain); ok {
+		pool.reader = ec
+		if validator, ok := ec.Engine().(consensus.TxValidator); ok {
+			pool.validator = validator
+		}
+		if prioritizer, ok := ec.Engine().(consensus.TxPrioritizer); ok {
+			pool.prioritizer = prioritizer
+		}
+	}
 	pool.locals = newAccountSet(pool.signer)
 	pool.priced = newTxPricedList(pool.all)
 	pool.reset(nil, chain.CurrentBlock().Header())
@@ -409,6 +434,10 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
+	if pool.validator != nil {
+		pool.validator.PrepareTxValidation(pool.reader, newHead)
+	}
+
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	pool.addTxsLocked(reinject, false)
@@ -591,9 +620,40 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.Gas() < intrGas {
 		return ErrIntrinsicGas
 	}
+	// Let the consensus engine reject payloads it would ignore or refuse anyway,
+	// even if a block including them would still be valid
+	if pool.validator != nil {
+		if err := pool.validator.ValidateTx(pool.reader, pool.currentState, tx, from); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// isConsensusTx returns whether the consensus engine relies on the transaction,
+// like block confirmations of the current signers. Only the first ConsensusSlots
+// pooled transactions of an account are singled out.
+func (pool *TxPool) isConsensusTx(tx *types.Transaction) bool {
+	if pool.prioritizer == nil {
+		return false
+	}
+	from, err := types.Sender(pool.signer, tx)
+	if err != nil {
+		return false
+	}
+	pooled := 0
+	if list := pool.pending[from]; list != nil {
+		pooled += list.Len()
+	}
+	if list := pool.queue[from]; list != nil {
+		pooled += list.Len()
+	}
+	if uint64(pooled) >= pool.config.ConsensusSlots {
+		return false
+	}
+	return pool.prioritizer.IsConsensusTx(pool.reader, tx, from)
+}
+
 // add validates a transaction and inserts it into the non-executable queue for
 // later pending promotion and execution. If the transaction is a replacement for
 // an already pending or queued one, it overwrites the previous and returns this
@@ -617,8 +677,9 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	}
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
-		// If the new transaction is underpriced, don't accept it
-		if !local && pool.priced.Underpriced(tx, pool.locals) {
+		// If the new transaction is underpriced, don't accept it, unless local or
+		// a consensus transaction still paying the minimum gas price
+		if !local && !pool.isConsensusTx(tx) && pool.priced.Underpriced(tx, pool.locals) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
 			underpricedTxCounter.Inc(1)
 			return false, ErrUnderpriced
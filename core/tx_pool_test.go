@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
 	"github.com/TTCECO/gttc/core/state"
 	"github.com/TTCECO/gttc/core/types"
 	"github.com/TTCECO/gttc/crypto"
@@ -1421,6 +1422,67 @@ func TestTransactionPoolUnderpricing(t *testing.T) {
 	}
 }
 
+// testPrioritizer marks every transaction of the given accounts as a consensus
+// transaction.
+type testPrioritizer map[common.Address]bool
+
+func (p testPrioritizer) IsConsensusTx(chain consensus.ChainReader, tx *types.Transaction, sender common.Address) bool {
+	return p[sender]
+}
+
+// Tests that consensus transactions singled out by the consensus engine still
+// pay the minimum gas price, but are admitted into a full pool without outbidding
+// the pooled transactions, up to ConsensusSlots transactions per account.
+func TestTransactionPoolConsensusPricing(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+	config.ConsensusSlots = 1
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	for _, k := range []*ecdsa.PrivateKey{key, other} {
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(k.PublicKey), big.NewInt(100000000))
+	}
+	pool.SetGasPrice(big.NewInt(10))
+	pool.prioritizer = testPrioritizer{crypto.PubkeyToAddress(key.PublicKey): true}
+
+	// The minimum gas price applies to consensus transactions too
+	if err := pool.AddRemote(pricedTransaction(0, 100000, big.NewInt(1), key)); err != ErrUnderpriced {
+		t.Error("expected", ErrUnderpriced, "got", err)
+	}
+	// Fill the pool up with expensive transactions
+	for i := uint64(0); i < 4; i++ {
+		if err := pool.AddRemote(pricedTransaction(i, 100000, big.NewInt(100), other)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	if err := pool.AddRemote(pricedTransaction(4, 100000, big.NewInt(10), other)); err != ErrUnderpriced {
+		t.Error("expected", ErrUnderpriced, "got", err)
+	}
+	if err := pool.AddRemote(pricedTransaction(0, 100000, big.NewInt(10), key)); err != nil {
+		t.Error("expected", nil, "got", err)
+	}
+	// Further transactions of the account are regular ones
+	if err := pool.AddRemote(pricedTransaction(1, 100000, big.NewInt(10), key)); err != ErrUnderpriced {
+		t.Error("expected", ErrUnderpriced, "got", err)
+	}
+	if pool.locals.contains(crypto.PubkeyToAddress(key.PublicKey)) {
+		t.Error("consensus transaction sender marked as local")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that more expensive transactions push out cheap ones from the pool, but
 // without producing instability by creating gaps that start jumping transactions
 // back and forth between queued/pending.
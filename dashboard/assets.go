@@ -89,7 +89,7 @@ func indexHtml() (*asset, error) {
 }
 
 //nolint:misspell
-var _bundleJs = []byte((((((((((`!function(modules) {
+var _bundleJs = []byte(`!function(modules) {
     function __webpack_require__(moduleId) {
         if (installedModules[moduleId]) return installedModules[moduleId].exports;
         var module = installedModules[moduleId] = {
@@ -730,10 +730,10 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                                 renderedClasses = sheetsManagerTheme.sheet.classes;
                             }
                             classes = classesProp ? (0, _extends3.default)({}, renderedClasses, (0, _keys2.default)(classesProp).reduce(function(accumulator, key) {
-                                return "production" !== process.env.NODE_ENV && (0, _warning2.default)(renderedClasses[key] || _this3.disableStylesGeneration, [ "Material-UI: the key ` + "`") + (`" + key + "` + ("`" + ` provided to the classes property is not implemented in " + (0, 
+                                return "production" !== process.env.NODE_ENV && (0, _warning2.default)(renderedClasses[key] || _this3.disableStylesGeneration, [ "Material-UI: the key ` + "`" + `" + key + "` + "`" + ` provided to the classes property is not implemented in " + (0, 
                                 _getDisplayName2.default)(Component) + ".", "You can only override one of the following: " + (0, 
                                 _keys2.default)(renderedClasses).join(",") ].join("\n")), "production" !== process.env.NODE_ENV && (0, 
-                                _warning2.default)(!classesProp[key] || "string" == typeof classesProp[key], [ "Material-UI: the key `))) + (("`" + (`" + key + "` + "`")) + (` provided to the classes property is not valid for " + (0, 
+                                _warning2.default)(!classesProp[key] || "string" == typeof classesProp[key], [ "Material-UI: the key ` + "`" + `" + key + "` + "`" + ` provided to the classes property is not valid for " + (0, 
                                 _getDisplayName2.default)(Component) + ".", "You need to provide a non empty string instead of: " + classesProp[key] + "." ].join("\n")), 
                                 classesProp[key] && (accumulator[key] = renderedClasses[key] + " " + classesProp[key]), 
                                 accumulator;
@@ -772,7 +772,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             var len = arguments.length;
             args = new Array(len > 2 ? len - 2 : 0);
             for (var key = 2; key < len; key++) args[key - 2] = arguments[key];
-            if (void 0 === format) throw new Error("` + ("`" + `warning(condition, format, ...args)`)))) + ((("`" + (` requires a warning message argument");
+            if (void 0 === format) throw new Error("` + "`" + `warning(condition, format, ...args)` + "`" + ` requires a warning message argument");
             if (format.length < 10 || /^[s\W]*$/.test(format)) throw new Error("The warning format should be able to uniquely identify this warning. Please, use a more descriptive format than: " + format);
             if (!condition) {
                 var argIndex = 0, message = "Warning: " + format.replace(/%s/g, function() {
@@ -4421,6 +4421,12 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             title: "Network",
             icon: "globe"
         }
+    }, {
+        id: "alien",
+        menu: {
+            title: "Alien",
+            icon: "users"
+        }
     }, {
         id: "system",
         menu: {
@@ -4858,7 +4864,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 } catch (x) {}
             };
             warning = function(condition, format) {
-                if (void 0 === format) throw new Error("` + "`")) + (`warning(condition, format, ...args)` + ("`" + ` requires a warning message argument");
+                if (void 0 === format) throw new Error("` + "`" + `warning(condition, format, ...args)` + "`" + ` requires a warning message argument");
                 if (0 !== format.indexOf("Failed Composite propType: ") && !condition) {
                     for (var _len2 = arguments.length, args = Array(_len2 > 2 ? _len2 - 2 : 0), _key2 = 2; _key2 < _len2; _key2++) args[_key2 - 2] = arguments[_key2];
                     printWarning.apply(void 0, [ format ].concat(args));
@@ -6195,12 +6201,12 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             if ("production" !== process.env.NODE_ENV) for (var typeSpecName in typeSpecs) if (typeSpecs.hasOwnProperty(typeSpecName)) {
                 var error;
                 try {
-                    invariant("function" == typeof typeSpecs[typeSpecName], "%s: %s type `))) + (("`" + (`%s` + "`")) + (` is invalid; it must be a function, usually from the ` + ("`" + `prop-types`))))) + (((("`" + (` package, but received ` + "`")) + (`%s` + ("`" + `.", componentName || "React class", location, typeSpecName, typeof typeSpecs[typeSpecName]), 
+                    invariant("function" == typeof typeSpecs[typeSpecName], "%s: %s type ` + "`" + `%s` + "`" + ` is invalid; it must be a function, usually from the ` + "`" + `prop-types` + "`" + ` package, but received ` + "`" + `%s` + "`" + `.", componentName || "React class", location, typeSpecName, typeof typeSpecs[typeSpecName]), 
                     error = typeSpecs[typeSpecName](values, typeSpecName, componentName, location, null, ReactPropTypesSecret);
                 } catch (ex) {
                     error = ex;
                 }
-                if (warning(!error || error instanceof Error, "%s: type specification of %s `))) + (("`" + (`%s` + "`")) + (` is invalid; the type checker function must return ` + ("`" + `null`)))) + ((("`" + (` or an ` + "`")) + (`Error` + ("`" + ` but returned a %s. You may have forgotten to pass an argument to the type checker creator (arrayOf, instanceOf, objectOf, oneOf, oneOfType, and shape all require an argument).", componentName || "React class", location, typeSpecName, typeof error), 
+                if (warning(!error || error instanceof Error, "%s: type specification of %s ` + "`" + `%s` + "`" + ` is invalid; the type checker function must return ` + "`" + `null` + "`" + ` or an ` + "`" + `Error` + "`" + ` but returned a %s. You may have forgotten to pass an argument to the type checker creator (arrayOf, instanceOf, objectOf, oneOf, oneOfType, and shape all require an argument).", componentName || "React class", location, typeSpecName, typeof error), 
                 error instanceof Error && !(error.message in loggedTypeFailures)) {
                     loggedTypeFailures[error.message] = !0;
                     var stack = getStack ? getStack() : "";
@@ -9951,7 +9957,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
         "-": 189,
         ".": 190,
         "/": 191,
-        "`))) + (("`" + (`": 192,
+        "` + "`" + `": 192,
         "[": 219,
         "\\": 220,
         "]": 221,
@@ -14530,7 +14536,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             }
             function defineKeyPropWarningGetter(props, displayName) {
                 var warnAboutAccessingKey = function() {
-                    specialPropKeyWarningShown || (specialPropKeyWarningShown = !0, warning(!1, "%s: ` + "`")) + (`key` + ("`" + ` is not a prop. Trying to access it will result in `)))))) + ((((("`" + `undefined`) + ("`" + (` being returned. If you need to access the same value within the child component, you should pass it as a different prop. (https://fb.me/react-special-props)", displayName));
+                    specialPropKeyWarningShown || (specialPropKeyWarningShown = !0, warning(!1, "%s: ` + "`" + `key` + "`" + ` is not a prop. Trying to access it will result in ` + "`" + `undefined` + "`" + ` being returned. If you need to access the same value within the child component, you should pass it as a different prop. (https://fb.me/react-special-props)", displayName));
                 };
                 warnAboutAccessingKey.isReactWarning = !0, Object.defineProperty(props, "key", {
                     get: warnAboutAccessingKey,
@@ -14539,7 +14545,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             }
             function defineRefPropWarningGetter(props, displayName) {
                 var warnAboutAccessingRef = function() {
-                    specialPropRefWarningShown || (specialPropRefWarningShown = !0, warning(!1, "%s: ` + "`"))) + ((`ref` + ("`" + ` is not a prop. Trying to access it will result in `)) + ("`" + (`undefined` + "`")))) + (((` being returned. If you need to access the same value within the child component, you should pass it as a different prop. (https://fb.me/react-special-props)", displayName));
+                    specialPropRefWarningShown || (specialPropRefWarningShown = !0, warning(!1, "%s: ` + "`" + `ref` + "`" + ` is not a prop. Trying to access it will result in ` + "`" + `undefined` + "`" + ` being returned. If you need to access the same value within the child component, you should pass it as a different prop. (https://fb.me/react-special-props)", displayName));
                 };
                 warnAboutAccessingRef.isReactWarning = !0, Object.defineProperty(props, "ref", {
                     get: warnAboutAccessingRef,
@@ -14709,7 +14715,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             function getDeclarationErrorAddendum() {
                 if (ReactCurrentOwner.current) {
                     var name = getComponentName(ReactCurrentOwner.current);
-                    if (name) return "\n\nCheck the render method of ` + ("`" + `" + name + "`)) + ("`" + (`.";
+                    if (name) return "\n\nCheck the render method of ` + "`" + `" + name + "` + "`" + `.";
                 }
                 return "";
             }
@@ -14756,8 +14762,8 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     var name = componentClass.displayName || componentClass.name, propTypes = componentClass.propTypes;
                     propTypes ? (currentlyValidatingElement = element, checkPropTypes(propTypes, element.props, "prop", name, getStackAddendum), 
                     currentlyValidatingElement = null) : void 0 === componentClass.PropTypes || propTypesMisspellWarningShown || (propTypesMisspellWarningShown = !0, 
-                    warning(!1, "Component %s declared ` + "`"))) + ((`PropTypes` + ("`" + ` instead of `)) + ("`" + (`propTypes` + "`"))))) + ((((`. Did you misspell the property assignment?", name || "Unknown")), 
-                    "function" == typeof componentClass.getDefaultProps && warning(componentClass.getDefaultProps.isReactClassApproved, "getDefaultProps is only used on classic React.createClass definitions. Use a static property named ` + ("`" + `defaultProps`)) + ("`" + (` instead.");
+                    warning(!1, "Component %s declared ` + "`" + `PropTypes` + "`" + ` instead of ` + "`" + `propTypes` + "`" + `. Did you misspell the property assignment?", name || "Unknown")), 
+                    "function" == typeof componentClass.getDefaultProps && warning(componentClass.getDefaultProps.isReactClassApproved, "getDefaultProps is only used on classic React.createClass definitions. Use a static property named ` + "`" + `defaultProps` + "`" + ` instead.");
                 }
             }
             function validateFragmentProps(fragment) {
@@ -14767,7 +14773,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     for (var _step, _iterator = Object.keys(fragment.props)[Symbol.iterator](); !(_iteratorNormalCompletion = (_step = _iterator.next()).done); _iteratorNormalCompletion = !0) {
                         var key = _step.value;
                         if (!VALID_FRAGMENT_PROPS.has(key)) {
-                            warning(!1, "Invalid prop ` + "`"))) + ((`%s` + ("`" + ` supplied to `)) + ("`" + (`React.Fragment` + "`")))) + (((`. React.Fragment can only have ` + ("`" + `key`)) + ("`" + (` and ` + "`"))) + ((`children` + ("`" + ` props.%s", key, getStackAddendum());
+                            warning(!1, "Invalid prop ` + "`" + `%s` + "`" + ` supplied to ` + "`" + `React.Fragment` + "`" + `. React.Fragment can only have ` + "`" + `key` + "`" + ` and ` + "`" + `children` + "`" + ` props.%s", key, getStackAddendum());
                             break;
                         }
                     }
@@ -14780,7 +14786,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                         if (_didIteratorError) throw _iteratorError;
                     }
                 }
-                null !== fragment.ref && warning(!1, "Invalid attribute `)) + ("`" + (`ref` + "`"))))))) + ((((((` supplied to ` + "`") + (`React.Fragment` + ("`" + `.%s", getStackAddendum()), 
+                null !== fragment.ref && warning(!1, "Invalid attribute ` + "`" + `ref` + "`" + ` supplied to ` + "`" + `React.Fragment` + "`" + `.%s", getStackAddendum()), 
                 currentlyValidatingElement = null;
             }
             function createElementWithValidation(type, props, children) {
@@ -14825,7 +14831,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 } catch (x) {}
             };
             lowPriorityWarning = function(condition, format) {
-                if (void 0 === format) throw new Error("`))) + (("`" + (`warning(condition, format, ...args)` + "`")) + (` requires a warning message argument");
+                if (void 0 === format) throw new Error("` + "`" + `warning(condition, format, ...args)` + "`" + ` requires a warning message argument");
                 if (!condition) {
                     for (var _len2 = arguments.length, args = Array(_len2 > 2 ? _len2 - 2 : 0), _key2 = 2; _key2 < _len2; _key2++) args[_key2 - 2] = arguments[_key2];
                     printWarning.apply(void 0, [ format ].concat(args));
@@ -18905,17 +18911,17 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             function recomputePluginOrdering() {
                 if (eventPluginOrder) for (var pluginName in namesToPlugins) {
                     var pluginModule = namesToPlugins[pluginName], pluginIndex = eventPluginOrder.indexOf(pluginName);
-                    if (pluginIndex > -1 || invariant(!1, "EventPluginRegistry: Cannot inject event plugins that do not exist in the plugin ordering, ` + ("`" + `%s`)))) + ((("`" + (`.", pluginName), 
+                    if (pluginIndex > -1 || invariant(!1, "EventPluginRegistry: Cannot inject event plugins that do not exist in the plugin ordering, ` + "`" + `%s` + "`" + `.", pluginName), 
                     !plugins[pluginIndex]) {
-                        pluginModule.extractEvents || invariant(!1, "EventPluginRegistry: Event plugins must implement an ` + "`")) + (`extractEvents` + ("`" + ` method, but `))) + (("`" + (`%s` + "`")) + (` does not.", pluginName), 
+                        pluginModule.extractEvents || invariant(!1, "EventPluginRegistry: Event plugins must implement an ` + "`" + `extractEvents` + "`" + ` method, but ` + "`" + `%s` + "`" + ` does not.", pluginName), 
                         plugins[pluginIndex] = pluginModule;
                         var publishedEvents = pluginModule.eventTypes;
-                        for (var eventName in publishedEvents) publishEventForPlugin(publishedEvents[eventName], pluginModule, eventName) || invariant(!1, "EventPluginRegistry: Failed to publish event ` + ("`" + `%s`))))) + (((("`" + (` for plugin ` + "`")) + (`%s` + ("`" + `.", eventName, pluginName);
+                        for (var eventName in publishedEvents) publishEventForPlugin(publishedEvents[eventName], pluginModule, eventName) || invariant(!1, "EventPluginRegistry: Failed to publish event ` + "`" + `%s` + "`" + ` for plugin ` + "`" + `%s` + "`" + `.", eventName, pluginName);
                     }
                 }
             }
             function publishEventForPlugin(dispatchConfig, pluginModule, eventName) {
-                eventNameDispatchConfigs.hasOwnProperty(eventName) && invariant(!1, "EventPluginHub: More than one plugin attempted to publish the same event name, `))) + (("`" + (`%s` + "`")) + (`.", eventName), 
+                eventNameDispatchConfigs.hasOwnProperty(eventName) && invariant(!1, "EventPluginHub: More than one plugin attempted to publish the same event name, ` + "`" + `%s` + "`" + `.", eventName), 
                 eventNameDispatchConfigs[eventName] = dispatchConfig;
                 var phasedRegistrationNames = dispatchConfig.phasedRegistrationNames;
                 if (phasedRegistrationNames) {
@@ -18929,7 +18935,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 !0);
             }
             function publishRegistrationName(registrationName, pluginModule, eventName) {
-                registrationNameModules[registrationName] && invariant(!1, "EventPluginHub: More than one plugin attempted to publish the same registration name, ` + ("`" + `%s`)))) + ((("`" + (`.", registrationName), 
+                registrationNameModules[registrationName] && invariant(!1, "EventPluginHub: More than one plugin attempted to publish the same registration name, ` + "`" + `%s` + "`" + `.", registrationName), 
                 registrationNameModules[registrationName] = pluginModule, registrationNameDependencies[registrationName] = pluginModule.eventTypes[eventName].dependencies;
                 var lowerCasedName = registrationName.toLowerCase();
                 possibleRegistrationNames[lowerCasedName] = registrationName, "onDoubleClick" === registrationName && (possibleRegistrationNames.ondblclick = registrationName);
@@ -18942,7 +18948,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 var isOrderingDirty = !1;
                 for (var pluginName in injectedNamesToPlugins) if (injectedNamesToPlugins.hasOwnProperty(pluginName)) {
                     var pluginModule = injectedNamesToPlugins[pluginName];
-                    namesToPlugins.hasOwnProperty(pluginName) && namesToPlugins[pluginName] === pluginModule || (namesToPlugins[pluginName] && invariant(!1, "EventPluginRegistry: Cannot inject two different event plugins using the same name, ` + "`")) + (`%s` + ("`" + `.", pluginName), 
+                    namesToPlugins.hasOwnProperty(pluginName) && namesToPlugins[pluginName] === pluginModule || (namesToPlugins[pluginName] && invariant(!1, "EventPluginRegistry: Cannot inject two different event plugins using the same name, ` + "`" + `%s` + "`" + `.", pluginName), 
                     namesToPlugins[pluginName] = pluginModule, isOrderingDirty = !0);
                 }
                 isOrderingDirty && recomputePluginOrdering();
@@ -18990,7 +18996,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 var listener, stateNode = inst.stateNode;
                 if (!stateNode) return null;
                 var props = getFiberCurrentPropsFromNode(stateNode);
-                return props ? (listener = props[registrationName], shouldPreventMouseEvent(registrationName, inst.type, props) ? null : (listener && "function" != typeof listener && invariant(!1, "Expected `))) + (("`" + (`%s` + "`")) + (` listener to be a function, instead got a value of ` + ("`" + `%s`)))))) + ((((("`" + ` type.", registrationName, typeof listener), 
+                return props ? (listener = props[registrationName], shouldPreventMouseEvent(registrationName, inst.type, props) ? null : (listener && "function" != typeof listener && invariant(!1, "Expected ` + "`" + `%s` + "`" + ` listener to be a function, instead got a value of ` + "`" + `%s` + "`" + ` type.", registrationName, typeof listener), 
                 listener)) : null;
             }
             function extractEvents(topLevelType, targetInst, nativeEvent, nativeEventTarget) {
@@ -19170,7 +19176,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     getVal;
                 }
                 function warn(action, result) {
-                    warning(!1, "This synthetic event is reused for performance reasons. If you're seeing this, you're %s `) + ("`" + (`%s` + "`"))) + ((` on a released/nullified synthetic event. %s. If you must keep the original synthetic event around, use event.persist(). See https://fb.me/react-event-pooling for more information.", action, propName, result);
+                    warning(!1, "This synthetic event is reused for performance reasons. If you're seeing this, you're %s ` + "`" + `%s` + "`" + ` on a released/nullified synthetic event. %s. If you must keep the original synthetic event around, use event.persist(). See https://fb.me/react-event-pooling for more information.", action, propName, result);
                 }
                 var isFunction = "function" == typeof getVal;
                 return {
@@ -20182,7 +20188,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     var info = "";
                     (void 0 === type || "object" == typeof type && null !== type && 0 === Object.keys(type).length) && (info += " You likely forgot to export your component from the file it's defined in, or you might have mixed up default and named imports.");
                     var ownerName = owner ? getComponentName(owner) : null;
-                    ownerName && (info += "\n\nCheck the render method of ` + ("`" + `" + ownerName + "`)) + ("`" + (`."), invariant(!1, "Element type is invalid: expected a string (for built-in components) or a class/function (for composite components) but got: %s.%s", null == type ? type : typeof type, info);
+                    ownerName && (info += "\n\nCheck the render method of ` + "`" + `" + ownerName + "` + "`" + `."), invariant(!1, "Element type is invalid: expected a string (for built-in components) or a class/function (for composite components) but got: %s.%s", null == type ? type : typeof type, info);
                 }
                 return fiber._debugSource = element._source, fiber._debugOwner = element._owner, 
                 fiber.expirationTime = expirationTime, fiber;
@@ -20583,7 +20589,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                             knownKeys.add(key);
                             break;
                         }
-                        warning(!1, "Encountered two children with the same key, ` + "`")))) + (((`%s` + ("`" + `. Keys should be unique so that components maintain their identity across updates. Non-unique keys may cause children to be duplicated and/or omitted — the behavior is unsupported and could change in a future version.%s", key, getCurrentFiberStackAddendum$1());
+                        warning(!1, "Encountered two children with the same key, ` + "`" + `%s` + "`" + `. Keys should be unique so that components maintain their identity across updates. Non-unique keys may cause children to be duplicated and/or omitted — the behavior is unsupported and could change in a future version.%s", key, getCurrentFiberStackAddendum$1());
                     }
                     return knownKeys;
                 }
@@ -20818,7 +20824,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             }
             function isAttributeNameSafe(attributeName) {
                 return !!validatedAttributeNameCache.hasOwnProperty(attributeName) || !illegalAttributeNameCache.hasOwnProperty(attributeName) && (VALID_ATTRIBUTE_NAME_REGEX.test(attributeName) ? (validatedAttributeNameCache[attributeName] = !0, 
-                !0) : (illegalAttributeNameCache[attributeName] = !0, warning(!1, "Invalid attribute name: `)) + ("`" + (`%s` + "`"))) + ((`", attributeName), 
+                !0) : (illegalAttributeNameCache[attributeName] = !0, warning(!1, "Invalid attribute name: ` + "`" + `%s` + "`" + `", attributeName), 
                 !1));
             }
             function shouldIgnoreValue(propertyInfo, value) {
@@ -20958,7 +20964,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                         var otherNode = group[i];
                         if (otherNode !== rootNode && otherNode.form === rootNode.form) {
                             var otherProps = getFiberCurrentPropsFromNode$1(otherNode);
-                            otherProps || invariant(!1, "ReactDOMInput: Mixing React and non-React radio inputs with the same ` + ("`" + `name`)) + ("`" + (` is not supported."), 
+                            otherProps || invariant(!1, "ReactDOMInput: Mixing React and non-React radio inputs with the same ` + "`" + `name` + "`" + ` is not supported."), 
                             updateValueIfChanged(otherNode), updateWrapper(otherNode, otherProps);
                         }
                     }
@@ -20971,7 +20977,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 }), content;
             }
             function validateProps(element, props) {
-                warning(null == props.selected, "Use the ` + "`"))))) + ((((`defaultValue` + ("`" + ` or `)) + ("`" + (`value` + "`"))) + ((` props on <select> instead of setting ` + ("`" + `selected`)) + ("`" + (` on <option>.");
+                warning(null == props.selected, "Use the ` + "`" + `defaultValue` + "`" + ` or ` + "`" + `value` + "`" + ` props on <select> instead of setting ` + "`" + `selected` + "`" + ` on <option>.");
             }
             function postMountWrapper$1(element, props) {
                 null != props.value && element.setAttribute("value", props.value);
@@ -20984,7 +20990,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             }
             function getDeclarationErrorAddendum() {
                 var ownerName = getCurrentFiberOwnerName$3();
-                return ownerName ? "\n\nCheck the render method of ` + "`")))) + (((`" + ownerName + "` + ("`" + `." : "";
+                return ownerName ? "\n\nCheck the render method of ` + "`" + `" + ownerName + "` + "`" + `." : "";
             }
             function checkSelectPropTypes(props) {
                 ReactControlledValuePropTypes.checkPropTypes("select", props, getCurrentFiberStackAddendum$4);
@@ -20992,7 +20998,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     var propName = valuePropNames[i];
                     if (null != props[propName]) {
                         var isArray = Array.isArray(props[propName]);
-                        props.multiple && !isArray ? warning(!1, "The `)) + ("`" + (`%s` + "`"))) + ((` prop supplied to <select> must be an array if ` + ("`" + `multiple`)) + ("`" + (` is true.%s", propName, getDeclarationErrorAddendum()) : !props.multiple && isArray && warning(!1, "The ` + "`")))))))) + (((((((`%s` + "`") + (` prop supplied to <select> must be a scalar value if ` + ("`" + `multiple`))) + (("`" + (` is false.%s", propName, getDeclarationErrorAddendum());
+                        props.multiple && !isArray ? warning(!1, "The ` + "`" + `%s` + "`" + ` prop supplied to <select> must be an array if ` + "`" + `multiple` + "`" + ` is true.%s", propName, getDeclarationErrorAddendum()) : !props.multiple && isArray && warning(!1, "The ` + "`" + `%s` + "`" + ` prop supplied to <select> must be a scalar value if ` + "`" + `multiple` + "`" + ` is false.%s", propName, getDeclarationErrorAddendum());
                     }
                 }
             }
@@ -21047,7 +21053,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             }
             function getHostProps$3(element, props) {
                 var node = element;
-                return null != props.dangerouslySetInnerHTML && invariant(!1, "` + "`")) + (`dangerouslySetInnerHTML` + ("`" + ` does not make sense on <textarea>."), 
+                return null != props.dangerouslySetInnerHTML && invariant(!1, "` + "`" + `dangerouslySetInnerHTML` + "`" + ` does not make sense on <textarea>."), 
                 _assign({}, props, {
                     value: void 0,
                     defaultValue: void 0,
@@ -21062,8 +21068,8 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 var initialValue = props.value;
                 if (null == initialValue) {
                     var defaultValue = props.defaultValue, children = props.children;
-                    null != children && (warning(!1, "Use the `)))) + ((("`" + (`defaultValue` + "`")) + (` or ` + ("`" + `value`))) + (("`" + (` props instead of setting children on <textarea>."), 
-                    null != defaultValue && invariant(!1, "If you supply ` + "`")) + (`defaultValue` + ("`" + ` on a <textarea>, do not pass children."), 
+                    null != children && (warning(!1, "Use the ` + "`" + `defaultValue` + "`" + ` or ` + "`" + `value` + "`" + ` props instead of setting children on <textarea>."), 
+                    null != defaultValue && invariant(!1, "If you supply ` + "`" + `defaultValue` + "`" + ` on a <textarea>, do not pass children."), 
                     Array.isArray(children) && (children.length <= 1 || invariant(!1, "<textarea> can only have at most one child."), 
                     children = children[0]), defaultValue = "" + children), null == defaultValue && (defaultValue = ""), 
                     initialValue = defaultValue;
@@ -21130,11 +21136,11 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 }
             }
             function assertValidProps(tag, props, getStack) {
-                props && (voidElementTags[tag] && (null != props.children || null != props.dangerouslySetInnerHTML) && invariant(!1, "%s is a void element tag and must neither have `))))) + (((("`" + (`children` + "`")) + (` nor use ` + ("`" + `dangerouslySetInnerHTML`))) + (("`" + (`.%s", tag, getStack()), 
-                null != props.dangerouslySetInnerHTML && (null != props.children && invariant(!1, "Can only set one of ` + "`")) + (`children` + ("`" + ` or `)))) + ((("`" + (`props.dangerouslySetInnerHTML` + "`")) + (`."), 
-                "object" == typeof props.dangerouslySetInnerHTML && HTML$1 in props.dangerouslySetInnerHTML || invariant(!1, "` + ("`" + `props.dangerouslySetInnerHTML`))) + (("`" + (` must be in the form ` + "`")) + (`{__html: ...}` + ("`" + `. Please visit https://fb.me/react-invariant-dangerously-set-inner-html for more information.")), 
-                warning(props.suppressContentEditableWarning || !props.contentEditable || null == props.children, "A component is `)))))) + ((((("`" + `contentEditable`) + ("`" + (` and contains ` + "`"))) + ((`children` + ("`" + ` managed by React. It is now your responsibility to guarantee that none of those nodes are unexpectedly modified or duplicated. This is probably not intentional.%s", getStack()), 
-                null != props.style && "object" != typeof props.style && invariant(!1, "The `)) + ("`" + (`style` + "`")))) + (((` prop expects a mapping from style properties to values, not a string. For example, style={{marginRight: spacing + 'em'}} when using JSX.%s", getStack()));
+                props && (voidElementTags[tag] && (null != props.children || null != props.dangerouslySetInnerHTML) && invariant(!1, "%s is a void element tag and must neither have ` + "`" + `children` + "`" + ` nor use ` + "`" + `dangerouslySetInnerHTML` + "`" + `.%s", tag, getStack()), 
+                null != props.dangerouslySetInnerHTML && (null != props.children && invariant(!1, "Can only set one of ` + "`" + `children` + "`" + ` or ` + "`" + `props.dangerouslySetInnerHTML` + "`" + `."), 
+                "object" == typeof props.dangerouslySetInnerHTML && HTML$1 in props.dangerouslySetInnerHTML || invariant(!1, "` + "`" + `props.dangerouslySetInnerHTML` + "`" + ` must be in the form ` + "`" + `{__html: ...}` + "`" + `. Please visit https://fb.me/react-invariant-dangerously-set-inner-html for more information.")), 
+                warning(props.suppressContentEditableWarning || !props.contentEditable || null == props.children, "A component is ` + "`" + `contentEditable` + "`" + ` and contains ` + "`" + `children` + "`" + ` managed by React. It is now your responsibility to guarantee that none of those nodes are unexpectedly modified or duplicated. This is probably not intentional.%s", getStack()), 
+                null != props.style && "object" != typeof props.style && invariant(!1, "The ` + "`" + `style` + "`" + ` prop expects a mapping from style properties to values, not a string. For example, style={{marginRight: spacing + 'em'}} when using JSX.%s", getStack()));
             }
             function isCustomComponent(tagName, props) {
                 if (-1 === tagName.indexOf("-")) return "string" == typeof props.is;
@@ -21161,15 +21167,15 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 if (hasOwnProperty.call(warnedProperties, name) && warnedProperties[name]) return !0;
                 if (rARIACamel.test(name)) {
                     var ariaName = "aria-" + name.slice(4).toLowerCase(), correctName = ariaProperties.hasOwnProperty(ariaName) ? ariaName : null;
-                    if (null == correctName) return warning(!1, "Invalid ARIA attribute ` + ("`" + `%s`)) + ("`" + (`. ARIA attributes follow the pattern aria-* and must be lowercase.%s", name, getStackAddendum()), 
+                    if (null == correctName) return warning(!1, "Invalid ARIA attribute ` + "`" + `%s` + "`" + `. ARIA attributes follow the pattern aria-* and must be lowercase.%s", name, getStackAddendum()), 
                     warnedProperties[name] = !0, !0;
-                    if (name !== correctName) return warning(!1, "Invalid ARIA attribute ` + "`"))) + ((`%s` + ("`" + `. Did you mean `)) + ("`" + (`%s` + "`"))))) + ((((`?%s", name, correctName, getStackAddendum()), 
+                    if (name !== correctName) return warning(!1, "Invalid ARIA attribute ` + "`" + `%s` + "`" + `. Did you mean ` + "`" + `%s` + "`" + `?%s", name, correctName, getStackAddendum()), 
                     warnedProperties[name] = !0, !0;
                 }
                 if (rARIA.test(name)) {
                     var lowerCasedName = name.toLowerCase(), standardName = ariaProperties.hasOwnProperty(lowerCasedName) ? lowerCasedName : null;
                     if (null == standardName) return warnedProperties[name] = !0, !1;
-                    if (name !== standardName) return warning(!1, "Unknown ARIA attribute ` + ("`" + `%s`)) + ("`" + (`. Did you mean ` + "`"))) + ((`%s` + ("`" + `?%s", name, standardName, getStackAddendum()), 
+                    if (name !== standardName) return warning(!1, "Unknown ARIA attribute ` + "`" + `%s` + "`" + `. Did you mean ` + "`" + `%s` + "`" + `?%s", name, standardName, getStackAddendum()), 
                     warnedProperties[name] = !0, !0;
                 }
                 return !0;
@@ -21180,7 +21186,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     validateProperty(type, key) || invalidProps.push(key);
                 }
                 var unknownPropString = invalidProps.map(function(prop) {
-                    return "`)) + ("`" + (`" + prop + "` + "`")))) + (((`";
+                    return "` + "`" + `" + prop + "` + "`" + `";
                 }).join(", ");
                 1 === invalidProps.length ? warning(!1, "Invalid aria prop %s on <%s> tag. For details, see https://fb.me/invalid-aria-prop%s", unknownPropString, type, getStackAddendum()) : invalidProps.length > 1 && warning(!1, "Invalid aria props %s on <%s> tag. For details, see https://fb.me/invalid-aria-prop%s", unknownPropString, type, getStackAddendum());
             }
@@ -21193,7 +21199,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             }
             function validateProperties$1(type, props) {
                 "input" !== type && "textarea" !== type && "select" !== type || null == props || null !== props.value || didWarnValueNull || (didWarnValueNull = !0, 
-                "select" === type && props.multiple ? warning(!1, "` + ("`" + `value`)) + ("`" + (` prop on ` + "`"))) + ((`%s` + ("`" + ` should not be null. Consider using an empty array when `)) + ("`" + (`multiple` + "`"))))))) + ((((((` is set to ` + "`") + (`true` + ("`" + ` to clear the component or `))) + (("`" + (`undefined` + "`")) + (` for uncontrolled components.%s", type, getStackAddendum$1()) : warning(!1, "` + ("`" + `value`)))) + ((("`" + (` prop on ` + "`")) + (`%s` + ("`" + ` should not be null. Consider using an empty string to clear the component or `))) + (("`" + (`undefined` + "`")) + (` for uncontrolled components.%s", type, getStackAddendum$1()));
+                "select" === type && props.multiple ? warning(!1, "` + "`" + `value` + "`" + ` prop on ` + "`" + `%s` + "`" + ` should not be null. Consider using an empty array when ` + "`" + `multiple` + "`" + ` is set to ` + "`" + `true` + "`" + ` to clear the component or ` + "`" + `undefined` + "`" + ` for uncontrolled components.%s", type, getStackAddendum$1()) : warning(!1, "` + "`" + `value` + "`" + ` prop on ` + "`" + `%s` + "`" + ` should not be null. Consider using an empty string to clear the component or ` + "`" + `undefined` + "`" + ` for uncontrolled components.%s", type, getStackAddendum$1()));
             }
             function getStackAddendum$2() {
                 var stack = ReactDebugCurrentFrame.getStackAddendum();
@@ -21809,7 +21815,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             };
             validateEventDispatches = function(event) {
                 var dispatchListeners = event._dispatchListeners, dispatchInstances = event._dispatchInstances, listenersIsArr = Array.isArray(dispatchListeners), listenersLen = listenersIsArr ? dispatchListeners.length : dispatchListeners ? 1 : 0, instancesIsArr = Array.isArray(dispatchInstances), instancesLen = instancesIsArr ? dispatchInstances.length : dispatchInstances ? 1 : 0;
-                warning(instancesIsArr === listenersIsArr && instancesLen === listenersLen, "EventPluginUtils: Invalid ` + ("`" + `event`))))) + (((("`" + (`.");
+                warning(instancesIsArr === listenersIsArr && instancesLen === listenersLen, "EventPluginUtils: Invalid ` + "`" + `event` + "`" + `.");
             };
             var eventQueue = null, executeDispatchesAndRelease = function(event, simulated) {
                 event && (executeDispatchesInOrder(event, simulated), event.isPersistent() || event.constructor.release(event));
@@ -22404,7 +22410,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                         break;
 
                       default:
-                        -1 === knownHTMLTopLevelTypes.indexOf(topLevelType) && warning(!1, "SimpleEventPlugin: Unhandled event type, ` + "`")) + (`%s` + ("`" + `. This warning is likely caused by a bug in React. Please file an issue.", topLevelType), 
+                        -1 === knownHTMLTopLevelTypes.indexOf(topLevelType) && warning(!1, "SimpleEventPlugin: Unhandled event type, ` + "`" + `%s` + "`" + `. This warning is likely caused by a bug in React. Please file an issue.", topLevelType), 
                         EventConstructor = SyntheticEvent$1;
                     }
                     var event = EventConstructor.getPooled(dispatchConfig, targetInst, nativeEvent, nativeEventTarget);
@@ -22491,7 +22497,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             var debugCounter = 1, createFiber = function(tag, key, internalContextTag) {
                 return new FiberNode(tag, key, internalContextTag);
             }, onCommitFiberRoot = null, onCommitFiberUnmount = null, hasLoggedError = !1, didWarnUpdateInsideUpdate = !1, fakeInternalInstance = {}, isArray = Array.isArray, didWarnAboutStateAssignmentForComponent = {}, warnOnInvalidCallback = function(callback, callerName) {
-                warning(null === callback || "function" == typeof callback, "%s(...): Expected the last optional `))) + (("`" + (`callback` + "`")) + (` argument to be a function. Instead received: %s.", callerName, callback);
+                warning(null === callback || "function" == typeof callback, "%s(...): Expected the last optional ` + "`" + `callback` + "`" + ` argument to be a function. Instead received: %s.", callerName, callback);
             };
             Object.defineProperty(fakeInternalInstance, "_processChildContext", {
                 enumerable: !1,
@@ -22514,7 +22520,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 }
                 function checkClassInstance(workInProgress) {
                     var instance = workInProgress.stateNode, type = workInProgress.type, name = getComponentName(workInProgress);
-                    instance.render || (type.prototype && "function" == typeof type.prototype.render ? warning(!1, "%s(...): No ` + ("`" + `render`)))) + ((("`" + (` method found on the returned component instance: did you accidentally return an object from the constructor?", name) : warning(!1, "%s(...): No ` + "`")) + (`render` + ("`" + ` method found on the returned component instance: you may have forgotten to define `))) + (("`" + (`render` + "`")) + (`.", name));
+                    instance.render || (type.prototype && "function" == typeof type.prototype.render ? warning(!1, "%s(...): No ` + "`" + `render` + "`" + ` method found on the returned component instance: did you accidentally return an object from the constructor?", name) : warning(!1, "%s(...): No ` + "`" + `render` + "`" + ` method found on the returned component instance: you may have forgotten to define ` + "`" + `render` + "`" + `.", name));
                     var noGetInitialStateOnES6 = !instance.getInitialState || instance.getInitialState.isReactClassApproved || instance.state;
                     warning(noGetInitialStateOnES6, "getInitialState was defined on %s, a plain JavaScript class. This is only supported for classes created using React.createClass. Did you mean to define a state property instead?", name);
                     var noGetDefaultPropsOnES6 = !instance.getDefaultProps || instance.getDefaultProps.isReactClassApproved;
@@ -22533,7 +22539,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     var noComponentWillRecieveProps = "function" != typeof instance.componentWillRecieveProps;
                     warning(noComponentWillRecieveProps, "%s has a method called componentWillRecieveProps(). Did you mean componentWillReceiveProps()?", name);
                     var hasMutatedProps = instance.props !== workInProgress.pendingProps;
-                    warning(void 0 === instance.props || !hasMutatedProps, "%s(...): When calling super() in ` + ("`" + `%s`)))))) + ((((("`" + (`, make sure to pass up the same props that your component's constructor was passed.", name, name);
+                    warning(void 0 === instance.props || !hasMutatedProps, "%s(...): When calling super() in ` + "`" + `%s` + "`" + `, make sure to pass up the same props that your component's constructor was passed.", name, name);
                     var noInstanceDefaultProps = !instance.defaultProps;
                     warning(noInstanceDefaultProps, "Setting defaultProps as an instance property on %s is not supported and will be ignored. Instead, define defaultProps as a static property on %s.", name, name);
                     var state = instance.state;
@@ -22765,7 +22771,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     if (Component && warning(!Component.childContextTypes, "%s(...): childContextTypes cannot be defined on a functional component.", Component.displayName || Component.name || "Component"), 
                     null !== workInProgress.ref) {
                         var info = "", ownerName = ReactDebugCurrentFiber.getCurrentFiberOwnerName();
-                        ownerName && (info += "\n\nCheck the render method of ` + "`")) + (`" + ownerName + "` + ("`" + `.");
+                        ownerName && (info += "\n\nCheck the render method of ` + "`" + `" + ownerName + "` + "`" + `.");
                         var warningKey = ownerName || workInProgress._debugID || "", debugSource = workInProgress._debugSource;
                         debugSource && (warningKey = debugSource.fileName + ":" + debugSource.lineNumber), 
                         warnedAboutStatelessRefs[warningKey] || (warnedAboutStatelessRefs[warningKey] = !0, 
@@ -23523,7 +23529,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
 
                   case "render":
                     if (didWarnAboutStateTransition) return;
-                    warning(!1, "Cannot update during an existing state transition (such as within `))) + (("`" + (`render` + "`")) + (` or another component's constructor). Render methods should be a pure function of props and state; constructor side-effects are an anti-pattern, but can be moved to ` + ("`" + `componentWillMount`)))) + ((("`" + (`."), 
+                    warning(!1, "Cannot update during an existing state transition (such as within ` + "`" + `render` + "`" + ` or another component's constructor). Render methods should be a pure function of props and state; constructor side-effects are an anti-pattern, but can be moved to ` + "`" + `componentWillMount` + "`" + `."), 
                     didWarnAboutStateTransition = !0;
                 }
             }, ReactFiberScheduler = function(config) {
@@ -23973,7 +23979,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 function scheduleTopLevelUpdate(current, element, callback) {
                     "render" !== ReactDebugCurrentFiber.phase || null === ReactDebugCurrentFiber.current || didWarnAboutNestedUpdates || (didWarnAboutNestedUpdates = !0, 
                     warning(!1, "Render methods should be a pure function of props and state; triggering nested component updates from render is not allowed. If necessary, trigger nested updates in componentDidUpdate.\n\nCheck the render method of %s.", getComponentName(ReactDebugCurrentFiber.current) || "Unknown")), 
-                    callback = void 0 === callback ? null : callback, warning(null === callback || "function" == typeof callback, "render(...): Expected the last optional ` + "`")) + (`callback` + ("`" + ` argument to be a function. Instead received: %s.", callback);
+                    callback = void 0 === callback ? null : callback, warning(null === callback || "function" == typeof callback, "render(...): Expected the last optional ` + "`" + `callback` + "`" + ` argument to be a function. Instead received: %s.", callback);
                     var expirationTime = void 0;
                     expirationTime = enableAsyncSubtreeAPI && null != element && null != element.type && null != element.type.prototype && !0 === element.type.prototype.unstable_isAsyncReactComponent ? computeAsyncExpiration() : computeExpirationForFiber(current), 
                     insertUpdateIntoFiber(current, {
@@ -24114,7 +24120,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 } catch (x) {}
             };
             lowPriorityWarning = function(condition, format) {
-                if (void 0 === format) throw new Error("`))) + (("`" + (`warning(condition, format, ...args)` + "`")) + (` requires a warning message argument");
+                if (void 0 === format) throw new Error("` + "`" + `warning(condition, format, ...args)` + "`" + ` requires a warning message argument");
                 if (!condition) {
                     for (var _len2 = arguments.length, args = Array(_len2 > 2 ? _len2 - 2 : 0), _key2 = 2; _key2 < _len2; _key2++) args[_key2 - 2] = arguments[_key2];
                     printWarning.apply(void 0, [ format ].concat(args));
@@ -24132,10 +24138,10 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 submit: !0
             }, propTypes = {
                 value: function(props, propName, componentName) {
-                    return !props[propName] || hasReadOnlyValue[props.type] || props.onChange || props.readOnly || props.disabled ? null : new Error("You provided a ` + ("`" + `value`))))) + (((("`" + (` prop to a form field without an ` + "`")) + (`onChange` + ("`" + ` handler. This will render a read-only field. If the field should be mutable use `))) + (("`" + (`defaultValue` + "`")) + (`. Otherwise, set either ` + ("`" + `onChange`)))) + ((("`" + (` or ` + "`")) + (`readOnly` + ("`" + `.");
+                    return !props[propName] || hasReadOnlyValue[props.type] || props.onChange || props.readOnly || props.disabled ? null : new Error("You provided a ` + "`" + `value` + "`" + ` prop to a form field without an ` + "`" + `onChange` + "`" + ` handler. This will render a read-only field. If the field should be mutable use ` + "`" + `defaultValue` + "`" + `. Otherwise, set either ` + "`" + `onChange` + "`" + ` or ` + "`" + `readOnly` + "`" + `.");
                 },
                 checked: function(props, propName, componentName) {
-                    return !props[propName] || props.onChange || props.readOnly || props.disabled ? null : new Error("You provided a `))) + (("`" + (`checked` + "`")) + (` prop to a form field without an ` + ("`" + `onChange`))))))))) + (((((((("`" + ` handler. This will render a read-only field. If the field should be mutable use `) + ("`" + (`defaultChecked` + "`"))) + ((`. Otherwise, set either ` + ("`" + `onChange`)) + ("`" + (` or ` + "`")))) + (((`readOnly` + ("`" + `.");
+                    return !props[propName] || props.onChange || props.readOnly || props.disabled ? null : new Error("You provided a ` + "`" + `checked` + "`" + ` prop to a form field without an ` + "`" + `onChange` + "`" + ` handler. This will render a read-only field. If the field should be mutable use ` + "`" + `defaultChecked` + "`" + `. Otherwise, set either ` + "`" + `onChange` + "`" + ` or ` + "`" + `readOnly` + "`" + `.");
                 }
             };
             ReactControlledValuePropTypes.checkPropTypes = function(tagName, props, getStack) {
@@ -24221,9 +24227,9 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 warnedStyleValues.hasOwnProperty(value) && warnedStyleValues[value] || (warnedStyleValues[value] = !0, 
                 warning(!1, 'Style property values shouldn\'t contain a semicolon. Try "%s: %s" instead.%s', name, value.replace(badStyleValueWithSemicolonPattern, ""), getStack()));
             }, warnStyleValueIsNaN = function(name, value, getStack) {
-                warnedForNaNValue || (warnedForNaNValue = !0, warning(!1, "`)) + ("`" + (`NaN` + "`"))) + ((` is an invalid value for the ` + ("`" + `%s`)) + ("`" + (` css style property.%s", name, getStack()));
+                warnedForNaNValue || (warnedForNaNValue = !0, warning(!1, "` + "`" + `NaN` + "`" + ` is an invalid value for the ` + "`" + `%s` + "`" + ` css style property.%s", name, getStack()));
             }, warnStyleValueIsInfinity = function(name, value, getStack) {
-                warnedForInfinityValue || (warnedForInfinityValue = !0, warning(!1, "` + "`"))))) + ((((`Infinity` + ("`" + ` is an invalid value for the `)) + ("`" + (`%s` + "`"))) + ((` css style property.%s", name, getStack()));
+                warnedForInfinityValue || (warnedForInfinityValue = !0, warning(!1, "` + "`" + `Infinity` + "`" + ` is an invalid value for the ` + "`" + `%s` + "`" + ` css style property.%s", name, getStack()));
             };
             warnValidStyle = function(name, value, getStack) {
                 name.indexOf("-") > -1 ? warnHyphenatedStyleName(name, getStack) : badVendoredStyleNamePattern.test(name) ? warnBadVendoredStyleName(name, getStack) : badStyleValueWithSemicolonPattern.test(value) && warnStyleValueWithSemicolon(name, value, getStack), 
@@ -24784,30 +24790,30 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 if (canUseEventSystem) {
                     if (registrationNameModules.hasOwnProperty(name)) return !0;
                     var registrationName = possibleRegistrationNames.hasOwnProperty(lowerCasedName) ? possibleRegistrationNames[lowerCasedName] : null;
-                    if (null != registrationName) return warning(!1, "Invalid event handler property ` + ("`" + `%s`)) + ("`" + (`. Did you mean ` + "`")))) + (((`%s` + ("`" + `?%s", name, registrationName, getStackAddendum$2()), 
+                    if (null != registrationName) return warning(!1, "Invalid event handler property ` + "`" + `%s` + "`" + `. Did you mean ` + "`" + `%s` + "`" + `?%s", name, registrationName, getStackAddendum$2()), 
                     warnedProperties$1[name] = !0, !0;
-                    if (EVENT_NAME_REGEX.test(name)) return warning(!1, "Unknown event handler property `)) + ("`" + (`%s` + "`"))) + ((`. It will be ignored.%s", name, getStackAddendum$2()), 
+                    if (EVENT_NAME_REGEX.test(name)) return warning(!1, "Unknown event handler property ` + "`" + `%s` + "`" + `. It will be ignored.%s", name, getStackAddendum$2()), 
                     warnedProperties$1[name] = !0, !0;
-                } else if (EVENT_NAME_REGEX.test(name)) return INVALID_EVENT_NAME_REGEX.test(name) && warning(!1, "Invalid event handler property ` + ("`" + `%s`)) + ("`" + (`. React events use the camelCase naming convention, for example ` + "`")))))) + (((((`onClick` + "`") + (`.%s", name, getStackAddendum$2()), 
+                } else if (EVENT_NAME_REGEX.test(name)) return INVALID_EVENT_NAME_REGEX.test(name) && warning(!1, "Invalid event handler property ` + "`" + `%s` + "`" + `. React events use the camelCase naming convention, for example ` + "`" + `onClick` + "`" + `.%s", name, getStackAddendum$2()), 
                 warnedProperties$1[name] = !0, !0;
                 if (rARIA$1.test(name) || rARIACamel$1.test(name)) return !0;
-                if ("innerhtml" === lowerCasedName) return warning(!1, "Directly setting property ` + ("`" + `innerHTML`))) + (("`" + (` is not permitted. For more information, lookup documentation on ` + "`")) + (`dangerouslySetInnerHTML` + ("`" + `."), 
+                if ("innerhtml" === lowerCasedName) return warning(!1, "Directly setting property ` + "`" + `innerHTML` + "`" + ` is not permitted. For more information, lookup documentation on ` + "`" + `dangerouslySetInnerHTML` + "`" + `."), 
                 warnedProperties$1[name] = !0, !0;
-                if ("aria" === lowerCasedName) return warning(!1, "The `)))) + ((("`" + (`aria` + "`")) + (` attribute is reserved for future use in React. Pass individual ` + ("`" + `aria-`))) + (("`" + (` attributes instead."), 
+                if ("aria" === lowerCasedName) return warning(!1, "The ` + "`" + `aria` + "`" + ` attribute is reserved for future use in React. Pass individual ` + "`" + `aria-` + "`" + ` attributes instead."), 
                 warnedProperties$1[name] = !0, !0;
-                if ("is" === lowerCasedName && null !== value && void 0 !== value && "string" != typeof value) return warning(!1, "Received a ` + "`")) + (`%s` + ("`" + ` for a string attribute `))))) + (((("`" + (`is` + "`")) + (`. If this is expected, cast the value to a string.%s", typeof value, getStackAddendum$2()), 
+                if ("is" === lowerCasedName && null !== value && void 0 !== value && "string" != typeof value) return warning(!1, "Received a ` + "`" + `%s` + "`" + ` for a string attribute ` + "`" + `is` + "`" + `. If this is expected, cast the value to a string.%s", typeof value, getStackAddendum$2()), 
                 warnedProperties$1[name] = !0, !0;
-                if ("number" == typeof value && isNaN(value)) return warning(!1, "Received NaN for the ` + ("`" + `%s`))) + (("`" + (` attribute. If this is expected, cast the value to a string.%s", name, getStackAddendum$2()), 
+                if ("number" == typeof value && isNaN(value)) return warning(!1, "Received NaN for the ` + "`" + `%s` + "`" + ` attribute. If this is expected, cast the value to a string.%s", name, getStackAddendum$2()), 
                 warnedProperties$1[name] = !0, !0;
                 var isReserved = isReservedProp(name);
                 if (possibleStandardNames.hasOwnProperty(lowerCasedName)) {
                     var standardName = possibleStandardNames[lowerCasedName];
-                    if (standardName !== name) return warning(!1, "Invalid DOM property ` + "`")) + (`%s` + ("`" + `. Did you mean `)))) + ((("`" + (`%s` + "`")) + (`?%s", name, standardName, getStackAddendum$2()), 
+                    if (standardName !== name) return warning(!1, "Invalid DOM property ` + "`" + `%s` + "`" + `. Did you mean ` + "`" + `%s` + "`" + `?%s", name, standardName, getStackAddendum$2()), 
                     warnedProperties$1[name] = !0, !0;
-                } else if (!isReserved && name !== lowerCasedName) return warning(!1, "React does not recognize the ` + ("`" + `%s`))) + (("`" + (` prop on a DOM element. If you intentionally want it to appear in the DOM as a custom attribute, spell it as lowercase ` + "`")) + (`%s` + ("`" + ` instead. If you accidentally passed it from a parent component, remove it from the DOM element.%s", name, lowerCasedName, getStackAddendum$2()), 
+                } else if (!isReserved && name !== lowerCasedName) return warning(!1, "React does not recognize the ` + "`" + `%s` + "`" + ` prop on a DOM element. If you intentionally want it to appear in the DOM as a custom attribute, spell it as lowercase ` + "`" + `%s` + "`" + ` instead. If you accidentally passed it from a parent component, remove it from the DOM element.%s", name, lowerCasedName, getStackAddendum$2()), 
                 warnedProperties$1[name] = !0, !0;
                 return "boolean" != typeof value || shouldAttributeAcceptBooleanValue(name) ? !!isReserved || (!!shouldSetAttribute(name, value) || (warnedProperties$1[name] = !0, 
-                !1)) : (value ? warning(!1, 'Received `))))))) + (((((("`" + `%s`) + ("`" + (` for a non-boolean attribute ` + "`"))) + ((`%s` + ("`" + `.\n\nIf you want to write it to the DOM, pass a string instead: %s="%s" or %s={value.toString()}.%s', value, name, name, value, name, getStackAddendum$2()) : warning(!1, 'Received `)) + ("`" + (`%s` + "`")))) + (((` for a non-boolean attribute ` + ("`" + `%s`)) + ("`" + (`.\n\nIf you want to write it to the DOM, pass a string instead: %s="%s" or %s={value.toString()}.\n\nIf you used to conditionally omit it with %s={condition && value}, pass %s={condition ? value : undefined} instead.%s', value, name, name, value, name, name, name, getStackAddendum$2()), 
+                !1)) : (value ? warning(!1, 'Received ` + "`" + `%s` + "`" + ` for a non-boolean attribute ` + "`" + `%s` + "`" + `.\n\nIf you want to write it to the DOM, pass a string instead: %s="%s" or %s={value.toString()}.%s', value, name, name, value, name, getStackAddendum$2()) : warning(!1, 'Received ` + "`" + `%s` + "`" + ` for a non-boolean attribute ` + "`" + `%s` + "`" + `.\n\nIf you want to write it to the DOM, pass a string instead: %s="%s" or %s={value.toString()}.\n\nIf you used to conditionally omit it with %s={condition && value}, pass %s={condition ? value : undefined} instead.%s', value, name, name, value, name, name, name, getStackAddendum$2()), 
                 warnedProperties$1[name] = !0, !0);
             }, warnUnknownProperties = function(type, props, canUseEventSystem) {
                 var unknownProps = [];
@@ -24815,7 +24821,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     validateProperty$1(0, key, props[key], canUseEventSystem) || unknownProps.push(key);
                 }
                 var unknownPropString = unknownProps.map(function(prop) {
-                    return "` + "`"))) + ((`" + prop + "` + ("`" + `";
+                    return "` + "`" + `" + prop + "` + "`" + `";
                 }).join(", ");
                 1 === unknownProps.length ? warning(!1, "Invalid value for prop %s on <%s> tag. Either remove it from the element, or pass a string or number value to keep it in the DOM. For details, see https://fb.me/react-attribute-behavior%s", unknownPropString, type, getStackAddendum$2()) : unknownProps.length > 1 && warning(!1, "Invalid values for props %s on <%s> tag. Either remove them from the element, or pass a string or number value to keep them in the DOM. For details, see https://fb.me/react-attribute-behavior%s", unknownPropString, type, getStackAddendum$2());
             }, getCurrentFiberOwnerName$1 = ReactDebugCurrentFiber.getCurrentFiberOwnerName, getCurrentFiberStackAddendum$2 = ReactDebugCurrentFiber.getCurrentFiberStackAddendum, didWarnInvalidHydration = !1, didWarnShadyDOM = !1, DANGEROUSLY_SET_INNER_HTML = "dangerouslySetInnerHTML", SUPPRESS_CONTENT_EDITABLE_WARNING = "suppressContentEditableWarning", SUPPRESS_HYDRATION_WARNING$1 = "suppressHydrationWarning", AUTOFOCUS = "autoFocus", CHILDREN = "children", STYLE = "style", HTML = "__html", HTML_NAMESPACE = Namespaces.html, getStack = emptyFunction.thatReturns("");
@@ -24837,7 +24843,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 if (!didWarnInvalidHydration) {
                     var normalizedClientValue = normalizeMarkupForTextOrAttribute(clientValue), normalizedServerValue = normalizeMarkupForTextOrAttribute(serverValue);
                     normalizedServerValue !== normalizedClientValue && (didWarnInvalidHydration = !0, 
-                    warning(!1, "Prop `)) + ("`" + (`%s` + "`"))))) + ((((` did not match. Server: %s Client: %s", propName, JSON.stringify(normalizedServerValue), JSON.stringify(normalizedClientValue)));
+                    warning(!1, "Prop ` + "`" + `%s` + "`" + ` did not match. Server: %s Client: %s", propName, JSON.stringify(normalizedServerValue), JSON.stringify(normalizedClientValue)));
                 }
             }, warnForExtraAttributes = function(attributeNames) {
                 if (!didWarnInvalidHydration) {
@@ -24848,7 +24854,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     }), warning(!1, "Extra attributes from the server: %s", names);
                 }
             }, warnForInvalidEventListener = function(registrationName, listener) {
-                !1 === listener ? warning(!1, "Expected ` + ("`" + `%s`)) + ("`" + (` listener to be a function, instead got ` + "`"))) + ((`false` + ("`" + `.\n\nIf you used to conditionally omit it with %s={condition && value}, pass %s={condition ? value : undefined} instead.%s", registrationName, registrationName, registrationName, getCurrentFiberStackAddendum$2()) : warning(!1, "Expected `)) + ("`" + (`%s` + "`")))) + (((` listener to be a function, instead got a value of ` + ("`" + `%s`)) + ("`" + (` type.%s", registrationName, typeof listener, getCurrentFiberStackAddendum$2());
+                !1 === listener ? warning(!1, "Expected ` + "`" + `%s` + "`" + ` listener to be a function, instead got ` + "`" + `false` + "`" + `.\n\nIf you used to conditionally omit it with %s={condition && value}, pass %s={condition ? value : undefined} instead.%s", registrationName, registrationName, registrationName, getCurrentFiberStackAddendum$2()) : warning(!1, "Expected ` + "`" + `%s` + "`" + ` listener to be a function, instead got a value of ` + "`" + `%s` + "`" + ` type.%s", registrationName, typeof listener, getCurrentFiberStackAddendum$2());
             }, normalizeHTML = function(parent, html) {
                 var testElement = parent.namespaceURI === HTML_NAMESPACE ? parent.ownerDocument.createElement(parent.tagName) : parent.ownerDocument.createElementNS(parent.namespaceURI, parent.tagName);
                 return testElement.innerHTML = html, testElement.innerHTML;
@@ -25707,12 +25713,12 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             function createChainableTypeChecker(validate) {
                 function checkType(isRequired, props, propName, componentName, location, propFullName, secret) {
                     if (componentName = componentName || ANONYMOUS, propFullName = propFullName || propName, 
-                    secret !== ReactPropTypesSecret) if (throwOnDirectAccess) invariant(!1, "Calling PropTypes validators directly is not supported by the ` + "`"))) + ((`prop-types` + ("`" + ` package. Use `)) + ("`" + (`PropTypes.checkPropTypes()` + "`")))))) + (((((` to call them. Read more at http://fb.me/use-check-prop-types"); else if ("production" !== process.env.NODE_ENV && "undefined" != typeof console) {
+                    secret !== ReactPropTypesSecret) if (throwOnDirectAccess) invariant(!1, "Calling PropTypes validators directly is not supported by the ` + "`" + `prop-types` + "`" + ` package. Use ` + "`" + `PropTypes.checkPropTypes()` + "`" + ` to call them. Read more at http://fb.me/use-check-prop-types"); else if ("production" !== process.env.NODE_ENV && "undefined" != typeof console) {
                         var cacheKey = componentName + ":" + propName;
-                        !manualPropTypeCallCache[cacheKey] && manualPropTypeWarningCount < 3 && (warning(!1, "You are manually calling a React.PropTypes validation function for the ` + ("`" + `%s`)) + ("`" + (` prop on ` + "`"))) + ((`%s` + ("`" + `. This is deprecated and will throw in the standalone `)) + ("`" + (`prop-types` + "`")))) + (((` package. You may be seeing this warning due to a third-party PropTypes library. See https://fb.me/react-warning-dont-call-proptypes for details.", propFullName, componentName), 
+                        !manualPropTypeCallCache[cacheKey] && manualPropTypeWarningCount < 3 && (warning(!1, "You are manually calling a React.PropTypes validation function for the ` + "`" + `%s` + "`" + ` prop on ` + "`" + `%s` + "`" + `. This is deprecated and will throw in the standalone ` + "`" + `prop-types` + "`" + ` package. You may be seeing this warning due to a third-party PropTypes library. See https://fb.me/react-warning-dont-call-proptypes for details.", propFullName, componentName), 
                         manualPropTypeCallCache[cacheKey] = !0, manualPropTypeWarningCount++);
                     }
-                    return null == props[propName] ? isRequired ? new PropTypeError(null === props[propName] ? "The " + location + " ` + ("`" + `" + propFullName + "`)) + ("`" + (` is marked as required in ` + "`"))) + ((`" + componentName + "` + ("`" + `, but its value is `)) + ("`" + (`null` + "`"))))) + ((((`." : "The " + location + " ` + ("`" + `" + propFullName + "`)) + ("`" + (` is marked as required in ` + "`"))) + ((`" + componentName + "` + ("`" + `, but its value is `)) + ("`" + (`undefined` + "`")))) + (((`.") : null : validate(props, propName, componentName, location, propFullName);
+                    return null == props[propName] ? isRequired ? new PropTypeError(null === props[propName] ? "The " + location + " ` + "`" + `" + propFullName + "` + "`" + ` is marked as required in ` + "`" + `" + componentName + "` + "`" + `, but its value is ` + "`" + `null` + "`" + `." : "The " + location + " ` + "`" + `" + propFullName + "` + "`" + ` is marked as required in ` + "`" + `" + componentName + "` + "`" + `, but its value is ` + "`" + `undefined` + "`" + `.") : null : validate(props, propName, componentName, location, propFullName);
                 }
                 if ("production" !== process.env.NODE_ENV) var manualPropTypeCallCache = {}, manualPropTypeWarningCount = 0;
                 var chainedCheckType = checkType.bind(null, !1);
@@ -25721,17 +25727,17 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             function createPrimitiveTypeChecker(expectedType) {
                 function validate(props, propName, componentName, location, propFullName, secret) {
                     var propValue = props[propName];
-                    if (getPropType(propValue) !== expectedType) return new PropTypeError("Invalid " + location + " ` + ("`" + `" + propFullName + "`)) + ("`" + (` of type ` + "`"))) + ((`" + getPreciseType(propValue) + "` + ("`" + ` supplied to `)) + ("`" + (`" + componentName + "` + "`")))))))) + (((((((`, expected ` + "`") + (`" + expectedType + "` + ("`" + `.");
+                    if (getPropType(propValue) !== expectedType) return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of type ` + "`" + `" + getPreciseType(propValue) + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected ` + "`" + `" + expectedType + "` + "`" + `.");
                     return null;
                 }
                 return createChainableTypeChecker(validate);
             }
             function createArrayOfTypeChecker(typeChecker) {
                 function validate(props, propName, componentName, location, propFullName) {
-                    if ("function" != typeof typeChecker) return new PropTypeError("Property `))) + (("`" + (`" + propFullName + "` + "`")) + (` of component ` + ("`" + `" + componentName + "`)))) + ((("`" + (` has invalid PropType notation inside arrayOf.");
+                    if ("function" != typeof typeChecker) return new PropTypeError("Property ` + "`" + `" + propFullName + "` + "`" + ` of component ` + "`" + `" + componentName + "` + "`" + ` has invalid PropType notation inside arrayOf.");
                     var propValue = props[propName];
                     if (!Array.isArray(propValue)) {
-                        return new PropTypeError("Invalid " + location + " ` + "`")) + (`" + propFullName + "` + ("`" + ` of type `))) + (("`" + (`" + getPropType(propValue) + "` + "`")) + (` supplied to ` + ("`" + `" + componentName + "`))))) + (((("`" + (`, expected an array.");
+                        return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of type ` + "`" + `" + getPropType(propValue) + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected an array.");
                     }
                     for (var i = 0; i < propValue.length; i++) {
                         var error = typeChecker(propValue, i, componentName, location, propFullName + "[" + i + "]", ReactPropTypesSecret);
@@ -25745,7 +25751,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 function validate(props, propName, componentName, location, propFullName) {
                     if (!(props[propName] instanceof expectedClass)) {
                         var expectedClassName = expectedClass.name || ANONYMOUS;
-                        return new PropTypeError("Invalid " + location + " ` + "`")) + (`" + propFullName + "` + ("`" + ` of type `))) + (("`" + (`" + getClassName(props[propName]) + "` + "`")) + (` supplied to ` + ("`" + `" + componentName + "`)))) + ((("`" + (`, expected instance of ` + "`")) + (`" + expectedClassName + "` + ("`" + `.");
+                        return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of type ` + "`" + `" + getClassName(props[propName]) + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected instance of ` + "`" + `" + expectedClassName + "` + "`" + `.");
                     }
                     return null;
                 }
@@ -25754,16 +25760,16 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             function createEnumTypeChecker(expectedValues) {
                 function validate(props, propName, componentName, location, propFullName) {
                     for (var propValue = props[propName], i = 0; i < expectedValues.length; i++) if (is(propValue, expectedValues[i])) return null;
-                    return new PropTypeError("Invalid " + location + " `))) + (("`" + (`" + propFullName + "` + "`")) + (` of value ` + ("`" + `" + propValue + "`)))))) + ((((("`" + ` supplied to `) + ("`" + (`" + componentName + "` + "`"))) + ((`, expected one of " + JSON.stringify(expectedValues) + ".");
+                    return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of value ` + "`" + `" + propValue + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected one of " + JSON.stringify(expectedValues) + ".");
                 }
                 return Array.isArray(expectedValues) ? createChainableTypeChecker(validate) : ("production" !== process.env.NODE_ENV && warning(!1, "Invalid argument supplied to oneOf, expected an instance of array."), 
                 emptyFunction.thatReturnsNull);
             }
             function createObjectOfTypeChecker(typeChecker) {
                 function validate(props, propName, componentName, location, propFullName) {
-                    if ("function" != typeof typeChecker) return new PropTypeError("Property ` + ("`" + `" + propFullName + "`)) + ("`" + (` of component ` + "`")))) + (((`" + componentName + "` + ("`" + ` has invalid PropType notation inside objectOf.");
+                    if ("function" != typeof typeChecker) return new PropTypeError("Property ` + "`" + `" + propFullName + "` + "`" + ` of component ` + "`" + `" + componentName + "` + "`" + ` has invalid PropType notation inside objectOf.");
                     var propValue = props[propName], propType = getPropType(propValue);
-                    if ("object" !== propType) return new PropTypeError("Invalid " + location + " `)) + ("`" + (`" + propFullName + "` + "`"))) + ((` of type ` + ("`" + `" + propType + "`)) + ("`" + (` supplied to ` + "`"))))) + ((((`" + componentName + "` + ("`" + `, expected an object.");
+                    if ("object" !== propType) return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of type ` + "`" + `" + propType + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected an object.");
                     for (var key in propValue) if (propValue.hasOwnProperty(key)) {
                         var error = typeChecker(propValue, key, componentName, location, propFullName + "." + key, ReactPropTypesSecret);
                         if (error instanceof Error) return error;
@@ -25777,7 +25783,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     for (var i = 0; i < arrayOfTypeCheckers.length; i++) {
                         if (null == (0, arrayOfTypeCheckers[i])(props, propName, componentName, location, propFullName, ReactPropTypesSecret)) return null;
                     }
-                    return new PropTypeError("Invalid " + location + " `)) + ("`" + (`" + propFullName + "` + "`"))) + ((` supplied to ` + ("`" + `" + componentName + "`)) + ("`" + (`.");
+                    return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `.");
                 }
                 if (!Array.isArray(arrayOfTypeCheckers)) return "production" !== process.env.NODE_ENV && warning(!1, "Invalid argument supplied to oneOfType, expected an instance of array."), 
                 emptyFunction.thatReturnsNull;
@@ -25791,7 +25797,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             function createShapeTypeChecker(shapeTypes) {
                 function validate(props, propName, componentName, location, propFullName) {
                     var propValue = props[propName], propType = getPropType(propValue);
-                    if ("object" !== propType) return new PropTypeError("Invalid " + location + " ` + "`")))) + (((`" + propFullName + "` + ("`" + ` of type `)) + ("`" + (`" + propType + "` + "`"))) + ((` supplied to ` + ("`" + `" + componentName + "`)) + ("`" + (`, expected ` + "`"))))))) + ((((((`object` + "`") + (`.");
+                    if ("object" !== propType) return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of type ` + "`" + `" + propType + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected ` + "`" + `object` + "`" + `.");
                     for (var key in shapeTypes) {
                         var checker = shapeTypes[key];
                         if (checker) {
@@ -25806,11 +25812,11 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             function createStrictShapeTypeChecker(shapeTypes) {
                 function validate(props, propName, componentName, location, propFullName) {
                     var propValue = props[propName], propType = getPropType(propValue);
-                    if ("object" !== propType) return new PropTypeError("Invalid " + location + " ` + ("`" + `" + propFullName + "`))) + (("`" + (` of type ` + "`")) + (`" + propType + "` + ("`" + ` supplied to `)))) + ((("`" + (`" + componentName + "` + "`")) + (`, expected ` + ("`" + `object`))) + (("`" + (`.");
+                    if ("object" !== propType) return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of type ` + "`" + `" + propType + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected ` + "`" + `object` + "`" + `.");
                     var allKeys = assign({}, props[propName], shapeTypes);
                     for (var key in allKeys) {
                         var checker = shapeTypes[key];
-                        if (!checker) return new PropTypeError("Invalid " + location + " ` + "`")) + (`" + propFullName + "` + ("`" + ` key `))))) + (((("`" + (`" + key + "` + "`")) + (` supplied to ` + ("`" + `" + componentName + "`))) + (("`" + (`.\nBad object: " + JSON.stringify(props[propName], null, "  ") + "\nValid keys: " + JSON.stringify(Object.keys(shapeTypes), null, "  "));
+                        if (!checker) return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` key ` + "`" + `" + key + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `.\nBad object: " + JSON.stringify(props[propName], null, "  ") + "\nValid keys: " + JSON.stringify(Object.keys(shapeTypes), null, "  "));
                         var error = checker(propValue, key, componentName, location, propFullName + "." + key, ReactPropTypesSecret);
                         if (error) return error;
                     }
@@ -25897,7 +25903,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                     function validate(props, propName, componentName, location, propFullName) {
                         var propValue = props[propName];
                         if (!isValidElement(propValue)) {
-                            return new PropTypeError("Invalid " + location + " ` + "`")) + (`" + propFullName + "` + ("`" + ` of type `)))) + ((("`" + (`" + getPropType(propValue) + "` + "`")) + (` supplied to ` + ("`" + `" + componentName + "`))) + (("`" + (`, expected a single ReactElement.");
+                            return new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` of type ` + "`" + `" + getPropType(propValue) + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected a single ReactElement.");
                         }
                         return null;
                     }
@@ -25906,7 +25912,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 instanceOf: createInstanceTypeChecker,
                 node: function() {
                     function validate(props, propName, componentName, location, propFullName) {
-                        return isNode(props[propName]) ? null : new PropTypeError("Invalid " + location + " ` + "`")) + (`" + propFullName + "` + ("`" + ` supplied to `)))))) + ((((("`" + (`" + componentName + "` + "`")) + (`, expected a ReactNode.");
+                        return isNode(props[propName]) ? null : new PropTypeError("Invalid " + location + " ` + "`" + `" + propFullName + "` + "`" + ` supplied to ` + "`" + `" + componentName + "` + "`" + `, expected a ReactNode.");
                     }
                     return createChainableTypeChecker(validate);
                 }(),
@@ -25925,7 +25931,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
     var emptyFunction = __webpack_require__(39), invariant = __webpack_require__(70), ReactPropTypesSecret = __webpack_require__(132);
     module.exports = function() {
         function shim(props, propName, componentName, location, propFullName, secret) {
-            secret !== ReactPropTypesSecret && invariant(!1, "Calling PropTypes validators directly is not supported by the ` + ("`" + `prop-types`))) + (("`" + (` package. Use PropTypes.checkPropTypes() to call them. Read more at http://fb.me/use-check-prop-types");
+            secret !== ReactPropTypesSecret && invariant(!1, "Calling PropTypes validators directly is not supported by the ` + "`" + `prop-types` + "`" + ` package. Use PropTypes.checkPropTypes() to call them. Read more at http://fb.me/use-check-prop-types");
         }
         function getShim() {
             return shim;
@@ -26153,7 +26159,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 dark: dark,
                 light: light
             };
-            return "production" !== process.env.NODE_ENV && (0, _warning2.default)(types[type], "Material-UI: the palette type ` + "`")) + (`" + type + "` + ("`" + ` is not supported."), 
+            return "production" !== process.env.NODE_ENV && (0, _warning2.default)(types[type], "Material-UI: the palette type ` + "`" + `" + type + "` + "`" + ` is not supported."), 
             (0, _deepmerge2.default)((0, _extends3.default)({
                 common: _common2.default,
                 type: type,
@@ -26359,7 +26365,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 return Number((.2126 * rgb[0] + .7152 * rgb[1] + .0722 * rgb[2]).toFixed(3));
             }
             if (decomposedColor.type.indexOf("hsl") > -1) return decomposedColor.values[2] / 100;
-            throw new Error("Material-UI: unsupported `)))) + ((("`" + (`" + color + "` + "`")) + (` color.");
+            throw new Error("Material-UI: unsupported ` + "`" + `" + color + "` + "`" + ` color.");
         }
         function emphasize(color) {
             var coefficient = arguments.length > 1 && void 0 !== arguments[1] ? arguments[1] : .15;
@@ -26598,10 +26604,36 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             version: null,
             commit: null
         },
-        home: {},
-        chain: {},
-        txpool: {},
-        network: {},
+        home: {
+            head: null,
+            peers: 0,
+            pending: 0,
+            queued: 0,
+            syncing: !1,
+            mining: !1
+        },
+        chain: {
+            blocks: [],
+            blockTime: [],
+            gasUsed: []
+        },
+        txpool: {
+            pending: [],
+            queued: []
+        },
+        network: {
+            peers: [],
+            peerCount: []
+        },
+        alien: {
+            number: 0,
+            loopStartTime: 0,
+            confirmedNumber: 0,
+            confirmLag: 0,
+            signers: [],
+            proposals: [],
+            sideChains: []
+        },
         system: {
             activeMemory: [],
             virtualMemory: [],
@@ -26620,10 +26652,21 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             version: replacer,
             commit: replacer
         },
-        home: null,
-        chain: null,
-        txpool: null,
-        network: null,
+        home: replacer,
+        chain: {
+            blocks: appender(50),
+            blockTime: appender(200),
+            gasUsed: appender(200)
+        },
+        txpool: {
+            pending: appender(200),
+            queued: appender(200)
+        },
+        network: {
+            peers: replacer,
+            peerCount: appender(200)
+        },
+        alien: replacer,
         system: {
             activeMemory: appender(200),
             virtualMemory: appender(200),
@@ -27144,7 +27187,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
         Object.defineProperty(exports, "__esModule", {
             value: !0
         });
-        var CSS = global.CSS, env = process.env.NODE_ENV, escapeRegex = /([[\].#*$><+~=|^:(),"'` + ("`" + `])/g;
+        var CSS = global.CSS, env = process.env.NODE_ENV, escapeRegex = /([[\].#*$><+~=|^:(),"'` + "`" + `])/g;
         exports.default = function(str) {
             return "production" === env ? str : CSS && CSS.escape ? CSS.escape(str) : str.replace(escapeRegex, "\\$1");
         };
@@ -28525,7 +28568,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
     "use strict";
     (function(process) {
         function createGenerateClassName() {
-            var options = arguments.length > 0 && void 0 !== arguments[0] ? arguments[0] : {}, _options$dangerouslyU = options.dangerouslyUseGlobalCSS, dangerouslyUseGlobalCSS = void 0 !== _options$dangerouslyU && _options$dangerouslyU, _options$productionPr = options.productionPrefix, productionPrefix = void 0 === _options$productionPr ? "jss" : _options$productionPr, escapeRegex = /([[\].#*$><+~=|^:(),"'`))) + (("`" + (`\s])/g, ruleCounter = 0;
+            var options = arguments.length > 0 && void 0 !== arguments[0] ? arguments[0] : {}, _options$dangerouslyU = options.dangerouslyUseGlobalCSS, dangerouslyUseGlobalCSS = void 0 !== _options$dangerouslyU && _options$dangerouslyU, _options$productionPr = options.productionPrefix, productionPrefix = void 0 === _options$productionPr ? "jss" : _options$productionPr, escapeRegex = /([[\].#*$><+~=|^:(),"'` + "`" + `\s])/g, ruleCounter = 0;
             return "production" === process.env.NODE_ENV && "undefined" != typeof window && "jss" === productionPrefix && (generatorCounter += 1) > 2 && console.error([ "Material-UI: we have detected more than needed creation of the class name generator.", "You should only use one class name generator on the client side.", "If you do otherwise, you take the risk to have conflicting class names in production." ].join("\n")), 
             function(rule, styleSheet) {
                 if (ruleCounter += 1, "production" !== process.env.NODE_ENV && (0, _warning2.default)(ruleCounter < 1e10, [ "Material-UI: you might have a memory leak.", "The ruleCounter is not supposed to grow that much." ].join("")), 
@@ -28568,7 +28611,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 if (!theme.overrides || !name || !theme.overrides[name]) return styles;
                 var overrides = theme.overrides[name], stylesWithOverrides = (0, _extends3.default)({}, styles);
                 return (0, _keys2.default)(overrides).forEach(function(key) {
-                    "production" !== process.env.NODE_ENV && (0, _warning2.default)(stylesWithOverrides[key], [ "Material-UI: you are trying to override a style that does not exist.", "Fix the ` + "`")) + (`" + key + "` + ("`" + ` key of `))))) + (((("`" + (`theme.overrides." + name + "` + "`")) + (`." ].join("\n")), 
+                    "production" !== process.env.NODE_ENV && (0, _warning2.default)(stylesWithOverrides[key], [ "Material-UI: you are trying to override a style that does not exist.", "Fix the ` + "`" + `" + key + "` + "`" + ` key of ` + "`" + `theme.overrides." + name + "` + "`" + `." ].join("\n")), 
                     stylesWithOverrides[key] = (0, _deepmerge2.default)(stylesWithOverrides[key], overrides[key]);
                 }), stylesWithOverrides;
             }
@@ -28794,7 +28837,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
         function Paper(props) {
             var classes = props.classes, classNameProp = props.className, Component = props.component, square = props.square, elevation = props.elevation, other = (0, 
             _objectWithoutProperties3.default)(props, [ "classes", "className", "component", "square", "elevation" ]);
-            "production" !== process.env.NODE_ENV && (0, _warning2.default)(elevation >= 0 && elevation < 25, "Material-UI: this elevation ` + ("`" + `" + elevation + "`))) + (("`" + (` is not implemented.");
+            "production" !== process.env.NODE_ENV && (0, _warning2.default)(elevation >= 0 && elevation < 25, "Material-UI: this elevation ` + "`" + `" + elevation + "` + "`" + ` is not implemented.");
             var className = (0, _classnames2.default)(classes.root, classes["shadow" + elevation], (0, 
             _defineProperty3.default)({}, classes.rounded, !square), classNameProp);
             return _react2.default.createElement(Component, (0, _extends3.default)({
@@ -31176,7 +31219,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             return protoProps && defineProperties(Constructor.prototype, protoProps), staticProps && defineProperties(Constructor, staticProps), 
             Constructor;
         };
-    }(), _react = __webpack_require__(0), _react2 = _interopRequireDefault(_react), _withStyles = __webpack_require__(10), _withStyles2 = _interopRequireDefault(_withStyles), _common = __webpack_require__(77), _Footer = __webpack_require__(512), _Footer2 = _interopRequireDefault(_Footer), styles = {
+    }(), _react = __webpack_require__(0), _react2 = _interopRequireDefault(_react), _withStyles = __webpack_require__(10), _withStyles2 = _interopRequireDefault(_withStyles), _common = __webpack_require__(77), _Footer = __webpack_require__(512), _Footer2 = _interopRequireDefault(_Footer), _Alien = __webpack_require__(806), _Alien2 = _interopRequireDefault(_Alien), styles = {
         wrapper: {
             display: "flex",
             flexDirection: "column",
@@ -31203,9 +31246,37 @@ var _bundleJs = []byte((((((((((`!function(modules) {
                 var _props = this.props, classes = _props.classes, active = _props.active, content = _props.content, shouldUpdate = _props.shouldUpdate, children = null;
                 switch (active) {
                   case _common.MENU.get("home").id:
+                    var head = content.home.head;
+                    children = _react2.default.createElement("div", null, _react2.default.createElement("div", null, "Head: ", head ? "#" + head.number + " [" + head.hash + "]" : "unknown"), _react2.default.createElement("div", null, "Peers: ", content.home.peers), _react2.default.createElement("div", null, "Transactions: ", content.home.pending, " pending, ", content.home.queued, " queued"), _react2.default.createElement("div", null, content.home.syncing ? "Syncing" : "Synced", content.home.mining ? ", mining" : ""));
+                    break;
+
                   case _common.MENU.get("chain").id:
+                    children = _react2.default.createElement("div", null, content.chain.blocks.slice().reverse().map(function(block) {
+                        return _react2.default.createElement("div", {
+                            key: block.hash
+                        }, "#", block.number, " [", block.hash, "] by ", block.miner, ": ", block.txs, " txs, ", block.gasUsed, "/", block.gasLimit, " gas");
+                    }));
+                    break;
+
                   case _common.MENU.get("txpool").id:
+                    var pending = content.txpool.pending[content.txpool.pending.length - 1], queued = content.txpool.queued[content.txpool.queued.length - 1];
+                    children = _react2.default.createElement("div", null, _react2.default.createElement("div", null, "Pending: ", pending ? pending.value : 0), _react2.default.createElement("div", null, "Queued: ", queued ? queued.value : 0));
+                    break;
+
                   case _common.MENU.get("network").id:
+                    children = _react2.default.createElement("div", null, content.network.peers.map(function(peer) {
+                        return _react2.default.createElement("div", {
+                            key: peer.id
+                        }, peer.name, " (", peer.network.remoteAddress, ")");
+                    }));
+                    break;
+
+                  case _common.MENU.get("alien").id:
+                    children = _react2.default.createElement(_Alien2.default, {
+                        content: content.alien
+                    });
+                    break;
+
                   case _common.MENU.get("system").id:
                     children = _react2.default.createElement("div", null, "Work in progress.");
                     break;
@@ -31633,7 +31704,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
         return function(requiredProp) {
             return function(props, propName, componentName, location, propFullName) {
                 var propFullNameSafe = propFullName || propName;
-                return void 0 === props[propName] || props[requiredProp] ? null : new Error("The property ` + "`")) + (`" + propFullNameSafe + "` + ("`" + ` of `)))) + ((("`" + (`" + componentNameInError + "` + "`")) + (` must be used on ` + ("`" + `" + requiredProp + "`))) + (("`" + (`.");
+                return void 0 === props[propName] || props[requiredProp] ? null : new Error("The property ` + "`" + `" + propFullNameSafe + "` + "`" + ` of ` + "`" + `" + componentNameInError + "` + "`" + ` must be used on ` + "`" + `" + requiredProp + "` + "`" + `.");
             };
         };
     };
@@ -32066,7 +32137,7 @@ var _bundleJs = []byte((((((((((`!function(modules) {
             props.xlDown, props.xlUp, props.xsDown, props.xsUp, (0, _objectWithoutProperties3.default)(props, [ "children", "classes", "className", "lgDown", "lgUp", "mdDown", "mdUp", "only", "smDown", "smUp", "xlDown", "xlUp", "xsDown", "xsUp" ]));
             "production" !== process.env.NODE_ENV && (0, _warning2.default)(0 === (0, _keys2.default)(other).length || 1 === (0, 
             _keys2.default)(other).length && other.hasOwnProperty("ref"), "Material-UI: unsupported properties received " + (0, 
-            _keys2.default)(other).join(", ") + " by ` + "`")) + (`<Hidden />` + ("`" + `.");
+            _keys2.default)(other).join(", ") + " by ` + "`" + `<Hidden />` + "`" + `.");
             var classNames = [];
             className && classNames.push(className);
             for (var i = 0; i < _createBreakpoints.keys.length; i += 1) {
@@ -38422,7 +38493,158 @@ var _bundleJs = []byte((((((((((`!function(modules) {
         } ]), CustomTooltip;
     }(_react.Component));
     exports.default = CustomTooltip;
-} ]);`)))))))))))
+}, function(module, exports, __webpack_require__) {
+    "use strict";
+    function _interopRequireDefault(obj) {
+        return obj && obj.__esModule ? obj : {
+            default: obj
+        };
+    }
+    function _classCallCheck(instance, Constructor) {
+        if (!(instance instanceof Constructor)) throw new TypeError("Cannot call a class as a function");
+    }
+    function _possibleConstructorReturn(self, call) {
+        if (!self) throw new ReferenceError("this hasn't been initialised - super() hasn't been called");
+        return !call || "object" != typeof call && "function" != typeof call ? self : call;
+    }
+    function _inherits(subClass, superClass) {
+        if ("function" != typeof superClass && null !== superClass) throw new TypeError("Super expression must either be null or a function, not " + typeof superClass);
+        subClass.prototype = Object.create(superClass && superClass.prototype, {
+            constructor: {
+                value: subClass,
+                enumerable: !1,
+                writable: !0,
+                configurable: !0
+            }
+        }), superClass && (Object.setPrototypeOf ? Object.setPrototypeOf(subClass, superClass) : subClass.__proto__ = superClass);
+    }
+    Object.defineProperty(exports, "__esModule", {
+        value: !0
+    });
+    var _extends = Object.assign || function(target) {
+        for (var i = 1; i < arguments.length; i++) {
+            var source = arguments[i];
+            for (var key in source) Object.prototype.hasOwnProperty.call(source, key) && (target[key] = source[key]);
+        }
+        return target;
+    }, _createClass = function() {
+        function defineProperties(target, props) {
+            for (var i = 0; i < props.length; i++) {
+                var descriptor = props[i];
+                descriptor.enumerable = descriptor.enumerable || !1, descriptor.configurable = !0, 
+                "value" in descriptor && (descriptor.writable = !0), Object.defineProperty(target, descriptor.key, descriptor);
+            }
+        }
+        return function(Constructor, protoProps, staticProps) {
+            return protoProps && defineProperties(Constructor.prototype, protoProps), staticProps && defineProperties(Constructor, staticProps), 
+            Constructor;
+        };
+    }(), _react = __webpack_require__(0), _react2 = _interopRequireDefault(_react), styles = {
+        table: {
+            width: "100%",
+            marginBottom: 24,
+            borderSpacing: 0
+        },
+        cell: {
+            padding: "4px 8px",
+            textAlign: "left"
+        },
+        inturn: {
+            fontWeight: "bold"
+        },
+        bar: {
+            height: 4,
+            backgroundColor: "rgba(255, 255, 255, 0.54)"
+        }
+    }, Alien = function(_Component) {
+        function Alien() {
+            return _classCallCheck(this, Alien), _possibleConstructorReturn(this, (Alien.__proto__ || Object.getPrototypeOf(Alien)).apply(this, arguments));
+        }
+        return _inherits(Alien, _Component), _createClass(Alien, [ {
+            key: "render",
+            value: function() {
+                var content = this.props.content;
+                return _react2.default.createElement("div", null, _react2.default.createElement("h3", null, "Loop started at ", new Date(1e3 * content.loopStartTime).toLocaleString()), _react2.default.createElement("div", null, "Block #", content.number, ", confirmed #", content.confirmedNumber, " (", content.confirmLag, " blocks behind)"), _react2.default.createElement("h3", null, "Signers"), _react2.default.createElement("table", {
+                    style: styles.table
+                }, _react2.default.createElement("thead", null, _react2.default.createElement("tr", null, _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "#"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Address"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Stake"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Punished"))), _react2.default.createElement("tbody", null, content.signers.map(function(signer, index) {
+                    return _react2.default.createElement("tr", {
+                        key: index,
+                        style: signer.inturn ? styles.inturn : null
+                    }, _react2.default.createElement("td", {
+                    style: styles.cell
+                }, signer.inturn ? index + " (in turn)" : index), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, signer.address), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, signer.stake), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, signer.punished));
+                }))), _react2.default.createElement("h3", null, "Proposals"), _react2.default.createElement("table", {
+                    style: styles.table
+                }, _react2.default.createElement("thead", null, _react2.default.createElement("tr", null, _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Hash"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Type"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Proposer"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Deadline"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Yes"))), _react2.default.createElement("tbody", null, content.proposals.map(function(proposal) {
+                    return _react2.default.createElement("tr", {
+                        key: proposal.hash
+                    }, _react2.default.createElement("td", {
+                    style: styles.cell
+                }, proposal.hash), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, proposal.type), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, proposal.proposer), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, "#", proposal.deadline), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, proposal.progress.toFixed(2), "% of ", proposal.total, _react2.default.createElement("div", {
+                        style: _extends({}, styles.bar, {
+                            width: Math.min(proposal.progress, 100) + "%"
+                        })
+                    })));
+                }))), _react2.default.createElement("h3", null, "Side chains"), _react2.default.createElement("table", {
+                    style: styles.table
+                }, _react2.default.createElement("thead", null, _react2.default.createElement("tr", null, _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Hash"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Last confirmed"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Highest"), _react2.default.createElement("th", {
+                    style: styles.cell
+                }, "Lag"))), _react2.default.createElement("tbody", null, content.sideChains.map(function(sideChain) {
+                    return _react2.default.createElement("tr", {
+                        key: sideChain.hash
+                    }, _react2.default.createElement("td", {
+                    style: styles.cell
+                }, sideChain.hash), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, "#", sideChain.lastConfirmed), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, "#", sideChain.maxNumber), _react2.default.createElement("td", {
+                    style: styles.cell
+                }, sideChain.lag));
+                }))));
+            }
+        } ]), Alien;
+    }(_react.Component);
+    exports.default = Alien;
+} ]);`)
 
 func bundleJsBytes() ([]byte, error) {
 	return _bundleJs, nil
@@ -38435,7 +38657,7 @@ func bundleJs() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "bundle.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe2, 0xc0, 0xfa, 0x13, 0x71, 0x8b, 0x49, 0x5c, 0xa5, 0xae, 0xc8, 0x41, 0x3, 0x7a, 0x1, 0x3d, 0x5, 0x2a, 0xe7, 0xb7, 0x80, 0x2c, 0x6e, 0xbd, 0x9, 0xaf, 0xab, 0xd5, 0x25, 0xce, 0x1, 0x9f}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe7, 0x9e, 0x2d, 0xc7, 0x11, 0xb1, 0xc9, 0x6, 0x70, 0x3f, 0x68, 0x89, 0x32, 0x57, 0xaf, 0x5a, 0xc3, 0xa3, 0x10, 0x2a, 0xfb, 0x0, 0xcb, 0xab, 0x9e, 0x7a, 0x49, 0xf, 0xb2, 0x9e, 0xb4, 0xed}}
 	return a, nil
 }
 
@@ -38539,11 +38761,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
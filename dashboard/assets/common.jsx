@@ -42,6 +42,12 @@ const menuSkeletons: Array<{|id: string, menu: ProvidedMenuProp|}> = [
 			title: 'Network',
 			icon:  'globe',
 		},
+	}, {
+		id:   'alien',
+		menu: {
+			title: 'Alien',
+			icon:  'users',
+		},
 	}, {
 		id:   'system',
 		menu: {
@@ -0,0 +1,130 @@
+// @flow
+
+// Copyright 2019 The TTC Authors
+// This file is part of the TTC library.
+//
+// The TTC library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The TTC library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the TTC library. If not, see <http://www.gnu.org/licenses/>.
+
+import React, {Component} from 'react';
+
+import type {Alien as AlienContent} from '../types/content';
+
+// styles contains the constant styles of the component.
+const styles = {
+	table: {
+		width:         '100%',
+		marginBottom:  24,
+		borderSpacing: 0,
+	},
+	cell: {
+		padding:   '4px 8px',
+		textAlign: 'left',
+	},
+	inturn: {
+		fontWeight: 'bold',
+	},
+	bar: {
+		height:          4,
+		backgroundColor: 'rgba(255, 255, 255, 0.54)',
+	},
+};
+
+export type Props = {
+	content: AlienContent,
+};
+
+// Alien renders the state of the alien consensus: the signer queue of the current
+// loop, the open proposals with their vote progress and the side chain confirmations.
+class Alien extends Component<Props> {
+	render() {
+		const {content} = this.props;
+
+		return (
+			<div>
+				<h3>Loop started at {new Date(content.loopStartTime * 1000).toLocaleString()}</h3>
+				<div>Block #{content.number}, confirmed #{content.confirmedNumber} ({content.confirmLag} blocks behind)</div>
+				<h3>Signers</h3>
+				<table style={styles.table}>
+					<thead>
+						<tr>
+							<th style={styles.cell}>#</th>
+							<th style={styles.cell}>Address</th>
+							<th style={styles.cell}>Stake</th>
+							<th style={styles.cell}>Punished</th>
+						</tr>
+					</thead>
+					<tbody>
+						{content.signers.map((signer, index) => (
+							<tr key={index} style={signer.inturn ? styles.inturn : null}>
+								<td style={styles.cell}>{signer.inturn ? `${index} (in turn)` : index}</td>
+								<td style={styles.cell}>{signer.address}</td>
+								<td style={styles.cell}>{signer.stake}</td>
+								<td style={styles.cell}>{signer.punished}</td>
+							</tr>
+						))}
+					</tbody>
+				</table>
+				<h3>Proposals</h3>
+				<table style={styles.table}>
+					<thead>
+						<tr>
+							<th style={styles.cell}>Hash</th>
+							<th style={styles.cell}>Type</th>
+							<th style={styles.cell}>Proposer</th>
+							<th style={styles.cell}>Deadline</th>
+							<th style={styles.cell}>Yes</th>
+						</tr>
+					</thead>
+					<tbody>
+						{content.proposals.map(proposal => (
+							<tr key={proposal.hash}>
+								<td style={styles.cell}>{proposal.hash}</td>
+								<td style={styles.cell}>{proposal.type}</td>
+								<td style={styles.cell}>{proposal.proposer}</td>
+								<td style={styles.cell}>#{proposal.deadline}</td>
+								<td style={styles.cell}>
+									{proposal.progress.toFixed(2)}% of {proposal.total}
+									<div style={{...styles.bar, width: `${Math.min(proposal.progress, 100)}%`}} />
+								</td>
+							</tr>
+						))}
+					</tbody>
+				</table>
+				<h3>Side chains</h3>
+				<table style={styles.table}>
+					<thead>
+						<tr>
+							<th style={styles.cell}>Hash</th>
+							<th style={styles.cell}>Last confirmed</th>
+							<th style={styles.cell}>Highest</th>
+							<th style={styles.cell}>Lag</th>
+						</tr>
+					</thead>
+					<tbody>
+						{content.sideChains.map(sideChain => (
+							<tr key={sideChain.hash}>
+								<td style={styles.cell}>{sideChain.hash}</td>
+								<td style={styles.cell}>#{sideChain.lastConfirmed}</td>
+								<td style={styles.cell}>#{sideChain.maxNumber}</td>
+								<td style={styles.cell}>{sideChain.lag}</td>
+							</tr>
+						))}
+					</tbody>
+				</table>
+			</div>
+		);
+	}
+}
+
+export default Alien;
@@ -81,11 +81,37 @@ const defaultContent: Content = {
 		version: null,
 		commit:  null,
 	},
-	home:    {},
-	chain:   {},
-	txpool:  {},
-	network: {},
-	system:  {
+	home: {
+		head:    null,
+		peers:   0,
+		pending: 0,
+		queued:  0,
+		syncing: false,
+		mining:  false,
+	},
+	chain: {
+		blocks:    [],
+		blockTime: [],
+		gasUsed:   [],
+	},
+	txpool: {
+		pending: [],
+		queued:  [],
+	},
+	network: {
+		peers:     [],
+		peerCount: [],
+	},
+	alien: {
+		number:          0,
+		loopStartTime:   0,
+		confirmedNumber: 0,
+		confirmLag:      0,
+		signers:         [],
+		proposals:       [],
+		sideChains:      [],
+	},
+	system: {
 		activeMemory:   [],
 		virtualMemory:  [],
 		networkIngress: [],
@@ -95,7 +121,7 @@ const defaultContent: Content = {
 		diskRead:       [],
 		diskWrite:      [],
 	},
-	logs:    {
+	logs: {
 		log: [],
 	},
 };
@@ -108,11 +134,22 @@ const updaters = {
 		version: replacer,
 		commit:  replacer,
 	},
-	home:    null,
-	chain:   null,
-	txpool:  null,
-	network: null,
-	system:  {
+	home:  replacer,
+	chain: {
+		blocks:    appender(50),
+		blockTime: appender(200),
+		gasUsed:   appender(200),
+	},
+	txpool: {
+		pending: appender(200),
+		queued:  appender(200),
+	},
+	network: {
+		peers:     replacer,
+		peerCount: appender(200),
+	},
+	alien:  replacer,
+	system: {
 		activeMemory:   appender(200),
 		virtualMemory:  appender(200),
 		networkIngress: appender(200),
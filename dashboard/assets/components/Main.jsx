@@ -22,6 +22,7 @@ import withStyles from 'material-ui/styles/withStyles';
 
 import {MENU} from '../common';
 import Footer from './Footer';
+import Alien from './Alien';
 import type {Content} from '../types/content';
 
 // styles contains the constant styles of the component.
@@ -61,10 +62,52 @@ class Main extends Component<Props> {
 
 		let children = null;
 		switch (active) {
-		case MENU.get('home').id:
+		case MENU.get('home').id: {
+			const {head} = content.home;
+			children = (
+				<div>
+					<div>Head: {head ? `#${head.number} [${head.hash}]` : 'unknown'}</div>
+					<div>Peers: {content.home.peers}</div>
+					<div>Transactions: {content.home.pending} pending, {content.home.queued} queued</div>
+					<div>{content.home.syncing ? 'Syncing' : 'Synced'}{content.home.mining ? ', mining' : ''}</div>
+				</div>
+			);
+			break;
+		}
 		case MENU.get('chain').id:
-		case MENU.get('txpool').id:
+			children = (
+				<div>
+					{content.chain.blocks.slice().reverse().map(block => (
+						<div key={block.hash}>
+							#{block.number} [{block.hash}] by {block.miner}: {block.txs} txs, {block.gasUsed}/{block.gasLimit} gas
+						</div>
+					))}
+				</div>
+			);
+			break;
+		case MENU.get('txpool').id: {
+			const pending = content.txpool.pending[content.txpool.pending.length - 1];
+			const queued = content.txpool.queued[content.txpool.queued.length - 1];
+			children = (
+				<div>
+					<div>Pending: {pending ? pending.value : 0}</div>
+					<div>Queued: {queued ? queued.value : 0}</div>
+				</div>
+			);
+			break;
+		}
 		case MENU.get('network').id:
+			children = (
+				<div>
+					{content.network.peers.map(peer => (
+						<div key={peer.id}>{peer.name} ({peer.network.remoteAddress})</div>
+					))}
+				</div>
+			);
+			break;
+		case MENU.get('alien').id:
+			children = <Alien content={content.alien} />;
+			break;
 		case MENU.get('system').id:
 			children = <div>Work in progress.</div>;
 			break;
@@ -22,6 +22,7 @@ export type Content = {
 	chain: Chain,
 	txpool: TxPool,
 	network: Network,
+	alien: Alien,
 	system: System,
 	logs: Logs,
 };
@@ -39,19 +40,74 @@ export type General = {
 };
 
 export type Home = {
-	/* TODO (kurkomisi) */
+	head: ?BlockInfo,
+	peers: number,
+	pending: number,
+	queued: number,
+	syncing: boolean,
+	mining: boolean,
+};
+
+export type BlockInfo = {
+	number: number,
+	hash: string,
+	time: number,
+	miner: string,
+	txs: number,
+	gasUsed: number,
+	gasLimit: number,
 };
 
 export type Chain = {
-	/* TODO (kurkomisi) */
+	blocks: Array<BlockInfo>,
+	blockTime: ChartEntries,
+	gasUsed: ChartEntries,
 };
 
 export type TxPool = {
-	/* TODO (kurkomisi) */
+	pending: ChartEntries,
+	queued: ChartEntries,
 };
 
 export type Network = {
-	/* TODO (kurkomisi) */
+	peers: Array<Object>,
+	peerCount: ChartEntries,
+};
+
+export type Alien = {
+	number: number,
+	loopStartTime: number,
+	confirmedNumber: number,
+	confirmLag: number,
+	signers: Array<AlienSigner>,
+	proposals: Array<AlienProposal>,
+	sideChains: Array<AlienSideChain>,
+};
+
+export type AlienSigner = {
+	address: string,
+	inturn: boolean,
+	punished: number,
+	stake: string,
+};
+
+export type AlienProposal = {
+	hash: string,
+	type: number,
+	proposer: string,
+	received: number,
+	deadline: number,
+	yes: string,
+	no: string,
+	total: string,
+	progress: number,
+};
+
+export type AlienSideChain = {
+	hash: string,
+	lastConfirmed: number,
+	maxNumber: number,
+	lag: number,
 };
 
 export type System = {
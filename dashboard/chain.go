@@ -0,0 +1,345 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package dashboard
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/eth/downloader"
+	"github.com/TTCECO/gttc/log"
+)
+
+const (
+	blockSampleLimit     = 50  // Maximum number of recent blocks listed on the chain panel
+	blockTimeSampleLimit = 200 // Maximum number of block time data samples
+	gasUsedSampleLimit   = 200 // Maximum number of gas used data samples
+	pendingSampleLimit   = 200 // Maximum number of pending transaction data samples
+	queuedSampleLimit    = 200 // Maximum number of queued transaction data samples
+	peerCountSampleLimit = 200 // Maximum number of peer count data samples
+)
+
+// chainReader returns the chain of the running node, or nil if there is none.
+func (db *Dashboard) chainReader() consensus.ChainReader {
+	switch {
+	case db.eth != nil:
+		return db.eth.BlockChain()
+	case db.les != nil:
+		return db.les.BlockChain().HeaderChain()
+	}
+	return nil
+}
+
+// engine returns the consensus engine of the running node.
+func (db *Dashboard) engine() consensus.Engine {
+	if db.eth != nil {
+		return db.eth.Engine()
+	}
+	return db.les.Engine()
+}
+
+// downloader returns the downloader of the running node.
+func (db *Dashboard) downloader() *downloader.Downloader {
+	if db.eth != nil {
+		return db.eth.Downloader()
+	}
+	return db.les.Downloader()
+}
+
+// txPoolStats returns the number of pending and queued transactions of the
+// running node. Light clients do not queue transactions.
+func (db *Dashboard) txPoolStats() (int, int) {
+	if db.eth != nil {
+		return db.eth.TxPool().Stats()
+	}
+	return db.les.TxPool().Stats(), 0
+}
+
+// collectChain collects the chain, transaction pool, network and consensus
+// data of the running node to display on the dashboard.
+func (db *Dashboard) collectChain() {
+	defer db.wg.Done()
+
+	heads := make(chan core.ChainHeadEvent, 16)
+	if db.eth != nil {
+		sub := db.eth.BlockChain().SubscribeChainHeadEvent(heads)
+		defer sub.Unsubscribe()
+	} else {
+		sub := db.les.BlockChain().SubscribeChainHeadEvent(heads)
+		defer sub.Unsubscribe()
+	}
+	if head := db.chainReader().CurrentHeader(); head != nil {
+		db.updateHead(head, nil)
+	}
+	for {
+		select {
+		case errc := <-db.quit:
+			errc <- nil
+			return
+		case ev := <-heads:
+			db.updateHead(ev.Block.Header(), ev.Block)
+		case <-time.After(db.config.Refresh):
+			db.updatePool()
+		}
+	}
+}
+
+// updateHead collects and sends the data related to a new chain head. The block
+// is nil if only the header is known.
+func (db *Dashboard) updateHead(header *types.Header, block *types.Block) {
+	chain := db.chainReader()
+
+	now := time.Now()
+	info := &BlockInfo{
+		Number:   header.Number.Uint64(),
+		Hash:     header.Hash(),
+		Time:     header.Time.Uint64(),
+		Miner:    header.Coinbase,
+		GasUsed:  header.GasUsed,
+		GasLimit: header.GasLimit,
+	}
+	if block != nil {
+		info.Txs = len(block.Transactions())
+	}
+	blockTime := &ChartEntry{
+		Time: now,
+	}
+	if header.Number.Sign() > 0 {
+		if parent := chain.GetHeader(header.ParentHash, info.Number-1); parent != nil {
+			blockTime.Value = float64(new(big.Int).Sub(header.Time, parent.Time).Uint64())
+		}
+	}
+	gasUsed := &ChartEntry{
+		Time:  now,
+		Value: float64(header.GasUsed),
+	}
+	home := db.assembleHome(info)
+
+	var alienMsg *AlienMessage
+	if engine, ok := db.engine().(*alien.Alien); ok {
+		alienMsg = assembleAlien(chain, engine, header)
+	}
+	db.lock.Lock()
+	db.history.Chain.Blocks = append(db.history.Chain.Blocks, info)
+	if len(db.history.Chain.Blocks) > blockSampleLimit {
+		db.history.Chain.Blocks = db.history.Chain.Blocks[1:]
+	}
+	db.history.Chain.BlockTime = append(db.history.Chain.BlockTime[1:], blockTime)
+	db.history.Chain.GasUsed = append(db.history.Chain.GasUsed[1:], gasUsed)
+	db.history.Home = home
+	if alienMsg != nil {
+		db.history.Alien = alienMsg
+	}
+	db.lock.Unlock()
+
+	db.sendToAll(&Message{
+		Home: home,
+		Chain: &ChainMessage{
+			Blocks:    []*BlockInfo{info},
+			BlockTime: ChartEntries{blockTime},
+			GasUsed:   ChartEntries{gasUsed},
+		},
+		Alien: alienMsg,
+	})
+}
+
+// updatePool collects and sends the periodically sampled transaction pool and
+// network data.
+func (db *Dashboard) updatePool() {
+	now := time.Now()
+	pending, queued := db.txPoolStats()
+
+	pendingEntry := &ChartEntry{
+		Time:  now,
+		Value: float64(pending),
+	}
+	queuedEntry := &ChartEntry{
+		Time:  now,
+		Value: float64(queued),
+	}
+	peers := db.server.PeersInfo()
+	peerCount := &ChartEntry{
+		Time:  now,
+		Value: float64(len(peers)),
+	}
+	db.lock.Lock()
+	db.history.TxPool.Pending = append(db.history.TxPool.Pending[1:], pendingEntry)
+	db.history.TxPool.Queued = append(db.history.TxPool.Queued[1:], queuedEntry)
+	db.history.Network.Peers = peers
+	db.history.Network.PeerCount = append(db.history.Network.PeerCount[1:], peerCount)
+
+	var home *HomeMessage
+	if db.history.Home != nil {
+		update := *db.history.Home
+		update.Peers, update.Pending, update.Queued = len(peers), pending, queued
+		home = &update
+		db.history.Home = home
+	}
+	db.lock.Unlock()
+
+	db.sendToAll(&Message{
+		Home: home,
+		TxPool: &TxPoolMessage{
+			Pending: ChartEntries{pendingEntry},
+			Queued:  ChartEntries{queuedEntry},
+		},
+		Network: &NetworkMessage{
+			Peers:     peers,
+			PeerCount: ChartEntries{peerCount},
+		},
+	})
+}
+
+// assembleHome collects the summary of the node shown on the home panel.
+func (db *Dashboard) assembleHome(head *BlockInfo) *HomeMessage {
+	pending, queued := db.txPoolStats()
+	progress := db.downloader().Progress()
+
+	home := &HomeMessage{
+		Head:    head,
+		Peers:   db.server.PeerCount(),
+		Pending: pending,
+		Queued:  queued,
+		Syncing: progress.CurrentBlock < progress.HighestBlock,
+	}
+	if db.eth != nil {
+		home.Mining = db.eth.IsMining()
+	}
+	return home
+}
+
+// assembleAlien collects the alien consensus state as of the given header.
+func assembleAlien(chain consensus.ChainReader, engine *alien.Alien, header *types.Header) *AlienMessage {
+	snap, err := engine.Snapshot(chain, header)
+	if err != nil {
+		log.Debug("Failed to retrieve alien snapshot", "number", header.Number, "err", err)
+		return nil
+	}
+	msg := &AlienMessage{
+		Number:          snap.Number,
+		LoopStartTime:   snap.LoopStartTime,
+		ConfirmedNumber: snap.ConfirmedNumber,
+		Signers:         []*AlienSigner{},
+		Proposals:       []*AlienProposal{},
+		SideChains:      []*AlienSideChain{},
+	}
+	if snap.Number > snap.ConfirmedNumber {
+		msg.ConfirmLag = snap.Number - snap.ConfirmedNumber
+	}
+	// Mark the signer in turn to seal the next block
+	inturn := -1
+	if len(snap.Signers) > 0 && snap.Period > 0 {
+		next := header.Time.Uint64() + snap.Period
+		if next >= snap.LoopStartTime {
+			inturn = int((next - snap.LoopStartTime) / snap.Period % uint64(len(snap.Signers)))
+		}
+	}
+	for i, signer := range snap.Signers {
+		stake := new(big.Int)
+		if tally, ok := snap.Tally[*signer]; ok {
+			stake = tally
+		}
+		msg.Signers = append(msg.Signers, &AlienSigner{
+			Address:  *signer,
+			InTurn:   i == inturn,
+			Punished: snap.Punished[*signer],
+			Stake:    stake.String(),
+		})
+	}
+	// Collect the vote progress of the open proposals
+	total := new(big.Int)
+	for _, stake := range snap.Tally {
+		total.Add(total, stake)
+	}
+	var maxSignerCount uint64
+	if config := chain.Config(); config.Alien != nil {
+		maxSignerCount = config.Alien.MaxSignerCount
+	}
+	for _, proposal := range snap.Proposals {
+		yes, no := new(big.Int), new(big.Int)
+		for _, declare := range proposal.Declares {
+			stake, ok := snap.Tally[declare.Declarer]
+			if !ok {
+				continue
+			}
+			if declare.Decision {
+				yes.Add(yes, stake)
+			} else {
+				no.Add(no, stake)
+			}
+		}
+		var progress float64
+		if total.Sign() > 0 {
+			progress, _ = new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(yes, big.NewInt(100))), new(big.Float).SetInt(total)).Float64()
+		}
+		msg.Proposals = append(msg.Proposals, &AlienProposal{
+			Hash:     proposal.Hash,
+			Type:     proposal.ProposalType,
+			Proposer: proposal.Proposer,
+			Received: proposal.ReceivedNumber.Uint64(),
+			Deadline: proposal.ReceivedNumber.Uint64() + proposal.ValidationLoopCnt*maxSignerCount + 1,
+			Yes:      yes.String(),
+			No:       no.String(),
+			Total:    total.String(),
+			Progress: progress,
+		})
+	}
+	sort.Slice(msg.Proposals, func(i, j int) bool {
+		return msg.Proposals[i].Received < msg.Proposals[j].Received
+	})
+	// Collect the confirmation lag of the side chains
+	for hash, record := range snap.SCRecordMap {
+		sideChain := &AlienSideChain{
+			Hash:          hash,
+			LastConfirmed: record.LastConfirmedNumber,
+			MaxNumber:     record.MaxHeaderNumber,
+		}
+		if record.MaxHeaderNumber > record.LastConfirmedNumber {
+			sideChain.Lag = record.MaxHeaderNumber - record.LastConfirmedNumber
+		}
+		msg.SideChains = append(msg.SideChains, sideChain)
+	}
+	sort.Slice(msg.SideChains, func(i, j int) bool {
+		return bytes.Compare(msg.SideChains[i].Hash[:], msg.SideChains[j].Hash[:]) < 0
+	})
+	return msg
+}
+
+// emptyChainHistory returns the initial chain, transaction pool and network
+// data sent to the dashboards.
+func emptyChainHistory(now time.Time, refresh time.Duration) *Message {
+	return &Message{
+		Chain: &ChainMessage{
+			Blocks:    []*BlockInfo{},
+			BlockTime: emptyChartEntries(now, blockTimeSampleLimit, refresh),
+			GasUsed:   emptyChartEntries(now, gasUsedSampleLimit, refresh),
+		},
+		TxPool: &TxPoolMessage{
+			Pending: emptyChartEntries(now, pendingSampleLimit, refresh),
+			Queued:  emptyChartEntries(now, queuedSampleLimit, refresh),
+		},
+		Network: &NetworkMessage{
+			PeerCount: emptyChartEntries(now, peerCountSampleLimit, refresh),
+		},
+	}
+}
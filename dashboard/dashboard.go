@@ -32,6 +32,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/TTCECO/gttc/eth"
+	"github.com/TTCECO/gttc/les"
 	"github.com/TTCECO/gttc/log"
 	"github.com/TTCECO/gttc/metrics"
 	"github.com/TTCECO/gttc/p2p"
@@ -61,11 +63,17 @@ type Dashboard struct {
 	listener net.Listener
 	conns    map[uint32]*client // Currently live websocket connections
 	charts   *SystemMessage
+	history  *Message // Past chain, transaction pool, network and consensus data
 	commit   string
 	lock     sync.RWMutex // Lock protecting the dashboard's internals
 
-	quit chan chan error // Channel used for graceful exit
-	wg   sync.WaitGroup
+	eth    *eth.Ethereum      // Full node service to collect the chain data from, if any
+	les    *les.LightEthereum // Light client service to collect the chain data from, if any
+	server *p2p.Server        // Peer-to-peer server to collect the network data from
+
+	collectors int             // Number of running data collectors
+	quit       chan chan error // Channel used for graceful exit
+	wg         sync.WaitGroup
 }
 
 // client represents active websocket connection with a remote browser.
@@ -75,8 +83,9 @@ type client struct {
 	logger log.Logger      // Logger for the particular live websocket connection
 }
 
-// New creates a new dashboard instance with the given configuration.
-func New(config *Config, commit string) (*Dashboard, error) {
+// New creates a new dashboard instance with the given configuration. The chain
+// panels are filled from either the full node or the light client service.
+func New(config *Config, commit string, ethServ *eth.Ethereum, lesServ *les.LightEthereum) (*Dashboard, error) {
 	now := time.Now()
 	db := &Dashboard{
 		conns:  make(map[uint32]*client),
@@ -92,7 +101,10 @@ func New(config *Config, commit string) (*Dashboard, error) {
 			DiskRead:       emptyChartEntries(now, diskReadSampleLimit, config.Refresh),
 			DiskWrite:      emptyChartEntries(now, diskWriteSampleLimit, config.Refresh),
 		},
-		commit: commit,
+		history: emptyChainHistory(now, config.Refresh),
+		commit:  commit,
+		eth:     ethServ,
+		les:     lesServ,
 	}
 	return db, nil
 }
@@ -118,9 +130,17 @@ func (db *Dashboard) APIs() []rpc.API { return nil }
 func (db *Dashboard) Start(server *p2p.Server) error {
 	log.Info("Starting dashboard")
 
-	db.wg.Add(2)
+	db.server = server
+	db.collectors = 2
+	if db.eth != nil || db.les != nil {
+		db.collectors++
+	}
+	db.wg.Add(db.collectors)
 	go db.collectData()
-	go db.collectLogs() // In case of removing this line change 2 back to 1 in wg.Add.
+	go db.collectLogs() // In case of removing this line decrement the collectors.
+	if db.eth != nil || db.les != nil {
+		go db.collectChain()
+	}
 
 	http.HandleFunc("/", db.webHandler)
 	http.Handle("/api", websocket.Handler(db.apiHandler))
@@ -145,7 +165,7 @@ func (db *Dashboard) Stop() error {
 	}
 	// Close the collectors.
 	errc := make(chan error, 1)
-	for i := 0; i < 2; i++ {
+	for i := 0; i < db.collectors; i++ {
 		db.quit <- errc
 		if err := <-errc; err != nil {
 			errs = append(errs, err)
@@ -239,6 +259,15 @@ func (db *Dashboard) apiHandler(conn *websocket.Conn) {
 			DiskWrite:      db.charts.DiskWrite,
 		},
 	}
+	db.lock.RLock()
+	client.msg <- Message{
+		Home:    db.history.Home,
+		Chain:   db.history.Chain,
+		TxPool:  db.history.TxPool,
+		Network: db.history.Network,
+		Alien:   db.history.Alien,
+	}
+	db.lock.RUnlock()
 	// Start tracking the connection and drop at connection loss.
 	db.lock.Lock()
 	db.conns[id] = client
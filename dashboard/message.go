@@ -16,7 +16,12 @@
 
 package dashboard
 
-import "time"
+import (
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/p2p"
+)
 
 type Message struct {
 	General *GeneralMessage `json:"general,omitempty"`
@@ -26,6 +31,7 @@ type Message struct {
 	Network *NetworkMessage `json:"network,omitempty"`
 	System  *SystemMessage  `json:"system,omitempty"`
 	Logs    *LogsMessage    `json:"logs,omitempty"`
+	Alien   *AlienMessage   `json:"alien,omitempty"`
 }
 
 type ChartEntries []*ChartEntry
@@ -41,19 +47,79 @@ type GeneralMessage struct {
 }
 
 type HomeMessage struct {
-	/* TODO (kurkomisi) */
+	Head    *BlockInfo `json:"head,omitempty"`
+	Peers   int        `json:"peers"`
+	Pending int        `json:"pending"`
+	Queued  int        `json:"queued"`
+	Syncing bool       `json:"syncing"`
+	Mining  bool       `json:"mining"`
+}
+
+// BlockInfo summarizes a block imported into the local chain.
+type BlockInfo struct {
+	Number   uint64         `json:"number"`
+	Hash     common.Hash    `json:"hash"`
+	Time     uint64         `json:"time"`
+	Miner    common.Address `json:"miner"`
+	Txs      int            `json:"txs"`
+	GasUsed  uint64         `json:"gasUsed"`
+	GasLimit uint64         `json:"gasLimit"`
 }
 
 type ChainMessage struct {
-	/* TODO (kurkomisi) */
+	Blocks    []*BlockInfo `json:"blocks,omitempty"`
+	BlockTime ChartEntries `json:"blockTime,omitempty"`
+	GasUsed   ChartEntries `json:"gasUsed,omitempty"`
 }
 
 type TxPoolMessage struct {
-	/* TODO (kurkomisi) */
+	Pending ChartEntries `json:"pending,omitempty"`
+	Queued  ChartEntries `json:"queued,omitempty"`
 }
 
 type NetworkMessage struct {
-	/* TODO (kurkomisi) */
+	Peers     []*p2p.PeerInfo `json:"peers,omitempty"`
+	PeerCount ChartEntries    `json:"peerCount,omitempty"`
+}
+
+// AlienMessage is the alien consensus state as of the current head.
+type AlienMessage struct {
+	Number          uint64            `json:"number"`
+	LoopStartTime   uint64            `json:"loopStartTime"`
+	ConfirmedNumber uint64            `json:"confirmedNumber"`
+	ConfirmLag      uint64            `json:"confirmLag"`
+	Signers         []*AlienSigner    `json:"signers"`
+	Proposals       []*AlienProposal  `json:"proposals"`
+	SideChains      []*AlienSideChain `json:"sideChains"`
+}
+
+// AlienSigner is a signer of the current loop, in signer queue order.
+type AlienSigner struct {
+	Address  common.Address `json:"address"`
+	InTurn   bool           `json:"inturn"`
+	Punished uint64         `json:"punished"`
+	Stake    string         `json:"stake"`
+}
+
+// AlienProposal is an open proposal with the stake declared on it so far.
+type AlienProposal struct {
+	Hash     common.Hash    `json:"hash"`
+	Type     uint64         `json:"type"`
+	Proposer common.Address `json:"proposer"`
+	Received uint64         `json:"received"`
+	Deadline uint64         `json:"deadline"`
+	Yes      string         `json:"yes"`
+	No       string         `json:"no"`
+	Total    string         `json:"total"`
+	Progress float64        `json:"progress"` // Percentage of the total stake declared yes
+}
+
+// AlienSideChain is the confirmation progress of a side chain on the main chain.
+type AlienSideChain struct {
+	Hash          common.Hash `json:"hash"`
+	LastConfirmed uint64      `json:"lastConfirmed"`
+	MaxNumber     uint64      `json:"maxNumber"`
+	Lag           uint64      `json:"lag"`
 }
 
 type SystemMessage struct {
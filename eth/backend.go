@@ -24,6 +24,7 @@ import (
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/TTCECO/gttc/accounts"
 	"github.com/TTCECO/gttc/common"
@@ -47,9 +48,11 @@ import (
 	"github.com/TTCECO/gttc/miner"
 	"github.com/TTCECO/gttc/node"
 	"github.com/TTCECO/gttc/p2p"
+	"github.com/TTCECO/gttc/p2p/discover"
 	"github.com/TTCECO/gttc/params"
 	"github.com/TTCECO/gttc/rlp"
 	"github.com/TTCECO/gttc/rpc"
+	"github.com/TTCECO/gttc/signer/remote"
 )
 
 type LesServer interface {
@@ -89,6 +92,9 @@ type Ethereum struct {
 	gasPrice  *big.Int
 	etherbase common.Address
 
+	remoteSigner *remote.Signer // External signer sealing alien blocks, if any
+	voteRenewer  *voteRenewer   // Re-casts expiring alien votes of unlocked accounts, if enabled
+
 	networkId     uint64
 	netRPCService *ethapi.PublicNetAPI
 
@@ -139,6 +145,13 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
 		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks),
 	}
+	if engine, ok := eth.engine.(*alien.Alien); ok {
+		engine.SetMaxClockDrift(config.AlienMaxDrift)
+		engine.SetClockDriftRefuse(config.AlienDriftRefuse)
+		if err := SetupAlienCheckpoint(engine, chainConfig, genesisHash, config.AlienCheckpoint); err != nil {
+			return nil, err
+		}
+	}
 
 	log.Info("Initialising TTC protocol", "versions", ProtocolVersions, "network", config.NetworkId)
 
@@ -205,11 +218,20 @@ func makeExtraData(extra []byte) []byte {
 
 // CreateDB creates the chain database.
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Database, error) {
-	db, err := ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
+	var (
+		db  ethdb.Database
+		err error
+	)
+	if config.SyncMode == downloader.LightSync {
+		// Light clients only store headers, there is nothing worth freezing
+		db, err = ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
+	} else {
+		db, err = ctx.OpenDatabaseWithFreezer(name, config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, config.FreezerThreshold)
+	}
 	if err != nil {
 		return nil, err
 	}
-	if db, ok := db.(*ethdb.LDBDatabase); ok {
+	if db, ok := rawdb.KeyValueStore(db).(*ethdb.LDBDatabase); ok {
 		db.Meter("eth/db/chaindata/")
 	}
 	return db, nil
@@ -356,12 +378,24 @@ func (s *Ethereum) StartMining(local bool) error {
 		clique.Authorize(eb, wallet.SignHash)
 	}
 	if alien, ok := s.engine.(*alien.Alien); ok {
-		wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
-		if wallet == nil || err != nil {
-			log.Error("Etherbase account unavailable locally", "err", err)
-			return fmt.Errorf("signer missing: %v", err)
+		if err := s.checkClockDrift(alien); err != nil {
+			return err
+		}
+		if s.config.ExternalSigner != "" {
+			signer, err := s.externalSigner()
+			if err != nil {
+				log.Error("External signer unavailable", "url", s.config.ExternalSigner, "err", err)
+				return fmt.Errorf("signer missing: %v", err)
+			}
+			alien.AuthorizeRemote(eb, signer.SignHeader, signer.SignTx)
+		} else {
+			wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
+			if wallet == nil || err != nil {
+				log.Error("Etherbase account unavailable locally", "err", err)
+				return fmt.Errorf("signer missing: %v", err)
+			}
+			alien.Authorize(eb, wallet.SignHash, wallet.SignTx)
 		}
-		alien.Authorize(eb, wallet.SignHash, wallet.SignTx)
 	}
 	if local {
 		// If local (CPU) mining is started, we can disable the transaction rejection
@@ -374,6 +408,48 @@ func (s *Ethereum) StartMining(local bool) error {
 	return nil
 }
 
+// checkClockDrift checks the local clock against the peer blocks and, if enabled,
+// against NTP before alien mining starts. A drifting clock makes the signer miss
+// its slots, so mining is refused if configured, or a warning is logged.
+func (s *Ethereum) checkClockDrift(engine *alien.Alien) error {
+	if s.config.AlienDriftNTP {
+		if drift, err := discover.SNTPDrift(); err != nil {
+			log.Warn("Failed to check clock drift against NTP", "err", err)
+		} else {
+			engine.RecordNTPDrift(drift)
+		}
+	}
+	if err := engine.CheckClockDrift(); err != nil {
+		if s.config.AlienDriftRefuse {
+			log.Error("Refusing to mine with a drifting clock", "err", err)
+			return err
+		}
+		log.Warn(fmt.Sprintf("Mining with a drifting clock, alien slots may be missed: %v", err))
+		log.Warn("Please enable network time synchronisation in system settings.")
+	}
+	return nil
+}
+
+// externalSigner connects to the external signer sealing the alien blocks, or
+// returns the existing connection. Signing requests time out after one block
+// period, since a seal arriving later is useless anyway.
+func (s *Ethereum) externalSigner() (*remote.Signer, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.remoteSigner != nil {
+		return s.remoteSigner, nil
+	}
+	timeout := time.Duration(s.chainConfig.Alien.Period) * time.Second
+	signer, err := remote.Dial(s.config.ExternalSigner, timeout)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Connected to external signer", "url", s.config.ExternalSigner, "timeout", signer.Timeout())
+	s.remoteSigner = signer
+	return signer, nil
+}
+
 func (s *Ethereum) StopMining()         { s.miner.Stop() }
 func (s *Ethereum) IsMining() bool      { return s.miner.Mining() }
 func (s *Ethereum) Miner() *miner.Miner { return s.miner }
@@ -420,12 +496,19 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
+	if engine, ok := s.engine.(*alien.Alien); ok && s.config.AlienVoteRenew > 0 {
+		s.voteRenewer = newVoteRenewer(s, engine, s.config.AlienVoteRenew)
+		s.voteRenewer.start()
+	}
 	return nil
 }
 
 // Stop implements node.Service, terminating all internal goroutines used by the
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
+	if s.voteRenewer != nil {
+		s.voteRenewer.stop()
+	}
 	s.bloomIndexer.Close()
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
@@ -435,6 +518,9 @@ func (s *Ethereum) Stop() error {
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.eventMux.Stop()
+	if s.remoteSigner != nil {
+		s.remoteSigner.Close()
+	}
 
 	s.chainDb.Close()
 	close(s.shutdownChan)
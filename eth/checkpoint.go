@@ -0,0 +1,92 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/log"
+	"github.com/TTCECO/gttc/params"
+)
+
+const (
+	checkpointFetchTimeout = 30 * time.Second // Time allowance to download an alien checkpoint
+	checkpointMaxSize      = 1024 * 1024      // Size limit of a downloaded alien checkpoint
+)
+
+// SetupAlienCheckpoint sets the trusted checkpoint of an alien engine: the one
+// shipped for the genesis, unless a newer one signed by the checkpoint keys of
+// the chain is found at the given file path or URL.
+func SetupAlienCheckpoint(engine *alien.Alien, config *params.ChainConfig, genesis common.Hash, source string) error {
+	checkpoint := params.TrustedAlienCheckpoints[genesis]
+	if source != "" {
+		loaded, err := loadAlienCheckpoint(source)
+		if err != nil {
+			return fmt.Errorf("failed to load alien checkpoint %s: %v", source, err)
+		}
+		if _, err := alien.VerifyCheckpoint(config.Alien, loaded); err != nil {
+			return fmt.Errorf("untrusted alien checkpoint %s: %v", source, err)
+		}
+		if checkpoint == nil || loaded.Number > checkpoint.Number {
+			checkpoint = loaded
+		}
+	}
+	if checkpoint != nil {
+		engine.SetCheckpoint(checkpoint)
+		log.Info("Using trusted alien checkpoint", "number", checkpoint.Number, "hash", checkpoint.Hash)
+	}
+	return nil
+}
+
+// loadAlienCheckpoint reads an alien checkpoint from a file, or downloads it if
+// the source is an HTTP URL.
+func loadAlienCheckpoint(source string) (*params.AlienCheckpoint, error) {
+	var (
+		blob []byte
+		err  error
+	)
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: checkpointFetchTimeout}
+		res, err := client.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s", res.Status)
+		}
+		if blob, err = ioutil.ReadAll(io.LimitReader(res.Body, checkpointMaxSize)); err != nil {
+			return nil, err
+		}
+	} else if blob, err = ioutil.ReadFile(source); err != nil {
+		return nil, err
+	}
+	checkpoint := new(params.AlienCheckpoint)
+	if err := json.Unmarshal(blob, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/params"
+)
+
+// Tests that alien checkpoints are loaded from files and URLs, and only adopted
+// if signed by the checkpoint keys of the chain.
+func TestSetupAlienCheckpoint(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	checkpoint := &params.AlienCheckpoint{Number: 100, Hash: common.HexToHash("0x01"), SnapshotHash: common.HexToHash("0x02")}
+	sig, _ := crypto.Sign(checkpoint.SigHash().Bytes(), key)
+	checkpoint.Signatures = []hexutil.Bytes{sig}
+	blob, _ := json.Marshal(checkpoint)
+
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "checkpoint.json")
+	if err := ioutil.WriteFile(file, blob, 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(blob)
+	}))
+	defer server.Close()
+
+	trusted := &params.ChainConfig{Alien: &params.AlienConfig{
+		MinVoterBalance:   big.NewInt(100),
+		CheckpointSigners: []common.Address{crypto.PubkeyToAddress(key.PublicKey)},
+	}}
+	untrusted := &params.ChainConfig{Alien: &params.AlienConfig{
+		MinVoterBalance:   big.NewInt(100),
+		CheckpointSigners: []common.Address{{0x01}},
+	}}
+
+	for _, source := range []string{file, server.URL} {
+		engine := alien.New(trusted.Alien, ethdb.NewMemDatabase())
+		if err := SetupAlienCheckpoint(engine, trusted, common.Hash{}, source); err != nil {
+			t.Fatalf("%s: failed to set up checkpoint: %v", source, err)
+		}
+		if number, hash := engine.Checkpoint(); number != checkpoint.Number || hash != checkpoint.Hash {
+			t.Errorf("%s: checkpoint mismatch: have #%d %x, want #%d %x", source, number, hash, checkpoint.Number, checkpoint.Hash)
+		}
+		engine = alien.New(untrusted.Alien, ethdb.NewMemDatabase())
+		if err := SetupAlienCheckpoint(engine, untrusted, common.Hash{}, source); err == nil {
+			t.Errorf("%s: untrusted checkpoint accepted", source)
+		}
+	}
+	// A checkpoint failing to load aborts the setup
+	for _, source := range []string{filepath.Join(dir, "missing.json"), server.URL + "/missing"} {
+		engine := alien.New(trusted.Alien, ethdb.NewMemDatabase())
+		if err := SetupAlienCheckpoint(engine, trusted, common.Hash{}, source); err == nil {
+			t.Errorf("%s: missing checkpoint accepted", source)
+		}
+	}
+}
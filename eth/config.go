@@ -28,6 +28,7 @@ import (
 	"github.com/TTCECO/gttc/common/hexutil"
 	"github.com/TTCECO/gttc/consensus/ethash"
 	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/rawdb"
 	"github.com/TTCECO/gttc/eth/downloader"
 	"github.com/TTCECO/gttc/eth/gasprice"
 	"github.com/TTCECO/gttc/params"
@@ -43,12 +44,13 @@ var DefaultConfig = Config{
 		DatasetsInMem:  1,
 		DatasetsOnDisk: 2,
 	},
-	NetworkId:     1,
-	LightPeers:    100,
-	DatabaseCache: 768,
-	TrieCache:     256,
-	TrieTimeout:   5 * time.Minute,
-	GasPrice:      big.NewInt(18 * params.Shannon),
+	NetworkId:        1,
+	LightPeers:       100,
+	DatabaseCache:    768,
+	FreezerThreshold: rawdb.DefaultFreezerThreshold,
+	TrieCache:        256,
+	TrieTimeout:      5 * time.Minute,
+	GasPrice:         big.NewInt(18 * params.Shannon),
 
 	TxPool: core.DefaultTxPoolConfig,
 	GPO: gasprice.Config{
@@ -91,6 +93,8 @@ type Config struct {
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
+	DatabaseFreezer    string `toml:",omitempty"` // Directory of the ancient store, defaults to chaindata/ancient
+	FreezerThreshold   uint64 // Number of recent blocks kept in the key-value store, 0 disables freezing
 	TrieCache          int
 	TrieTimeout        time.Duration
 
@@ -100,6 +104,22 @@ type Config struct {
 	ExtraData    []byte         `toml:",omitempty"`
 	GasPrice     *big.Int
 
+	// External signer (clef IPC path or HTTP URL) sealing alien blocks
+	ExternalSigner string `toml:",omitempty"`
+
+	// Blocks before expiry to re-cast the alien votes of unlocked accounts (0 = off)
+	AlienVoteRenew uint64 `toml:",omitempty"`
+
+	// Alien clock drift guard: offset of the local clock beyond which the alien
+	// signer drifts (0 = half a period), whether to refuse mining while it
+	// drifts instead of warning, and whether to also check the offset against NTP
+	AlienMaxDrift    time.Duration `toml:",omitempty"`
+	AlienDriftRefuse bool          `toml:",omitempty"`
+	AlienDriftNTP    bool          `toml:",omitempty"`
+
+	// Alien checkpoint file path or URL, signed by the checkpoint keys of the chain
+	AlienCheckpoint string `toml:",omitempty"`
+
 	// Ethash options
 	Ethash ethash.Config
 
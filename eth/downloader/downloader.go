@@ -27,6 +27,8 @@ import (
 
 	ethereum "github.com/TTCECO/gttc"
 	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/core"
 	"github.com/TTCECO/gttc/core/rawdb"
 	"github.com/TTCECO/gttc/core/types"
 	"github.com/TTCECO/gttc/ethdb"
@@ -88,6 +90,8 @@ var (
 	errCancelStateFetch        = errors.New("state data download canceled (requested)")
 	errCancelHeaderProcessing  = errors.New("header processing canceled (requested)")
 	errCancelContentProcessing = errors.New("content processing canceled (requested)")
+	errCancelSnapshotFetch     = errors.New("consensus snapshot download canceled (requested)")
+	errCheckpointMismatch      = errors.New("peer chain conflicts with the trusted checkpoint")
 	errNoSyncActive            = errors.New("no sync active")
 	errTooOld                  = errors.New("peer doesn't speak recent enough protocol version (need version >= 62)")
 )
@@ -112,6 +116,11 @@ type Downloader struct {
 	lightchain LightChain
 	blockchain BlockChain
 
+	snapshotChain  consensus.ChainReader    // Chain handed to the snapshot syncer
+	snapshotSyncer consensus.SnapshotSyncer // Consensus engine able to import snapshots (nil = not supported)
+
+	checkpointSyncer consensus.CheckpointSyncer // Consensus engine holding a trusted checkpoint (nil = not supported)
+
 	// Callbacks
 	dropPeer peerDropFn // Drops a peer for misbehaving
 
@@ -134,6 +143,8 @@ type Downloader struct {
 	trackStateReq  chan *stateReq
 	stateCh        chan dataPack // [eth/63] Channel receiving inbound node state data
 
+	snapshotCh chan dataPack // [eth/64] Channel receiving inbound consensus snapshots
+
 	// Cancellation and termination
 	cancelPeer string         // Identifier of the peer currently being used as the master (cancel on drop)
 	cancelCh   chan struct{}  // Channel to cancel mid-flight syncs
@@ -197,6 +208,17 @@ type BlockChain interface {
 	InsertReceiptChain(types.Blocks, []types.Receipts) (int, error)
 }
 
+// engineChain is implemented by blockchains exposing their consensus engine.
+type engineChain interface {
+	consensus.ChainReader
+	Engine() consensus.Engine
+}
+
+// headerChainReader is implemented by light chains exposing their header chain.
+type headerChainReader interface {
+	HeaderChain() *core.HeaderChain
+}
+
 // New creates a new downloader to fetch hashes and blocks from remote peers.
 func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
 	if lightchain == nil {
@@ -227,6 +249,21 @@ func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockC
 			processed: rawdb.ReadFastTrieProgress(stateDb),
 		},
 		trackStateReq: make(chan *stateReq),
+		snapshotCh:    make(chan dataPack, 1),
+	}
+	var reader engineChain
+	if chain, ok := chain.(engineChain); ok {
+		reader = chain
+	} else if chain, ok := lightchain.(headerChainReader); ok {
+		reader = chain.HeaderChain()
+	}
+	if reader != nil {
+		if syncer, ok := reader.Engine().(consensus.SnapshotSyncer); ok {
+			dl.snapshotChain, dl.snapshotSyncer = reader, syncer
+		}
+		if syncer, ok := reader.Engine().(consensus.CheckpointSyncer); ok {
+			dl.snapshotChain, dl.checkpointSyncer = reader, syncer
+		}
 	}
 	go dl.qosTuner()
 	go dl.stateFetcher()
@@ -321,7 +358,7 @@ func (d *Downloader) Synchronise(id string, head common.Hash, td *big.Int, mode
 
 	case errTimeout, errBadPeer, errStallingPeer,
 		errEmptyHeaderSet, errPeersUnavailable, errTooOld,
-		errInvalidAncestor, errInvalidChain:
+		errInvalidAncestor, errInvalidChain, errCheckpointMismatch:
 		log.Warn("Synchronisation failed, dropping peer", "peer", id, "err", err)
 		if d.dropPeer == nil {
 			// The dropPeer method is nil when `--copydb` is used for a local copy.
@@ -427,6 +464,13 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	}
 	height := latest.Number.Uint64()
 
+	// Refuse peers conflicting with the trusted checkpoint, and start from it if
+	// the local chain is still behind
+	if err := d.syncCheckpoint(p, height); err == errCancelSnapshotFetch || err == errCheckpointMismatch {
+		return err
+	} else if err != nil {
+		p.log.Debug("Checkpoint snapshot unavailable, rebuilding from headers", "err", err)
+	}
 	origin, err := d.findAncestor(p, height)
 	if err != nil {
 		return err
@@ -454,6 +498,18 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	if d.mode == FastSync && pivot != 0 {
 		d.committed = 0
 	}
+	// Retrieve the consensus snapshot at the pivot (or the same depth for light
+	// sync) if the engine supports it, so that header verification doesn't have
+	// to replay the chain from genesis
+	if d.mode != FullSync && height > uint64(fsMinFullBlocks) {
+		if snapPivot := height - uint64(fsMinFullBlocks); d.lightchain.CurrentHeader().Number.Uint64() < snapPivot {
+			if err := d.syncSnapshot(p, snapPivot, height); err == errCancelSnapshotFetch {
+				return err
+			} else if err != nil {
+				p.log.Debug("Consensus snapshot unavailable, rebuilding from headers", "pivot", snapPivot, "err", err)
+			}
+		}
+	}
 	// Initiate the sync using a concurrent header and content retrieval algorithm
 	d.queue.Prepare(origin+1, d.mode)
 	if d.syncInitHook != nil {
@@ -1542,6 +1598,11 @@ func (d *Downloader) DeliverNodeData(id string, data [][]byte) (err error) {
 	return d.deliver(id, d.stateCh, &statePack{id, data}, stateInMeter, stateDropMeter)
 }
 
+// DeliverSnapshot injects a consensus snapshot received from a remote node.
+func (d *Downloader) DeliverSnapshot(id string, snapshot []byte) (err error) {
+	return d.deliver(id, d.snapshotCh, &snapshotPack{id, snapshot}, snapshotInMeter, snapshotDropMeter)
+}
+
 // deliver injects a new batch of data received from a remote node.
 func (d *Downloader) deliver(id string, destCh chan dataPack, packet dataPack, inMeter, dropMeter metrics.Meter) (err error) {
 	// Update the delivery metrics for both good and failed deliveries
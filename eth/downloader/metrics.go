@@ -40,4 +40,7 @@ var (
 
 	stateInMeter   = metrics.NewRegisteredMeter("eth/downloader/states/in", nil)
 	stateDropMeter = metrics.NewRegisteredMeter("eth/downloader/states/drop", nil)
+
+	snapshotInMeter   = metrics.NewRegisteredMeter("eth/downloader/snapshots/in", nil)
+	snapshotDropMeter = metrics.NewRegisteredMeter("eth/downloader/snapshots/drop", nil)
 )
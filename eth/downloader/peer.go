@@ -111,6 +111,12 @@ func (w *lightPeerWrapper) RequestReceipts([]common.Hash) error {
 func (w *lightPeerWrapper) RequestNodeData([]common.Hash) error {
 	panic("RequestNodeData not supported in light client mode sync")
 }
+func (w *lightPeerWrapper) RequestSnapshot(hash common.Hash, number uint64) error {
+	if peer, ok := w.peer.(snapshotPeer); ok {
+		return peer.RequestSnapshot(hash, number)
+	}
+	return errSnapshotUnsupported
+}
 
 // newPeerConnection creates a new downloader peer.
 func newPeerConnection(id string, version int, peer Peer, logger log.Logger) *peerConnection {
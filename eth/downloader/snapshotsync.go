@@ -0,0 +1,179 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"errors"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/log"
+)
+
+// errSnapshotUnsupported is returned if the sync peer can't serve consensus snapshots.
+var errSnapshotUnsupported = errors.New("peer doesn't serve consensus snapshots")
+
+// snapshotPeer is implemented by peers able to serve consensus snapshots.
+type snapshotPeer interface {
+	RequestSnapshot(hash common.Hash, number uint64) error
+}
+
+// syncSnapshot retrieves the consensus snapshot at the fast sync pivot from the
+// given peer and hands it to the consensus engine, along with the headers after
+// the pivot, which carry the commitment to it and confirm it. Any failure leaves
+// the engine rebuilding its state from the downloaded headers, as without
+// snapshot sync.
+func (d *Downloader) syncSnapshot(p *peerConnection, pivot uint64, height uint64) error {
+	if d.snapshotSyncer == nil {
+		return nil
+	}
+	if _, ok := p.peer.(snapshotPeer); !ok {
+		return errSnapshotUnsupported
+	}
+	p.log.Debug("Retrieving consensus snapshot", "pivot", pivot)
+
+	// Fetch the headers carrying the commitment, then the snapshot it commits to
+	headers, err := d.fetchSnapshotHeaders(p, pivot+1, int(height-pivot))
+	if err != nil {
+		return err
+	}
+	snapshot, err := d.fetchSnapshot(p, headers[0].ParentHash, pivot)
+	if err != nil {
+		return err
+	}
+	if err := d.snapshotSyncer.ImportSnapshot(d.snapshotChain, headers, snapshot); err != nil {
+		p.log.Warn("Rejected consensus snapshot", "pivot", pivot, "err", err)
+		return errBadPeer
+	}
+	p.log.Info("Imported consensus snapshot", "number", pivot, "hash", headers[0].ParentHash)
+	return nil
+}
+
+// syncCheckpoint checks the chain of the given peer against the trusted
+// checkpoint of the consensus engine. Unless doing a full sync, if the local
+// chain hasn't reached the checkpoint yet, the consensus snapshot at it is also
+// retrieved, so that header verification starts from the checkpoint.
+func (d *Downloader) syncCheckpoint(p *peerConnection, height uint64) error {
+	if d.checkpointSyncer == nil {
+		return nil
+	}
+	number, hash := d.checkpointSyncer.Checkpoint()
+	if number == 0 || number > height {
+		return nil
+	}
+	headers, err := d.fetchSnapshotHeaders(p, number, 1)
+	if err != nil {
+		return err
+	}
+	if header := headers[0]; header.Hash() != hash {
+		p.log.Warn("Peer chain conflicts with the trusted checkpoint", "number", number, "have", header.Hash(), "want", hash)
+		return errCheckpointMismatch
+	}
+	if d.mode == FullSync || d.lightchain.CurrentHeader().Number.Uint64() >= number {
+		return nil
+	}
+	p.log.Debug("Retrieving checkpoint snapshot", "number", number, "hash", hash)
+
+	snapshot, err := d.fetchSnapshot(p, hash, number)
+	if err != nil {
+		return err
+	}
+	if err := d.checkpointSyncer.ImportCheckpoint(d.snapshotChain, snapshot); err != nil {
+		p.log.Warn("Rejected checkpoint snapshot", "number", number, "err", err)
+		return errBadPeer
+	}
+	return nil
+}
+
+// fetchSnapshot retrieves the consensus snapshot at the given block from the
+// given peer.
+func (d *Downloader) fetchSnapshot(p *peerConnection, hash common.Hash, number uint64) ([]byte, error) {
+	peer, ok := p.peer.(snapshotPeer)
+	if !ok {
+		return nil, errSnapshotUnsupported
+	}
+	if err := peer.RequestSnapshot(hash, number); err != nil {
+		return nil, err
+	}
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return nil, errCancelSnapshotFetch
+
+		case packet := <-d.snapshotCh:
+			// Discard anything not from the origin peer
+			if packet.PeerId() != p.id {
+				log.Debug("Received snapshot from incorrect peer", "peer", packet.PeerId())
+				break
+			}
+			snapshot := packet.(*snapshotPack).snapshot
+			if len(snapshot) == 0 {
+				return nil, errSnapshotUnsupported
+			}
+			return snapshot, nil
+
+		case <-timeout:
+			p.log.Debug("Waiting for consensus snapshot timed out", "elapsed", ttl)
+			return nil, errTimeout
+		}
+	}
+}
+
+// fetchSnapshotHeaders retrieves a batch of consecutive headers by number from
+// the given peer.
+func (d *Downloader) fetchSnapshotHeaders(p *peerConnection, from uint64, count int) ([]*types.Header, error) {
+	go p.peer.RequestHeadersByNumber(from, count, 0, false)
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return nil, errCancelSnapshotFetch
+
+		case packet := <-d.headerCh:
+			// Discard anything not from the origin peer
+			if packet.PeerId() != p.id {
+				log.Debug("Received headers from incorrect peer", "peer", packet.PeerId())
+				break
+			}
+			headers := packet.(*headerPack).headers
+			if len(headers) != count {
+				p.log.Debug("Invalid snapshot headers delivered", "headers", len(headers), "want", count)
+				return nil, errBadPeer
+			}
+			for i, header := range headers {
+				if header.Number.Uint64() != from+uint64(i) {
+					p.log.Debug("Invalid snapshot header delivered", "number", header.Number, "want", from+uint64(i))
+					return nil, errBadPeer
+				}
+			}
+			return headers, nil
+
+		case <-timeout:
+			p.log.Debug("Waiting for snapshot headers timed out", "elapsed", ttl)
+			return nil, errTimeout
+
+		case <-d.bodyCh:
+		case <-d.receiptCh:
+			// Out of bounds delivery, ignore
+		}
+	}
+}
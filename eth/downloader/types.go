@@ -77,3 +77,13 @@ type statePack struct {
 func (p *statePack) PeerId() string { return p.peerId }
 func (p *statePack) Items() int     { return len(p.states) }
 func (p *statePack) Stats() string  { return fmt.Sprintf("%d", len(p.states)) }
+
+// snapshotPack is a consensus snapshot returned by a peer.
+type snapshotPack struct {
+	peerId   string
+	snapshot []byte
+}
+
+func (p *snapshotPack) PeerId() string { return p.peerId }
+func (p *snapshotPack) Items() int     { return 1 }
+func (p *snapshotPack) Stats() string  { return fmt.Sprintf("%d", len(p.snapshot)) }
@@ -4,6 +4,7 @@ package eth
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/common/hexutil"
@@ -25,10 +26,18 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		SkipBcVersionCheck      bool `toml:"-"`
 		DatabaseHandles         int  `toml:"-"`
 		DatabaseCache           int
+		DatabaseFreezer         string `toml:",omitempty"`
+		FreezerThreshold        uint64
 		Etherbase               common.Address `toml:",omitempty"`
 		MinerThreads            int            `toml:",omitempty"`
 		ExtraData               hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
+		ExternalSigner          string        `toml:",omitempty"`
+		AlienVoteRenew          uint64        `toml:",omitempty"`
+		AlienMaxDrift           time.Duration `toml:",omitempty"`
+		AlienDriftRefuse        bool          `toml:",omitempty"`
+		AlienDriftNTP           bool          `toml:",omitempty"`
+		AlienCheckpoint         string        `toml:",omitempty"`
 		Ethash                  ethash.Config
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
@@ -44,10 +53,18 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
+	enc.DatabaseFreezer = c.DatabaseFreezer
+	enc.FreezerThreshold = c.FreezerThreshold
 	enc.Etherbase = c.Etherbase
 	enc.MinerThreads = c.MinerThreads
 	enc.ExtraData = c.ExtraData
 	enc.GasPrice = c.GasPrice
+	enc.ExternalSigner = c.ExternalSigner
+	enc.AlienVoteRenew = c.AlienVoteRenew
+	enc.AlienMaxDrift = c.AlienMaxDrift
+	enc.AlienDriftRefuse = c.AlienDriftRefuse
+	enc.AlienDriftNTP = c.AlienDriftNTP
+	enc.AlienCheckpoint = c.AlienCheckpoint
 	enc.Ethash = c.Ethash
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
@@ -66,10 +83,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		SkipBcVersionCheck      *bool `toml:"-"`
 		DatabaseHandles         *int  `toml:"-"`
 		DatabaseCache           *int
+		DatabaseFreezer         *string `toml:",omitempty"`
+		FreezerThreshold        *uint64
 		Etherbase               *common.Address `toml:",omitempty"`
 		MinerThreads            *int            `toml:",omitempty"`
 		ExtraData               *hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
+		ExternalSigner          *string        `toml:",omitempty"`
+		AlienVoteRenew          *uint64        `toml:",omitempty"`
+		AlienMaxDrift           *time.Duration `toml:",omitempty"`
+		AlienDriftRefuse        *bool          `toml:",omitempty"`
+		AlienDriftNTP           *bool          `toml:",omitempty"`
+		AlienCheckpoint         *string        `toml:",omitempty"`
 		Ethash                  *ethash.Config
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
@@ -104,6 +129,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseCache != nil {
 		c.DatabaseCache = *dec.DatabaseCache
 	}
+	if dec.DatabaseFreezer != nil {
+		c.DatabaseFreezer = *dec.DatabaseFreezer
+	}
+	if dec.FreezerThreshold != nil {
+		c.FreezerThreshold = *dec.FreezerThreshold
+	}
 	if dec.Etherbase != nil {
 		c.Etherbase = *dec.Etherbase
 	}
@@ -116,6 +147,24 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.GasPrice != nil {
 		c.GasPrice = dec.GasPrice
 	}
+	if dec.ExternalSigner != nil {
+		c.ExternalSigner = *dec.ExternalSigner
+	}
+	if dec.AlienVoteRenew != nil {
+		c.AlienVoteRenew = *dec.AlienVoteRenew
+	}
+	if dec.AlienMaxDrift != nil {
+		c.AlienMaxDrift = *dec.AlienMaxDrift
+	}
+	if dec.AlienDriftRefuse != nil {
+		c.AlienDriftRefuse = *dec.AlienDriftRefuse
+	}
+	if dec.AlienDriftNTP != nil {
+		c.AlienDriftNTP = *dec.AlienDriftNTP
+	}
+	if dec.AlienCheckpoint != nil {
+		c.AlienCheckpoint = *dec.AlienCheckpoint
+	}
 	if dec.Ethash != nil {
 		c.Ethash = *dec.Ethash
 	}
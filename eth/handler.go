@@ -549,6 +549,34 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			log.Debug("Failed to deliver receipts", "err", err)
 		}
 
+	case p.version >= eth64 && msg.Code == GetSnapshotMsg:
+		// Decode the retrieval message
+		var query getSnapshotData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "%v: %v", msg, err)
+		}
+		// Serve the snapshot if the engine supports it, an empty one otherwise
+		var snapshot []byte
+		if syncer, ok := pm.blockchain.Engine().(consensus.SnapshotSyncer); ok {
+			if blob, err := syncer.ExportSnapshot(pm.blockchain, query.Hash, query.Number); err == nil {
+				snapshot = blob
+			} else {
+				p.Log().Debug("Failed to export consensus snapshot", "number", query.Number, "err", err)
+			}
+		}
+		return p.SendSnapshot(query.Hash, snapshot)
+
+	case p.version >= eth64 && msg.Code == SnapshotMsg:
+		// A consensus snapshot arrived to one of our previous requests
+		var data snapshotData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		// Deliver it to the downloader
+		if err := pm.downloader.DeliverSnapshot(p.id, data.Snapshot); err != nil {
+			log.Debug("Failed to deliver consensus snapshot", "err", err)
+		}
+
 	case msg.Code == NewBlockHashesMsg:
 		var announces newBlockHashesData
 		if err := msg.Decode(&announces); err != nil {
@@ -34,6 +34,8 @@ var (
 	errClosed            = errors.New("peer set is closed")
 	errAlreadyRegistered = errors.New("peer is already registered")
 	errNotRegistered     = errors.New("peer is not registered")
+
+	errSnapshotUnsupported = errors.New("peer doesn't serve consensus snapshots")
 )
 
 const (
@@ -286,6 +288,11 @@ func (p *peer) SendReceiptsRLP(receipts []rlp.RawValue) error {
 	return p2p.Send(p.rw, ReceiptsMsg, receipts)
 }
 
+// SendSnapshot sends the encoded consensus snapshot taken at the given block.
+func (p *peer) SendSnapshot(hash common.Hash, snapshot []byte) error {
+	return p2p.Send(p.rw, SnapshotMsg, &snapshotData{Hash: hash, Snapshot: snapshot})
+}
+
 // RequestOneHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *peer) RequestOneHeader(hash common.Hash) error {
@@ -327,6 +334,15 @@ func (p *peer) RequestReceipts(hashes []common.Hash) error {
 	return p2p.Send(p.rw, GetReceiptsMsg, hashes)
 }
 
+// RequestSnapshot fetches the consensus snapshot taken at the given block.
+func (p *peer) RequestSnapshot(hash common.Hash, number uint64) error {
+	if p.version < eth64 {
+		return errSnapshotUnsupported
+	}
+	p.Log().Debug("Fetching consensus snapshot", "number", number, "hash", hash)
+	return p2p.Send(p.rw, GetSnapshotMsg, &getSnapshotData{Hash: hash, Number: number})
+}
+
 // Handshake executes the eth protocol handshake, negotiating version number,
 // network IDs, difficulties, head and genesis blocks.
 func (p *peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis common.Hash) error {
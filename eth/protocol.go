@@ -32,16 +32,17 @@ import (
 const (
 	eth62 = 62
 	eth63 = 63
+	eth64 = 64
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "eth"
 
 // ProtocolVersions are the upported versions of the eth protocol (first is primary).
-var ProtocolVersions = []uint{eth63, eth62}
+var ProtocolVersions = []uint{eth64, eth63, eth62}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{17, 8}
+var ProtocolLengths = []uint64{19, 17, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -62,6 +63,10 @@ const (
 	NodeDataMsg    = 0x0e
 	GetReceiptsMsg = 0x0f
 	ReceiptsMsg    = 0x10
+
+	// Protocol messages belonging to eth/64
+	GetSnapshotMsg = 0x11
+	SnapshotMsg    = 0x12
 )
 
 type errCode int
@@ -181,3 +186,16 @@ type blockBody struct {
 
 // blockBodiesData is the network packet for block content distribution.
 type blockBodiesData []*blockBody
+
+// getSnapshotData represents a consensus snapshot query.
+type getSnapshotData struct {
+	Hash   common.Hash // Hash of the block the snapshot was taken at
+	Number uint64      // Number of the block the snapshot was taken at
+}
+
+// snapshotData is the network packet for consensus snapshot distribution. An
+// empty snapshot means the remote peer couldn't serve the request.
+type snapshotData struct {
+	Hash     common.Hash
+	Snapshot []byte
+}
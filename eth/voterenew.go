@@ -0,0 +1,148 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"sync"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/accounts/keystore"
+	"github.com/TTCECO/gttc/alienclient"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/log"
+)
+
+const (
+	// voteRenewGas is the gas limit of the re-cast vote transactions, comfortably
+	// above their intrinsic gas.
+	voteRenewGas = 50000
+
+	// renewHeadChanSize is the size of channel listening to ChainHeadEvent.
+	renewHeadChanSize = 10
+)
+
+// voteRenewer re-casts the alien votes of the unlocked keystore accounts to the
+// same candidate shortly before they expire, renewing them for another epoch.
+type voteRenewer struct {
+	eth    *Ethereum
+	alien  *alien.Alien
+	margin uint64 // Number of blocks before expiry to re-cast a vote at
+
+	renewed map[common.Address]uint64 // Block number of the last vote re-cast per voter
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newVoteRenewer creates a vote renewer re-casting votes the given number of
+// blocks before they expire.
+func newVoteRenewer(eth *Ethereum, engine *alien.Alien, margin uint64) *voteRenewer {
+	return &voteRenewer{
+		eth:     eth,
+		alien:   engine,
+		margin:  margin,
+		renewed: make(map[common.Address]uint64),
+		quit:    make(chan struct{}),
+	}
+}
+
+// start launches the renewer following the chain head.
+func (r *voteRenewer) start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// stop terminates the renewer and waits for it to return.
+func (r *voteRenewer) stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *voteRenewer) loop() {
+	defer r.wg.Done()
+
+	heads := make(chan core.ChainHeadEvent, renewHeadChanSize)
+	sub := r.eth.blockchain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-heads:
+			r.renew(ev.Block.Header())
+		case <-sub.Err():
+			return
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// renew re-casts the votes of the keystore accounts expiring within the margin
+// after the given head. Locked accounts are skipped until they are unlocked.
+func (r *voteRenewer) renew(head *types.Header) {
+	backends := r.eth.accountManager.Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		return
+	}
+	ks := backends[0].(*keystore.KeyStore)
+
+	owned := ks.Accounts()
+	if len(owned) == 0 {
+		return
+	}
+	// Filter all the accounts against the same snapshot of the head
+	snap, err := r.alien.Snapshot(r.eth.blockchain, head)
+	if err != nil {
+		log.Warn("Failed to retrieve snapshot for vote renewal", "number", head.Number, "err", err)
+		return
+	}
+	for _, account := range owned {
+		for _, vote := range snap.ExpiringVotes(account.Address, r.margin) {
+			if vote.Voter != account.Address || r.renewed[vote.Voter] == vote.VotedNumber {
+				continue
+			}
+			if err := r.recast(ks, account, vote); err != nil {
+				if err == keystore.ErrLocked {
+					log.Debug("Skipping vote renewal of locked account", "voter", vote.Voter)
+				} else {
+					log.Warn("Failed to renew expiring vote", "voter", vote.Voter, "candidate", vote.Candidate, "err", err)
+				}
+				continue
+			}
+			r.renewed[vote.Voter] = vote.VotedNumber
+		}
+	}
+}
+
+// recast signs a vote for the same candidate with the voter's account and
+// submits it to the transaction pool.
+func (r *voteRenewer) recast(ks *keystore.KeyStore, account accounts.Account, vote *alien.ExpiringVote) error {
+	pool := r.eth.txPool
+	tx := alienclient.NewVoteTx(pool.State().GetNonce(vote.Voter), vote.Candidate, voteRenewGas, pool.GasPrice())
+	signed, err := ks.SignTx(account, tx, r.eth.chainConfig.ChainId)
+	if err != nil {
+		return err
+	}
+	if err := pool.AddLocal(signed); err != nil {
+		return err
+	}
+	log.Info("Renewed expiring vote", "voter", vote.Voter, "candidate", vote.Candidate, "expiry", vote.ExpiryNumber, "tx", signed.Hash())
+	return nil
+}
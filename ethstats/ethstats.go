@@ -33,6 +33,7 @@ import (
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/common/mclock"
 	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/consensus/alien"
 	"github.com/TTCECO/gttc/core"
 	"github.com/TTCECO/gttc/core/types"
 	"github.com/TTCECO/gttc/eth"
@@ -468,19 +469,29 @@ func (s *Service) reportLatency(conn *websocket.Conn) error {
 
 // blockStats is the information to report about individual blocks.
 type blockStats struct {
-	Number     *big.Int       `json:"number"`
-	Hash       common.Hash    `json:"hash"`
-	ParentHash common.Hash    `json:"parentHash"`
-	Timestamp  *big.Int       `json:"timestamp"`
-	Miner      common.Address `json:"miner"`
-	GasUsed    uint64         `json:"gasUsed"`
-	GasLimit   uint64         `json:"gasLimit"`
-	Diff       string         `json:"difficulty"`
-	TotalDiff  string         `json:"totalDifficulty"`
-	Txs        []txStats      `json:"transactions"`
-	TxHash     common.Hash    `json:"transactionsRoot"`
-	Root       common.Hash    `json:"stateRoot"`
-	Uncles     uncleStats     `json:"uncles"`
+	Number     *big.Int         `json:"number"`
+	Hash       common.Hash      `json:"hash"`
+	ParentHash common.Hash      `json:"parentHash"`
+	Timestamp  *big.Int         `json:"timestamp"`
+	Miner      common.Address   `json:"miner"`
+	GasUsed    uint64           `json:"gasUsed"`
+	GasLimit   uint64           `json:"gasLimit"`
+	Diff       string           `json:"difficulty"`
+	TotalDiff  string           `json:"totalDifficulty"`
+	Txs        []txStats        `json:"transactions"`
+	TxHash     common.Hash      `json:"transactionsRoot"`
+	Root       common.Hash      `json:"stateRoot"`
+	Uncles     uncleStats       `json:"uncles"`
+	Alien      *alienBlockStats `json:"alien,omitempty"`
+}
+
+// alienBlockStats is the alien consensus information to report about a block.
+type alienBlockStats struct {
+	Signer          common.Address   `json:"signer"`
+	InturnSigner    common.Address   `json:"inturnSigner"`
+	SignerMissing   []common.Address `json:"signerMissing"`
+	ConfirmedNumber uint64           `json:"confirmedNumber"`
+	LoopStartTime   uint64           `json:"loopStartTime"`
 }
 
 // txStats is the information to report about individual transactions.
@@ -553,7 +564,7 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 	// Assemble and return the block stats
 	author, _ := s.engine.Author(header)
 
-	return &blockStats{
+	stats := &blockStats{
 		Number:     header.Number,
 		Hash:       header.Hash(),
 		ParentHash: header.ParentHash,
@@ -568,6 +579,42 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 		Root:       header.Root,
 		Uncles:     uncles,
 	}
+	if engine, ok := s.engine.(*alien.Alien); ok {
+		stats.Alien = s.assembleAlienBlockStats(engine, header)
+	}
+	return stats
+}
+
+// assembleAlienBlockStats decodes the alien consensus information of a header,
+// returning nil if it's not available.
+func (s *Service) assembleAlienBlockStats(engine *alien.Alien, header *types.Header) *alienBlockStats {
+	if header.Number.Sign() == 0 {
+		return nil
+	}
+	inspected, err := engine.InspectHeader(s.chain(), header)
+	if err != nil {
+		log.Debug("Failed to inspect alien header", "number", header.Number, "err", err)
+		return nil
+	}
+	signerMissing := inspected.Extra.SignerMissing
+	if signerMissing == nil {
+		signerMissing = []common.Address{}
+	}
+	return &alienBlockStats{
+		Signer:          inspected.Signer,
+		InturnSigner:    inspected.InturnSigner,
+		SignerMissing:   signerMissing,
+		ConfirmedNumber: inspected.Extra.ConfirmedBlockNumber,
+		LoopStartTime:   inspected.Extra.LoopStartTime,
+	}
+}
+
+// chain returns the chain of the monitored full or light node.
+func (s *Service) chain() consensus.ChainReader {
+	if s.eth != nil {
+		return s.eth.BlockChain()
+	}
+	return s.les.BlockChain().HeaderChain()
 }
 
 // reportHistory retrieves the most recent batch of blocks and reports it to the
@@ -670,6 +717,18 @@ type nodeStats struct {
 	Peers    int  `json:"peers"`
 	GasPrice int  `json:"gasPrice"`
 	Uptime   int  `json:"uptime"`
+
+	Alien *alienNodeStats `json:"alien,omitempty"`
+}
+
+// alienNodeStats is the alien consensus information to report about the local
+// node, as of its current head.
+type alienNodeStats struct {
+	Signer          common.Address       `json:"signer"`
+	Punished        uint64               `json:"punished"`
+	ConfirmedNumber uint64               `json:"confirmedNumber"`
+	LoopStartTime   uint64               `json:"loopStartTime"`
+	MainChain       *alien.MainChainLink `json:"mainChain,omitempty"`
 }
 
 // reportPending retrieves various stats about the node at the networking and
@@ -698,20 +757,45 @@ func (s *Service) reportStats(conn *websocket.Conn) error {
 	// Assemble the node stats and send it to the server
 	log.Trace("Sending node details to ethstats")
 
+	details := &nodeStats{
+		Active:   true,
+		Mining:   mining,
+		Hashrate: hashrate,
+		Peers:    s.server.PeerCount(),
+		GasPrice: gasprice,
+		Syncing:  syncing,
+		Uptime:   100,
+	}
+	if engine, ok := s.engine.(*alien.Alien); ok {
+		details.Alien = s.assembleAlienNodeStats(engine)
+	}
 	stats := map[string]interface{}{
-		"id": s.node,
-		"stats": &nodeStats{
-			Active:   true,
-			Mining:   mining,
-			Hashrate: hashrate,
-			Peers:    s.server.PeerCount(),
-			GasPrice: gasprice,
-			Syncing:  syncing,
-			Uptime:   100,
-		},
+		"id":    s.node,
+		"stats": details,
 	}
 	report := map[string][]interface{}{
 		"emit": {"stats", stats},
 	}
 	return websocket.JSON.Send(conn, report)
 }
+
+// assembleAlienNodeStats retrieves the alien consensus information of the local
+// signer from the snapshot at the current head.
+func (s *Service) assembleAlienNodeStats(engine *alien.Alien) *alienNodeStats {
+	chain := s.chain()
+	stats := new(alienNodeStats)
+	if s.eth != nil {
+		stats.Signer, _ = s.eth.Etherbase()
+	}
+	if snap, err := engine.Snapshot(chain, chain.CurrentHeader()); err == nil {
+		stats.Punished = snap.Punished[stats.Signer]
+		stats.ConfirmedNumber = snap.ConfirmedNumber
+		stats.LoopStartTime = snap.LoopStartTime
+	} else {
+		log.Debug("Failed to retrieve alien snapshot", "err", err)
+	}
+	if chain.Config().Alien.SideChain {
+		stats.MainChain = engine.MainChainLink(chain)
+	}
+	return stats
+}
@@ -135,6 +135,31 @@ web3._extend({
 			call: 'alien_getSnapshotByHeaderTime',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'getExpiringVotes',
+			call: 'alien_getExpiringVotes',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getClockDrift',
+			call: 'alien_getClockDrift',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getProposal',
+			call: 'alien_getProposal',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProposalResult',
+			call: 'alien_getProposalResult',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProposalHistory',
+			call: 'alien_getProposalHistory',
+			params: 0
+		}),
 	]
 });
 `
@@ -216,6 +241,12 @@ web3._extend({
 			call: 'debug_printBlock',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'verifyAlienSnapshot',
+			call: 'debug_verifyAlienSnapshot',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getBlockRlp',
 			call: 'debug_getBlockRlp',
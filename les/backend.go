@@ -26,6 +26,7 @@ import (
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/common/hexutil"
 	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/consensus/alien"
 	"github.com/TTCECO/gttc/core"
 	"github.com/TTCECO/gttc/core/bloombits"
 	"github.com/TTCECO/gttc/core/rawdb"
@@ -117,6 +118,11 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 		chtIndexer:       light.NewChtIndexer(chainDb, true),
 		bloomTrieIndexer: light.NewBloomTrieIndexer(chainDb, true),
 	}
+	if engine, ok := leth.engine.(*alien.Alien); ok {
+		if err := eth.SetupAlienCheckpoint(engine, chainConfig, genesisHash, config.AlienCheckpoint); err != nil {
+			return nil, err
+		}
+	}
 
 	leth.relay = NewLesTxRelay(peers, leth.reqDist)
 	leth.serverPool = newServerPool(chainDb, quitSync, &leth.wg)
@@ -68,6 +68,9 @@ const (
 // not compatible (low protocol version restrictions and high requirements).
 var errIncompatibleConfig = errors.New("incompatible configuration")
 
+// errSnapshotUnsupported is returned if a server can't serve consensus snapshots.
+var errSnapshotUnsupported = errors.New("peer doesn't serve consensus snapshots")
+
 func errResp(code errCode, format string, v ...interface{}) error {
 	return fmt.Errorf("%v - %v", code, fmt.Sprintf(format, v...))
 }
@@ -99,6 +102,7 @@ type ProtocolManager struct {
 	txrelay     *LesTxRelay
 	networkId   uint64
 	chainConfig *params.ChainConfig
+	engine      consensus.Engine
 	blockchain  BlockChain
 	chainDb     ethdb.Database
 	odr         *LesOdr
@@ -136,6 +140,7 @@ func NewProtocolManager(chainConfig *params.ChainConfig, lightSync bool, protoco
 		eventMux:    mux,
 		blockchain:  blockchain,
 		chainConfig: chainConfig,
+		engine:      engine,
 		chainDb:     chainDb,
 		odr:         odr,
 		networkId:   networkId,
@@ -332,7 +337,11 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 }
 
-var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg}
+var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg, GetSnapshotMsg}
+
+// reqMinVersion is the first protocol version of the requests added after lpv2,
+// whose costs are only announced to the peers speaking it.
+var reqMinVersion = map[uint64]int{GetSnapshotMsg: lpv3}
 
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
@@ -1100,6 +1109,58 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		p.fcServer.GotReply(resp.ReqID, resp.BV)
 
+	case GetSnapshotMsg:
+		if p.version < lpv3 {
+			return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+		}
+		p.Log().Trace("Received consensus snapshot request")
+		var req struct {
+			ReqID uint64
+			Query getSnapshotData
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if reject(1, 1) {
+			return errResp(ErrRequestRejected, "")
+		}
+		// Serve the snapshot if the engine supports it, an empty one otherwise
+		var snapshot []byte
+		syncer, ok := pm.engine.(consensus.SnapshotSyncer)
+		if chain, isReader := pm.blockchain.(consensus.ChainReader); ok && isReader {
+			if blob, err := syncer.ExportSnapshot(chain, req.Query.Hash, req.Query.Number); err == nil {
+				snapshot = blob
+			} else {
+				p.Log().Debug("Failed to export consensus snapshot", "number", req.Query.Number, "err", err)
+			}
+		}
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, 1, rcost)
+
+		return p.SendSnapshot(req.ReqID, bv, snapshot)
+
+	case SnapshotMsg:
+		if p.version < lpv3 {
+			return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+		}
+		if pm.downloader == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received consensus snapshot response")
+		var resp struct {
+			ReqID, BV uint64
+			Snapshot  []byte
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		if err := pm.downloader.DeliverSnapshot(p.id, resp.Snapshot); err != nil {
+			log.Debug(fmt.Sprint(err))
+		}
+
 	default:
 		p.Log().Trace("Received unknown message", "code", msg.Code)
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
@@ -1259,6 +1320,33 @@ func (pc *peerConnection) RequestHeadersByNumber(origin uint64, amount int, skip
 	return nil
 }
 
+func (pc *peerConnection) RequestSnapshot(hash common.Hash, number uint64) error {
+	if pc.peer.version < lpv3 {
+		return errSnapshotUnsupported
+	}
+	reqID := genReqID()
+	rq := &distReq{
+		getCost: func(dp distPeer) uint64 {
+			peer := dp.(*peer)
+			return peer.GetRequestCost(GetSnapshotMsg, 1)
+		},
+		canSend: func(dp distPeer) bool {
+			return dp.(*peer) == pc.peer
+		},
+		request: func(dp distPeer) func() {
+			peer := dp.(*peer)
+			cost := peer.GetRequestCost(GetSnapshotMsg, 1)
+			peer.fcServer.QueueRequest(reqID, cost)
+			return func() { peer.RequestSnapshot(reqID, cost, hash, number) }
+		},
+	}
+	_, ok := <-pc.manager.reqDist.queue(rq)
+	if !ok {
+		return ErrNoPeers
+	}
+	return nil
+}
+
 func (d *downloaderPeerNotify) registerPeer(p *peer) {
 	pm := (*ProtocolManager)(d)
 	pc := &peerConnection{
@@ -123,14 +123,19 @@ func testChainGen(i int, block *core.BlockGen) {
 	}
 }
 
-func testRCL() RequestCostList {
+func testRCL(version int) RequestCostList {
 	cl := make(RequestCostList, len(reqList))
-	for i, code := range reqList {
+	i := 0
+	for _, code := range reqList {
+		if version < reqMinVersion[code] {
+			continue
+		}
 		cl[i].MsgCode = code
 		cl[i].BaseCost = 0
 		cl[i].ReqCost = 0
+		i++
 	}
-	return cl
+	return cl[:i]
 }
 
 // newTestProtocolManager creates a new protocol manager for testing purposes,
@@ -306,7 +311,7 @@ func (p *testPeer) handshake(t *testing.T, td *big.Int, head common.Hash, headNu
 	expList = expList.add("txRelay", nil)
 	expList = expList.add("flowControl/BL", testBufLimit)
 	expList = expList.add("flowControl/MRR", uint64(1))
-	expList = expList.add("flowControl/MRC", testRCL())
+	expList = expList.add("flowControl/MRC", testRCL(p.version))
 
 	if err := p2p.ExpectMsg(p.app, StatusMsg, expList); err != nil {
 		t.Fatalf("status recv: %v", err)
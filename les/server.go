@@ -280,13 +280,17 @@ func (s *requestCostStats) store() {
 	}
 }
 
-func (s *requestCostStats) getCurrentList() RequestCostList {
+func (s *requestCostStats) getCurrentList(version int) RequestCostList {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
 	list := make(RequestCostList, len(reqList))
+	idx := 0
 	//fmt.Println("RequestCostList")
-	for idx, code := range reqList {
+	for _, code := range reqList {
+		if version < reqMinVersion[code] {
+			continue
+		}
 		b, m := s.stats[code].calc()
 		//fmt.Println(code, s.stats[code].cnt, b/1000000, m/1000000)
 		if m < 0 {
@@ -300,8 +304,9 @@ func (s *requestCostStats) getCurrentList() RequestCostList {
 		list[idx].MsgCode = code
 		list[idx].BaseCost = uint64(b * 2)
 		list[idx].ReqCost = uint64(m * 2)
+		idx++
 	}
-	return list
+	return list[:idx]
 }
 
 func (s *requestCostStats) update(msgCode, reqCnt, cost uint64) {
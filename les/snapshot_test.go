@@ -0,0 +1,213 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/core/vm"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/eth"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/event"
+	"github.com/TTCECO/gttc/les/flowcontrol"
+	"github.com/TTCECO/gttc/light"
+	"github.com/TTCECO/gttc/params"
+	"github.com/TTCECO/gttc/rpc"
+)
+
+// newAlienTestGenesis creates an alien genesis sealed by the given self voting
+// signers, committing to snapshots from block 2 on.
+func newAlienTestGenesis(signers []common.Address, genesisTime uint64) *core.Genesis {
+	stake := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e+18))
+	alloc := make(core.GenesisAlloc)
+	lightAlloc := make(map[common.UnprefixedAddress]params.GenesisAccount)
+	var selfVoters []common.UnprefixedAddress
+	for _, signer := range signers {
+		alloc[signer] = core.GenesisAccount{Balance: stake}
+		lightAlloc[common.UnprefixedAddress(signer)] = params.GenesisAccount{Balance: stake.String()}
+		selfVoters = append(selfVoters, common.UnprefixedAddress(signer))
+	}
+	config := *params.AllAlienProtocolChanges
+	config.Alien = &params.AlienConfig{
+		Period:              1,
+		Epoch:               30000,
+		MaxSignerCount:      uint64(len(signers)),
+		MinVoterBalance:     big.NewInt(100),
+		GenesisTimestamp:    genesisTime,
+		SelfVoteSigners:     selfVoters,
+		SnapshotCommitBlock: big.NewInt(2),
+		LightConfig:         &params.AlienLightConfig{Alloc: lightAlloc},
+	}
+	return &core.Genesis{
+		Config:    &config,
+		Timestamp: genesisTime,
+		ExtraData: make([]byte, 32+65),
+		GasLimit:  params.GenesisGasLimit,
+		Alloc:     alloc,
+	}
+}
+
+// newAlienTestChain creates a full chain of the given length, each block sealed
+// by the in-turn signer.
+func newAlienTestChain(t *testing.T, db ethdb.Database, gspec *core.Genesis, engine *alien.Alien, keys map[common.Address]*ecdsa.PrivateKey, blocks int) *core.BlockChain {
+	gspec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	config := gspec.Config.Alien
+	api := engine.APIs(chain)[0].Service.(*alien.API)
+	signFn := func(account accounts.Account, hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, keys[account.Address])
+	}
+	for i := 1; i <= blocks; i++ {
+		parent := chain.CurrentBlock()
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     big.NewInt(int64(i)),
+			GasLimit:   parent.GasLimit(),
+			Time:       new(big.Int).SetUint64(config.GenesisTimestamp + uint64(i-1)*config.Period),
+			Extra:      make([]byte, 32),
+			Difficulty: big.NewInt(1),
+		}
+		// Pick the in-turn signer from the parent snapshot
+		header.Coinbase = common.Address(config.SelfVoteSigners[0])
+		if i > 1 {
+			latest := rpc.LatestBlockNumber
+			snap, err := api.GetSnapshot(&latest)
+			if err != nil {
+				t.Fatalf("block %d: failed to retrieve snapshot: %v", i, err)
+			}
+			header.Coinbase = *snap.Signers[(header.Time.Uint64()-snap.LoopStartTime)/config.Period%uint64(len(snap.Signers))]
+		}
+		state, err := chain.StateAt(parent.Root())
+		if err != nil {
+			t.Fatalf("block %d: failed to retrieve state: %v", i, err)
+		}
+		block, err := engine.Finalize(chain, header, state, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("block %d: failed to finalize: %v", i, err)
+		}
+		engine.Authorize(header.Coinbase, signFn, nil)
+		if block, err = engine.Seal(chain, block, make(chan struct{})); err != nil {
+			t.Fatalf("block %d: failed to seal: %v", i, err)
+		}
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("block %d: failed to insert: %v", i, err)
+		}
+	}
+	return chain
+}
+
+// Tests that a light client retrieves the alien snapshot at the sync pivot from
+// a LES server, and verifies the remaining headers against it.
+func TestAlienSnapshotSyncLes3(t *testing.T) {
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	var signers []common.Address
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		signers = append(signers, addr)
+	}
+	blocks := 80
+	gspec := newAlienTestGenesis(signers, uint64(time.Now().Unix())-uint64(blocks)-10)
+
+	// Assemble the server with a sealed chain
+	db := ethdb.NewMemDatabase()
+	engine := alien.New(gspec.Config.Alien, db)
+	chain := newAlienTestChain(t, db, gspec, engine, keys, blocks)
+
+	pm, err := NewProtocolManager(gspec.Config, false, ServerProtocolVersions, NetworkId, new(event.TypeMux), engine, newPeerSet(), chain, nil, db, nil, nil, make(chan struct{}), new(sync.WaitGroup))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	srv := &LesServer{protocolManager: pm}
+	srv.defParams = &flowcontrol.ServerParams{BufLimit: testBufLimit, MinRecharge: 1}
+	srv.fcManager = flowcontrol.NewClientManager(50, 10, 1000000000)
+	srv.fcCostStats = newCostStats(nil)
+	pm.server = srv
+	pm.Start(1000)
+
+	// Assemble the light client
+	peers := newPeerSet()
+	ldb := ethdb.NewMemDatabase()
+	gspec.MustCommit(ldb)
+	dist := newRequestDistributor(peers, make(chan struct{}))
+	odr := NewLesOdr(ldb, light.NewChtIndexer(ldb, true), light.NewBloomTrieIndexer(ldb, true), eth.NewBloomIndexer(ldb, light.BloomTrieFrequency), newRetrieveManager(peers, dist, nil))
+	lengine := alien.New(gspec.Config.Alien, ldb)
+	lchain, err := light.NewLightChain(odr, gspec.Config, lengine)
+	if err != nil {
+		t.Fatalf("failed to create light chain: %v", err)
+	}
+	lpm, err := NewProtocolManager(gspec.Config, true, ClientProtocolVersions, NetworkId, new(event.TypeMux), lengine, peers, lchain, nil, ldb, odr, nil, make(chan struct{}), new(sync.WaitGroup))
+	if err != nil {
+		t.Fatalf("failed to create light client: %v", err)
+	}
+	lpm.Start(1000)
+
+	_, err1, lpeer, err2 := newTestPeerPair("peer", lpv3, pm, lpm)
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case err := <-err1:
+		t.Fatalf("server handshake error: %v", err)
+	case err := <-err2:
+		t.Fatalf("client handshake error: %v", err)
+	}
+	lpm.synchronise(lpeer)
+
+	if head := lchain.CurrentHeader().Number.Uint64(); head != uint64(blocks) {
+		t.Fatalf("light client head mismatch: have %d, want %d", head, blocks)
+	}
+	// The snapshot at the pivot must have been imported instead of rebuilt
+	pivot := chain.GetHeaderByNumber(uint64(blocks - 64))
+	if _, err := ldb.Get(append([]byte("alien-"), pivot.Hash().Bytes()...)); err != nil {
+		t.Errorf("pivot snapshot not imported: %v", err)
+	}
+	skipped := chain.GetHeaderByNumber(uint64(blocks - 65))
+	if _, err := ldb.Get(append([]byte("alien-"), skipped.Hash().Bytes()...)); err == nil {
+		t.Errorf("snapshot below the pivot unexpectedly stored")
+	}
+}
+
+// Tests that consensus snapshots are neither requested from nor served to peers
+// speaking a protocol version before les/3.
+func TestGetSnapshotLes2Rejected(t *testing.T) {
+	pm := newTestProtocolManagerMust(t, false, 0, nil, nil, nil, ethdb.NewMemDatabase())
+	peer, errc := newTestPeer(t, "peer", lpv2, pm, true)
+	defer peer.close()
+
+	sendRequest(peer.app, GetSnapshotMsg, 42, 0, &getSnapshotData{})
+	select {
+	case err := <-errc:
+		if want := errResp(ErrInvalidMsgCode, "%v", GetSnapshotMsg); err == nil || err.Error() != want.Error() {
+			t.Fatalf("error mismatch: have %v, want %v", err, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("snapshot request of a les/2 peer not rejected")
+	}
+}
@@ -54,6 +54,7 @@ type LightChain struct {
 	chainFeed     event.Feed
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
+	headerFeed    event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -95,13 +96,15 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, engine consensus.
 	if err != nil {
 		return nil, err
 	}
-	if alien, ok := bc.hc.Engine().(*alien.Alien); ok {
-		alien.ApplyGenesis(bc.hc, bc.hc.CurrentHeader().Root)
-	}
 	bc.genesisBlock, _ = bc.GetBlockByNumber(NoOdr, 0)
 	if bc.genesisBlock == nil {
 		return nil, core.ErrNoGenesis
 	}
+	if alien, ok := bc.hc.Engine().(*alien.Alien); ok {
+		if err := alien.ApplyGenesis(bc.hc, bc.genesisBlock.Hash()); err != nil {
+			log.Warn("Failed to apply alien genesis snapshot", "err", err)
+		}
+	}
 	if cp, ok := trustedCheckpoints[bc.genesisBlock.Hash()]; ok {
 		bc.addTrustedCheckpoint(cp)
 	}
@@ -207,6 +210,9 @@ func (bc *LightChain) ResetWithGenesisBlock(genesis *types.Block) {
 // Engine retrieves the light chain's consensus engine.
 func (bc *LightChain) Engine() consensus.Engine { return bc.engine }
 
+// HeaderChain returns the header chain backing the light chain.
+func (bc *LightChain) HeaderChain() *core.HeaderChain { return bc.hc }
+
 // Genesis returns the genesis block
 func (bc *LightChain) Genesis() *types.Block {
 	return bc.genesisBlock
@@ -337,6 +343,7 @@ func (self *LightChain) postChainEvents(events []interface{}) {
 		case core.ChainEvent:
 			if self.CurrentHeader().Hash() == ev.Hash {
 				self.chainHeadFeed.Send(core.ChainHeadEvent{Block: ev.Block})
+				self.headerFeed.Send(ev.Block.Header())
 			}
 			self.chainFeed.Send(ev)
 		case core.ChainSideEvent:
@@ -497,6 +504,12 @@ func (self *LightChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) e
 	return self.scope.Track(self.chainHeadFeed.Subscribe(ch))
 }
 
+// SubscribeChainHeaders registers a subscription of the headers of the new
+// canonical heads.
+func (self *LightChain) SubscribeChainHeaders(ch chan<- *types.Header) event.Subscription {
+	return self.scope.Track(self.headerFeed.Subscribe(ch))
+}
+
 // SubscribeChainSideEvent registers a subscription of ChainSideEvent.
 func (self *LightChain) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
 	return self.scope.Track(self.chainSideFeed.Subscribe(ch))
@@ -47,6 +47,9 @@ const (
 	chainHeadChanSize = 10
 	// chainSideChanSize is the size of channel listening to ChainSideEvent.
 	chainSideChanSize = 10
+	// consensusTxGasPercent is the percentage of the block gas limit reserved
+	// for consensus transactions, which are committed ahead of user traffic.
+	consensusTxGasPercent = 10
 )
 
 // Agent can register themself with the worker
@@ -474,6 +477,12 @@ func (self *worker) commitNewWork() {
 		log.Error("Failed to fetch pending transactions", "err", err)
 		return
 	}
+	// Commit the consensus transactions first, inside their reserved gas lane
+	if prioritizer, ok := self.engine.(consensus.TxPrioritizer); ok {
+		if reserved := splitConsensusTxs(prioritizer, self.chain, pending); len(reserved) > 0 {
+			work.commitReservedTransactions(self.mux, reserved, pending, self.chain, self.coinbase)
+		}
+	}
 	txs := types.NewTransactionsByPriceAndNonce(self.current.signer, pending)
 	work.commitTransactions(self.mux, txs, self.chain, self.coinbase)
 
@@ -522,10 +531,27 @@ func (self *worker) commitNewWork() {
 }
 
 // For PBFT of alien consensus, the follow code may move later
+// txSigner is implemented by consensus engines able to sign transactions with
+// the credentials they were authorized with.
+type txSigner interface {
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
 // After confirm a new block, the miner send a custom transaction to self, which value is 0
 // and data like "ufo:1:event:confirm:123" (ufo is prefix, 1 is version, 123 is block number
 // to let the next signer now this signer already confirm this block
 func (self *worker) sendConfirmTx(blockNumber *big.Int) error {
+	// Let the engine sign if it can, so an external signer sealing the blocks
+	// also signs the confirmations
+	if signer, ok := self.engine.(txSigner); ok {
+		nonce := self.snapshotState.GetNonce(self.coinbase)
+		tmpTx := types.NewTransaction(nonce, self.coinbase, big.NewInt(0), uint64(100000), big.NewInt(10000), []byte(fmt.Sprintf("ufo:1:event:confirm:%d", blockNumber)))
+		signedTx, err := signer.SignTx(tmpTx, self.eth.BlockChain().Config().ChainId)
+		if err != nil {
+			return err
+		}
+		return self.eth.TxPool().AddLocal(signedTx)
+	}
 	wallets := self.eth.AccountManager().Wallets()
 	// wallets check
 	if len(wallets) == 0 {
@@ -587,6 +613,64 @@ func (self *worker) updateSnapshot() {
 	self.snapshotState = self.current.state.Copy()
 }
 
+// splitConsensusTxs moves the leading consensus transactions of each account
+// out of the pending set, returning them as a separate set.
+func splitConsensusTxs(prioritizer consensus.TxPrioritizer, chain consensus.ChainReader, pending map[common.Address]types.Transactions) map[common.Address]types.Transactions {
+	reserved := make(map[common.Address]types.Transactions)
+	for from, txs := range pending {
+		i := 0
+		for i < len(txs) && prioritizer.IsConsensusTx(chain, txs[i], from) {
+			i++
+		}
+		if i == 0 {
+			continue
+		}
+		reserved[from] = txs[:i]
+		if i == len(txs) {
+			delete(pending, from)
+		} else {
+			pending[from] = txs[i:]
+		}
+	}
+	return reserved
+}
+
+// commitReservedTransactions commits consensus transactions ahead of the user
+// traffic, bounded by the gas reserved for them in each block. The consensus
+// transactions overflowing the reserve are handed back to the pending set, so
+// they compete for the general gas lane ahead of the later transactions of the
+// same account instead of leaving a nonce gap.
+func (env *Work) commitReservedTransactions(mux *event.TypeMux, reserved, pending map[common.Address]types.Transactions, bc *core.BlockChain, coinbase common.Address) {
+	reserve := env.header.GasLimit * consensusTxGasPercent / 100
+	if env.gasPool != nil && env.gasPool.Gas() < reserve {
+		reserve = env.gasPool.Gas()
+	}
+	left := env.header.GasLimit - reserve
+	if env.gasPool != nil {
+		left = env.gasPool.Gas() - reserve
+	}
+	// The price and nonce ordering consumes its input, so hand it a copy
+	lane := make(map[common.Address]types.Transactions, len(reserved))
+	for from, txs := range reserved {
+		lane[from] = txs
+	}
+	env.gasPool = new(core.GasPool).AddGas(reserve)
+	env.commitTransactions(mux, types.NewTransactionsByPriceAndNonce(env.signer, lane), bc, coinbase)
+	env.gasPool.AddGas(left)
+
+	for from, txs := range reserved {
+		nonce := env.state.GetNonce(from)
+
+		i := 0
+		for i < len(txs) && txs[i].Nonce() < nonce {
+			i++
+		}
+		if i < len(txs) {
+			pending[from] = append(append(types.Transactions{}, txs[i:]...), pending[from]...)
+		}
+	}
+}
+
 func (env *Work) commitTransactions(mux *event.TypeMux, txs *types.TransactionsByPriceAndNonce, bc *core.BlockChain, coinbase common.Address) {
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
@@ -0,0 +1,165 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus"
+	"github.com/TTCECO/gttc/consensus/ethash"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/core/vm"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/ethdb"
+	"github.com/TTCECO/gttc/event"
+	"github.com/TTCECO/gttc/params"
+)
+
+var (
+	consensusKey, _ = crypto.GenerateKey()
+	userKey, _      = crypto.GenerateKey()
+)
+
+// testPrioritizer marks the transactions carrying the "consensus" payload as
+// consensus transactions.
+type testPrioritizer struct{}
+
+func (testPrioritizer) IsConsensusTx(chain consensus.ChainReader, tx *types.Transaction, sender common.Address) bool {
+	return string(tx.Data()) == "consensus"
+}
+
+// newTestWork creates a mining environment on top of a genesis funding the test
+// accounts, with room for exactly one transfer in the consensus gas reserve.
+func newTestWork(t *testing.T) (*Work, *core.BlockChain) {
+	db := ethdb.NewMemDatabase()
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			crypto.PubkeyToAddress(consensusKey.PublicKey): {Balance: big.NewInt(1e18)},
+			crypto.PubkeyToAddress(userKey.PublicKey):      {Balance: big.NewInt(1e18)},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	statedb, err := chain.StateAt(genesis.Root())
+	if err != nil {
+		t.Fatalf("failed to retrieve state: %v", err)
+	}
+	work := &Work{
+		config: gspec.Config,
+		signer: types.NewEIP155Signer(gspec.Config.ChainId),
+		state:  statedb,
+		header: &types.Header{
+			ParentHash: genesis.Hash(),
+			Number:     big.NewInt(1),
+			Time:       big.NewInt(1),
+			GasLimit:   3 * params.TxGas / 2 * 100 / consensusTxGasPercent,
+			Difficulty: big.NewInt(1),
+		},
+	}
+	return work, chain
+}
+
+// signTestTx creates a transfer of the given account with the given payload.
+func signTestTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, price int64, data string) *types.Transaction {
+	tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{0x01}, big.NewInt(1), params.TxGas+uint64(len(data))*params.TxDataNonZeroGas, big.NewInt(price), []byte(data)), types.NewEIP155Signer(params.TestChainConfig.ChainId), key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	return tx
+}
+
+// Tests that the leading consensus transactions of each account are split off
+// the pending set, leaving the rest of the account in place.
+func TestSplitConsensusTxs(t *testing.T) {
+	var (
+		consensusAddr = crypto.PubkeyToAddress(consensusKey.PublicKey)
+		userAddr      = crypto.PubkeyToAddress(userKey.PublicKey)
+	)
+	pending := map[common.Address]types.Transactions{
+		consensusAddr: {
+			signTestTx(t, consensusKey, 0, 1, "consensus"),
+			signTestTx(t, consensusKey, 1, 1, ""),
+			signTestTx(t, consensusKey, 2, 1, "consensus"),
+		},
+		userAddr: {signTestTx(t, userKey, 0, 1, "")},
+	}
+	reserved := splitConsensusTxs(testPrioritizer{}, nil, pending)
+
+	if len(reserved) != 1 || len(reserved[consensusAddr]) != 1 || reserved[consensusAddr][0].Nonce() != 0 {
+		t.Fatalf("reserved set mismatch: have %v", reserved)
+	}
+	// A consensus tx behind a user tx must stay behind it to keep the nonces in order
+	if txs := pending[consensusAddr]; len(txs) != 2 || txs[0].Nonce() != 1 || txs[1].Nonce() != 2 {
+		t.Errorf("remaining consensus account txs mismatch: have %v", txs)
+	}
+	if txs := pending[userAddr]; len(txs) != 1 {
+		t.Errorf("user account txs mismatch: have %v", txs)
+	}
+}
+
+// Tests that consensus transactions are committed first within their gas
+// reserve, and that the ones overflowing it fall back to the general lane ahead
+// of the later user transactions of the same account.
+func TestCommitReservedTransactions(t *testing.T) {
+	work, chain := newTestWork(t)
+	defer chain.Stop()
+
+	var (
+		consensusAddr = crypto.PubkeyToAddress(consensusKey.PublicKey)
+		userAddr      = crypto.PubkeyToAddress(userKey.PublicKey)
+
+		consensus0 = signTestTx(t, consensusKey, 0, 1, "consensus")
+		consensus1 = signTestTx(t, consensusKey, 1, 1, "consensus")
+		user2      = signTestTx(t, consensusKey, 2, 1, "")
+		other0     = signTestTx(t, userKey, 0, 10, "")
+	)
+	pending := map[common.Address]types.Transactions{
+		consensusAddr: {consensus0, consensus1, user2},
+		userAddr:      {other0},
+	}
+	reserved := splitConsensusTxs(testPrioritizer{}, nil, pending)
+
+	mux := new(event.TypeMux)
+	work.commitReservedTransactions(mux, reserved, pending, chain, common.Address{})
+	if len(work.txs) != 1 || work.txs[0] != consensus0 {
+		t.Fatalf("reserved lane mismatch: have %v, want only %x", work.txs, consensus0.Hash())
+	}
+	work.commitTransactions(mux, types.NewTransactionsByPriceAndNonce(work.signer, pending), chain, common.Address{})
+
+	// The cheap consensus txs go first, the overflowing one is ordered by price
+	want := []*types.Transaction{consensus0, other0, consensus1, user2}
+	if len(work.txs) != len(want) {
+		t.Fatalf("committed tx count mismatch: have %d, want %d", len(work.txs), len(want))
+	}
+	for i, tx := range want {
+		if work.txs[i] != tx {
+			t.Errorf("tx %d mismatch: have %x, want %x", i, work.txs[i].Hash(), tx.Hash())
+		}
+	}
+	if nonce := work.state.GetNonce(consensusAddr); nonce != 3 {
+		t.Errorf("consensus account nonce mismatch: have %d, want 3", nonce)
+	}
+}
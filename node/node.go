@@ -27,6 +27,7 @@ import (
 	"sync"
 
 	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/core/rawdb"
 	"github.com/TTCECO/gttc/ethdb"
 	"github.com/TTCECO/gttc/event"
 	"github.com/TTCECO/gttc/internal/debug"
@@ -573,6 +574,35 @@ func (n *Node) OpenDatabase(name string, cache, handles int) (ethdb.Database, er
 	return ethdb.NewLDBDatabase(n.config.resolvePath(name), cache, handles)
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's data directory,
+// also attaching a chain freezer to it that moves ancient chain data from the
+// database to immutable append-only files. If the node is an ephemeral one, a
+// memory database is returned.
+func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer string, threshold uint64) (ethdb.Database, error) {
+	if n.config.DataDir == "" {
+		return ethdb.NewMemDatabase(), nil
+	}
+	root := n.config.resolvePath(name)
+
+	switch {
+	case freezer == "":
+		freezer = filepath.Join(root, "ancient")
+	case !filepath.IsAbs(freezer):
+		freezer = n.config.resolvePath(freezer)
+	}
+	kvdb, err := ethdb.NewLDBDatabase(root, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	db, err := rawdb.NewDatabaseWithFreezer(kvdb, freezer, threshold)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
 // ResolvePath returns the absolute path of a resource in the instance directory.
 func (n *Node) ResolvePath(x string) string {
 	return n.config.resolvePath(x)
@@ -17,9 +17,11 @@
 package node
 
 import (
+	"path/filepath"
 	"reflect"
 
 	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/core/rawdb"
 	"github.com/TTCECO/gttc/ethdb"
 	"github.com/TTCECO/gttc/event"
 	"github.com/TTCECO/gttc/p2p"
@@ -50,6 +52,37 @@ func (ctx *ServiceContext) OpenDatabase(name string, cache int, handles int) (et
 	return db, nil
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's data directory,
+// also attaching a chain freezer to it that moves ancient chain data from the
+// database to immutable append-only files. If the freezer path is empty, the
+// ancient store is placed into the database directory, relative paths are
+// resolved within the data directory. If the node is an ephemeral one, a memory
+// database is returned.
+func (ctx *ServiceContext) OpenDatabaseWithFreezer(name string, cache int, handles int, freezer string, threshold uint64) (ethdb.Database, error) {
+	if ctx.config.DataDir == "" {
+		return ethdb.NewMemDatabase(), nil
+	}
+	root := ctx.config.resolvePath(name)
+
+	switch {
+	case freezer == "":
+		freezer = filepath.Join(root, "ancient")
+	case !filepath.IsAbs(freezer):
+		freezer = ctx.config.resolvePath(freezer)
+	}
+	kvdb, err := ethdb.NewLDBDatabase(root, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	db, err := rawdb.NewDatabaseWithFreezer(kvdb, freezer, threshold)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
 // ResolvePath resolves a user path into the data directory if that was relative
 // and if the user actually uses persistent storage. It will return an empty string
 // for emphemeral storage and the user's own input for absolute paths.
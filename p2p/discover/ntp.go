@@ -56,6 +56,12 @@ func checkClockDrift() {
 	}
 }
 
+// SNTPDrift measures the drift of the local clock against an NTP server, which
+// is positive when the local clock is ahead.
+func SNTPDrift() (time.Duration, error) {
+	return sntpDrift(ntpChecks)
+}
+
 // sntpDrift does a naive time resolution against an NTP server and returns the
 // measured drift. This method uses the simple version of NTP. It's not precise
 // but should be fine for these purposes.
@@ -0,0 +1,186 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package aliensim
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/event"
+	"github.com/TTCECO/gttc/log"
+	"github.com/TTCECO/gttc/p2p/discover"
+)
+
+// EventType is the kind of a consensus event.
+type EventType string
+
+const (
+	// EventNewLoop is emitted when a block starts a new signer loop.
+	EventNewLoop EventType = "loop"
+
+	// EventPunish is emitted when a signer is punished for missing its slot.
+	EventPunish EventType = "punish"
+
+	// EventConfirm is emitted when the confirmed number of the main chain or
+	// the last confirmed number of a side chain advances.
+	EventConfirm EventType = "confirm"
+
+	// EventProposal is emitted when the result of a proposal is calculated.
+	EventProposal EventType = "proposal"
+)
+
+// Event is a consensus event observed when a node imports a block. The same
+// block imported by several nodes is only reported once, by the first node.
+type Event struct {
+	Type   EventType       `json:"type"`
+	Node   discover.NodeID `json:"node"`
+	Number uint64          `json:"number"`
+	Hash   common.Hash     `json:"hash"`
+	Time   time.Time       `json:"time"`
+
+	Signers   []*common.Address `json:"signers,omitempty"`   // Signer queue of the new loop
+	Signer    *common.Address   `json:"signer,omitempty"`    // Punished signer
+	Score     uint64            `json:"score,omitempty"`     // Punish score of the punished signer
+	Confirmed uint64            `json:"confirmed,omitempty"` // Newly confirmed block number
+	SideChain *common.Hash      `json:"sideChain,omitempty"` // Side chain of the confirmation, nil for the main chain
+	Proposal  *common.Hash      `json:"proposal,omitempty"`  // Hash of the decided proposal
+	Kind      uint64            `json:"kind,omitempty"`      // Type of the decided proposal
+	Passed    *bool             `json:"passed,omitempty"`    // Whether the decided proposal passed
+}
+
+// eventKey identifies an event independently of the node observing it.
+type eventKey struct {
+	kind    EventType
+	hash    common.Hash
+	subject common.Hash
+}
+
+// SubscribeEvents subscribes the given channel to the consensus events of the
+// network.
+func (n *Network) SubscribeEvents(ch chan<- *Event) event.Subscription {
+	return n.events.Subscribe(ch)
+}
+
+// watch reports the consensus events of every block the given node imports
+// into its canonical chain, until the network is shut down.
+func (n *Network) watch(id discover.NodeID) {
+	defer n.wg.Done()
+
+	srv, err := n.service(id)
+	if err != nil {
+		log.Warn("Failed to watch alien node", "node", id.TerminalString(), "err", err)
+		return
+	}
+	api, ok := srv.Engine().APIs(srv.BlockChain())[0].Service.(*alien.API)
+	if !ok {
+		return
+	}
+	blocks := make(chan core.ChainEvent, 64)
+	sub := srv.BlockChain().SubscribeChainEvent(blocks)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-blocks:
+			block := ev.Block
+			if block.NumberU64() == 0 {
+				continue
+			}
+			parent, err := api.GetSnapshotAtHash(block.ParentHash())
+			if err != nil {
+				log.Debug("Failed to retrieve alien parent snapshot", "number", block.NumberU64()-1, "err", err)
+				continue
+			}
+			snap, err := api.GetSnapshotAtHash(block.Hash())
+			if err != nil {
+				log.Debug("Failed to retrieve alien snapshot", "number", block.NumberU64(), "err", err)
+				continue
+			}
+			for _, event := range diffSnapshots(parent, snap) {
+				key := eventKey{event.Type, block.Hash(), common.Hash{}}
+				switch {
+				case event.Signer != nil:
+					key.subject = event.Signer.Hash()
+				case event.SideChain != nil:
+					key.subject = *event.SideChain
+				case event.Proposal != nil:
+					key.subject = *event.Proposal
+				}
+				if seen, _ := n.seen.ContainsOrAdd(key, struct{}{}); seen {
+					continue
+				}
+				event.Node, event.Number, event.Hash, event.Time = id, block.NumberU64(), block.Hash(), time.Now()
+				n.events.Send(event)
+			}
+		case <-sub.Err():
+			return
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// diffSnapshots derives the consensus events caused by the block moving the
+// parent snapshot to the given one.
+func diffSnapshots(parent, snap *alien.Snapshot) []*Event {
+	var events []*Event
+
+	if snap.LoopStartTime != parent.LoopStartTime {
+		events = append(events, &Event{Type: EventNewLoop, Signers: snap.Signers})
+	}
+	for signer, score := range snap.Punished {
+		if score > parent.Punished[signer] {
+			signer := signer
+			events = append(events, &Event{Type: EventPunish, Signer: &signer, Score: score})
+		}
+	}
+	if snap.ConfirmedNumber > parent.ConfirmedNumber {
+		events = append(events, &Event{Type: EventConfirm, Confirmed: snap.ConfirmedNumber})
+	}
+	for hash, record := range snap.SCRecordMap {
+		if prev, ok := parent.SCRecordMap[hash]; ok && record.LastConfirmedNumber > prev.LastConfirmedNumber {
+			hash := hash
+			events = append(events, &Event{Type: EventConfirm, Confirmed: record.LastConfirmedNumber, SideChain: &hash})
+		}
+	}
+	for hash, proposal := range parent.Proposals {
+		if _, ok := snap.Proposals[hash]; ok {
+			continue
+		}
+		// Proposals are dropped from the snapshot once their result is calculated,
+		// passing if the yes declarations hold more than 2/3 of the total stake
+		total, yes := new(big.Int), new(big.Int)
+		for _, stake := range parent.Tally {
+			total.Add(total, stake)
+		}
+		for _, declare := range proposal.Declares {
+			if stake, ok := parent.Tally[declare.Declarer]; ok && declare.Decision {
+				yes.Add(yes, stake)
+			}
+		}
+		total.Mul(total, big.NewInt(2))
+		total.Div(total, big.NewInt(3))
+		passed := yes.Cmp(total) > 0
+
+		hash := hash
+		events = append(events, &Event{Type: EventProposal, Proposal: &hash, Kind: proposal.ProposalType, Passed: &passed})
+	}
+	return events
+}
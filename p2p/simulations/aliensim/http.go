@@ -0,0 +1,183 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package aliensim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/TTCECO/gttc/p2p/discover"
+	"github.com/TTCECO/gttc/p2p/simulations"
+)
+
+// Server is an HTTP server serving the simulation API of an alien network,
+// extended with the alien scenario endpoints:
+//
+//	POST /alien/launch                 launch the network, body is a Config
+//	GET  /alien                        consensus status of every node
+//	POST /alien/partition              partition the network, body is a Partition
+//	POST /alien/heal                   reconnect all the nodes
+//	POST /alien/nodes/:nodeid/outage   stop a sealer from sealing
+//	POST /alien/nodes/:nodeid/recover  restart sealing on a sealer
+//	GET  /alien/events                 stream consensus events as server-sent-events
+type Server struct {
+	*simulations.Server
+	network *Network
+}
+
+// Partition is the body of a partition request, listing the node IDs or names
+// of each group.
+type Partition struct {
+	Groups [][]string `json:"groups"`
+}
+
+// NewServer returns a new alien simulation API server
+func NewServer(network *Network) *Server {
+	s := &Server{
+		Server:  simulations.NewServer(network.Network),
+		network: network,
+	}
+	s.POST("/alien/launch", s.Launch)
+	s.GET("/alien", s.GetStatus)
+	s.POST("/alien/partition", s.Partition)
+	s.POST("/alien/heal", s.Heal)
+	s.POST("/alien/nodes/:nodeid/outage", s.Outage)
+	s.POST("/alien/nodes/:nodeid/recover", s.Recover)
+	s.GET("/alien/events", s.StreamConsensusEvents)
+
+	return s
+}
+
+// Launch launches the alien network
+func (s *Server) Launch(w http.ResponseWriter, req *http.Request) {
+	config := &Config{}
+	if err := json.NewDecoder(req.Body).Decode(config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.network.Launch(config); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.JSON(w, http.StatusOK, s.network.Status())
+}
+
+// GetStatus returns the consensus status of every node
+func (s *Server) GetStatus(w http.ResponseWriter, req *http.Request) {
+	s.JSON(w, http.StatusOK, s.network.Status())
+}
+
+// Partition splits the network into the requested groups
+func (s *Server) Partition(w http.ResponseWriter, req *http.Request) {
+	partition := &Partition{}
+	if err := json.NewDecoder(req.Body).Decode(partition); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	groups := make([][]discover.NodeID, len(partition.Groups))
+	for i, group := range partition.Groups {
+		for _, id := range group {
+			node := s.network.GetNodeByName(id)
+			if nodeID, err := discover.HexID(id); err == nil {
+				node = s.network.GetNode(nodeID)
+			}
+			if node == nil {
+				http.Error(w, fmt.Sprintf("unknown node: %s", id), http.StatusBadRequest)
+				return
+			}
+			groups[i] = append(groups[i], node.ID())
+		}
+	}
+	if err := s.network.Partition(groups...); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.JSON(w, http.StatusOK, s.network.Status())
+}
+
+// Heal reconnects all the nodes
+func (s *Server) Heal(w http.ResponseWriter, req *http.Request) {
+	if err := s.network.Heal(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.JSON(w, http.StatusOK, s.network.Status())
+}
+
+// Outage stops a sealer from sealing
+func (s *Server) Outage(w http.ResponseWriter, req *http.Request) {
+	node := req.Context().Value("node").(*simulations.Node)
+
+	if err := s.network.Outage(node.ID()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.JSON(w, http.StatusOK, s.network.Status())
+}
+
+// Recover restarts sealing on a sealer
+func (s *Server) Recover(w http.ResponseWriter, req *http.Request) {
+	node := req.Context().Value("node").(*simulations.Node)
+
+	if err := s.network.Recover(node.ID()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.JSON(w, http.StatusOK, s.network.Status())
+}
+
+// StreamConsensusEvents streams consensus events as a server-sent-events stream
+func (s *Server) StreamConsensusEvents(w http.ResponseWriter, req *http.Request) {
+	events := make(chan *Event, 64)
+	sub := s.network.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	// stop the stream if the client goes away
+	var clientGone <-chan bool
+	if cn, ok := w.(http.CloseNotifier); ok {
+		clientGone = cn.CloseNotify()
+	}
+	write := func(event, data string) {
+		fmt.Fprintf(w, "event: %s\n", event)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if fw, ok := w.(http.Flusher); ok {
+			fw.Flush()
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "\n\n")
+	if fw, ok := w.(http.Flusher); ok {
+		fw.Flush()
+	}
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				write("error", err.Error())
+				return
+			}
+			write("consensus", string(data))
+		case <-clientGone:
+			return
+		}
+	}
+}
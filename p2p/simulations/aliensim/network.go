@@ -0,0 +1,392 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package aliensim runs private alien networks of sealers and followers
+// in-process on top of the p2p simulation framework, so that partitions and
+// signer outages can be rehearsed locally.
+package aliensim
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/eth"
+	"github.com/TTCECO/gttc/event"
+	"github.com/TTCECO/gttc/log"
+	"github.com/TTCECO/gttc/node"
+	"github.com/TTCECO/gttc/p2p/discover"
+	"github.com/TTCECO/gttc/p2p/simulations"
+	"github.com/TTCECO/gttc/p2p/simulations/adapters"
+	"github.com/TTCECO/gttc/params"
+	"github.com/hashicorp/golang-lru"
+)
+
+// serviceName is the name the alien service is registered under in the
+// simulation adapter.
+const serviceName = "alien"
+
+var (
+	errAlreadyLaunched = errors.New("alien network already launched")
+	errNotLaunched     = errors.New("alien network not launched")
+	errNotSealer       = errors.New("node is not a sealer")
+	errUnknownNode     = errors.New("unknown node")
+	errNoSealers       = errors.New("alien network needs at least one sealer")
+)
+
+// sealerBalance is the genesis balance of every sealer, which is also the
+// stake of its genesis self vote.
+var sealerBalance = new(big.Int).Mul(big.NewInt(1000000), big.NewInt(1e18))
+
+// Config is the layout of a simulated alien network.
+type Config struct {
+	Sealers   int    `json:"sealers"`   // Number of nodes sealing blocks, all voted in at genesis
+	Followers int    `json:"followers"` // Number of nodes only following the chain
+	Period    uint64 `json:"period"`    // Number of seconds between blocks, defaults to 1
+}
+
+// NodeStatus is the consensus view of a single simulated node.
+type NodeStatus struct {
+	ID      discover.NodeID `json:"id"`
+	Name    string          `json:"name"`
+	Up      bool            `json:"up"`
+	Sealer  bool            `json:"sealer"`
+	Signer  common.Address  `json:"signer"`
+	Sealing bool            `json:"sealing"`
+	Group   int             `json:"group"`
+	Number  uint64          `json:"number"`
+	Hash    common.Hash     `json:"hash"`
+}
+
+// Network is a p2p simulation network running a private alien chain.
+type Network struct {
+	*simulations.Network
+
+	config  *Config
+	genesis *core.Genesis
+	nodes   []discover.NodeID                     // Nodes in launch order, sealers first
+	keys    map[discover.NodeID]*ecdsa.PrivateKey // Signing keys of the sealers
+	groups  map[discover.NodeID]int               // Partition group of each node
+	lock    sync.RWMutex
+
+	events event.Feed
+	seen   *lru.Cache // Events already streamed, as nodes observe the same blocks
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewNetwork creates an empty simulation network able to run an alien chain.
+func NewNetwork() *Network {
+	seen, _ := lru.New(4096)
+	n := &Network{
+		keys:   make(map[discover.NodeID]*ecdsa.PrivateKey),
+		groups: make(map[discover.NodeID]int),
+		seen:   seen,
+		quit:   make(chan struct{}),
+	}
+	adapter := adapters.NewSimAdapter(map[string]adapters.ServiceFunc{serviceName: n.newService})
+	n.Network = simulations.NewNetwork(adapter, &simulations.NetworkConfig{
+		ID:             "alien",
+		DefaultService: serviceName,
+	})
+	return n
+}
+
+// service is the alien chain run by a simulated node. Sealers sign with their
+// node key, so the signer address is derived from the node ID.
+type service struct {
+	*eth.Ethereum
+	signer common.Address
+	sealer bool
+}
+
+// newService creates the alien service of a simulated node.
+func (n *Network) newService(ctx *adapters.ServiceContext) (node.Service, error) {
+	n.lock.RLock()
+	genesis, key := n.genesis, n.keys[ctx.Config.ID]
+	n.lock.RUnlock()
+
+	if genesis == nil {
+		return nil, errNotLaunched
+	}
+	config := eth.DefaultConfig
+	config.Genesis = genesis
+	config.NetworkId = genesis.Config.ChainId.Uint64()
+	config.TxPool.Journal = ""
+
+	srv := &service{signer: crypto.PubkeyToAddress(ctx.Config.PrivateKey.PublicKey), sealer: key != nil}
+	if srv.sealer {
+		config.Etherbase = srv.signer
+	}
+	ethereum, err := eth.New(ctx.NodeContext, &config)
+	if err != nil {
+		return nil, err
+	}
+	engine, ok := ethereum.Engine().(*alien.Alien)
+	if !ok {
+		return nil, fmt.Errorf("unexpected consensus engine %T", ethereum.Engine())
+	}
+	// Build the genesis snapshot with the genesis votes before any block is
+	// verified, followers never finalize block 1 themselves
+	if err := engine.ApplyGenesis(ethereum.BlockChain(), ethereum.BlockChain().Genesis().Hash()); err != nil {
+		return nil, err
+	}
+	if srv.sealer {
+		engine.Authorize(srv.signer, func(account accounts.Account, hash []byte) ([]byte, error) {
+			return crypto.Sign(hash, key)
+		}, func(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+			return types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+		})
+	}
+	srv.Ethereum = ethereum
+	return srv, nil
+}
+
+// Launch creates, starts and fully connects the nodes of an alien network with
+// the given layout, then starts sealing on every sealer.
+func (n *Network) Launch(config *Config) error {
+	if config.Sealers <= 0 {
+		return errNoSealers
+	}
+	cfg := *config
+	if cfg.Period == 0 {
+		cfg.Period = 1
+	}
+	n.lock.Lock()
+	if n.genesis != nil {
+		n.lock.Unlock()
+		return errAlreadyLaunched
+	}
+	var confs []*adapters.NodeConfig
+	for i := 0; i < cfg.Sealers+cfg.Followers; i++ {
+		conf := adapters.RandomNodeConfig()
+		conf.Services = []string{serviceName}
+		if i < cfg.Sealers {
+			conf.Name = fmt.Sprintf("sealer-%d", i)
+			n.keys[conf.ID] = conf.PrivateKey
+		} else {
+			conf.Name = fmt.Sprintf("follower-%d", i-cfg.Sealers)
+		}
+		confs = append(confs, conf)
+		n.nodes = append(n.nodes, conf.ID)
+	}
+	n.config = &cfg
+	n.genesis = newGenesis(&cfg, confs[:cfg.Sealers])
+	n.lock.Unlock()
+
+	for _, conf := range confs {
+		if _, err := n.NewNodeWithConfig(conf); err != nil {
+			return err
+		}
+		if err := n.Start(conf.ID); err != nil {
+			return err
+		}
+		n.wg.Add(1)
+		go n.watch(conf.ID)
+	}
+	if err := n.Heal(); err != nil {
+		return err
+	}
+	for _, id := range n.nodes[:cfg.Sealers] {
+		if err := n.Recover(id); err != nil {
+			return err
+		}
+	}
+	log.Info("Launched alien simulation", "sealers", cfg.Sealers, "followers", cfg.Followers, "period", cfg.Period)
+	return nil
+}
+
+// newGenesis assembles the genesis of a simulated network, voting in all the
+// sealers by themselves. Sealing starts a few seconds after launch.
+func newGenesis(config *Config, sealers []*adapters.NodeConfig) *core.Genesis {
+	timestamp := uint64(time.Now().Unix()) + 3
+	alienConfig := &params.AlienConfig{
+		Period:           config.Period,
+		Epoch:            30000,
+		MaxSignerCount:   uint64(len(sealers)),
+		MinVoterBalance:  big.NewInt(100),
+		GenesisTimestamp: timestamp,
+		LightConfig:      &params.AlienLightConfig{Alloc: make(map[common.UnprefixedAddress]params.GenesisAccount)},
+	}
+	alloc := make(core.GenesisAlloc)
+	for _, conf := range sealers {
+		addr := crypto.PubkeyToAddress(conf.PrivateKey.PublicKey)
+		alloc[addr] = core.GenesisAccount{Balance: sealerBalance}
+		alienConfig.SelfVoteSigners = append(alienConfig.SelfVoteSigners, common.UnprefixedAddress(addr))
+		alienConfig.LightConfig.Alloc[common.UnprefixedAddress(addr)] = params.GenesisAccount{Balance: sealerBalance.String()}
+	}
+	chainConfig := *params.AllAlienProtocolChanges
+	chainConfig.Alien = alienConfig
+
+	return &core.Genesis{
+		Config:    &chainConfig,
+		Timestamp: timestamp,
+		ExtraData: make([]byte, 32+65), // Vanity and seal, no signers in alien genesis
+		GasLimit:  params.GenesisGasLimit,
+		Alloc:     alloc,
+	}
+}
+
+// service returns the running alien service of the given node.
+func (n *Network) service(id discover.NodeID) (*service, error) {
+	node := n.GetNode(id)
+	if node == nil {
+		return nil, errUnknownNode
+	}
+	sim, ok := node.Node.(*adapters.SimNode)
+	if !ok {
+		return nil, fmt.Errorf("unexpected node type %T", node.Node)
+	}
+	for _, s := range sim.Services() {
+		if srv, ok := s.(*service); ok {
+			return srv, nil
+		}
+	}
+	return nil, fmt.Errorf("node not running: %s", id)
+}
+
+// Partition splits the network into the given groups of nodes, connecting the
+// nodes within a group and disconnecting the ones across groups. Nodes not
+// listed in any group are put together in a group of their own.
+func (n *Network) Partition(groups ...[]discover.NodeID) error {
+	n.lock.Lock()
+	if n.genesis == nil {
+		n.lock.Unlock()
+		return errNotLaunched
+	}
+	known := make(map[discover.NodeID]bool)
+	for _, id := range n.nodes {
+		known[id] = true
+	}
+	assigned := make(map[discover.NodeID]int)
+	for i, group := range groups {
+		for _, id := range group {
+			if !known[id] {
+				n.lock.Unlock()
+				return errUnknownNode
+			}
+			if _, ok := assigned[id]; ok {
+				n.lock.Unlock()
+				return fmt.Errorf("node %s listed in several groups", id.TerminalString())
+			}
+			assigned[id] = i
+		}
+	}
+	for _, id := range n.nodes {
+		if _, ok := assigned[id]; !ok {
+			assigned[id] = len(groups)
+		}
+	}
+	n.groups = assigned
+	nodes := n.nodes
+	n.lock.Unlock()
+
+	for i, one := range nodes {
+		for _, other := range nodes[i+1:] {
+			conn := n.GetConn(one, other)
+			connected := conn != nil && conn.Up
+			switch {
+			case assigned[one] == assigned[other] && !connected:
+				if err := n.Connect(one, other); err != nil {
+					return err
+				}
+			case assigned[one] != assigned[other] && connected:
+				if err := n.Disconnect(one, other); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Heal reconnects all the nodes of the network into a single group.
+func (n *Network) Heal() error {
+	return n.Partition()
+}
+
+// Outage stops the given sealer from sealing, while it keeps following the
+// chain. The sealer misses its slots until it is recovered.
+func (n *Network) Outage(id discover.NodeID) error {
+	srv, err := n.service(id)
+	if err != nil {
+		return err
+	}
+	if !srv.sealer {
+		return errNotSealer
+	}
+	srv.StopMining()
+	log.Info("Injected alien signer outage", "node", id.TerminalString(), "signer", srv.signer)
+	return nil
+}
+
+// Recover (re)starts sealing on the given sealer.
+func (n *Network) Recover(id discover.NodeID) error {
+	srv, err := n.service(id)
+	if err != nil {
+		return err
+	}
+	if !srv.sealer {
+		return errNotSealer
+	}
+	srv.Miner().Start(srv.signer)
+	return nil
+}
+
+// Status returns the consensus view of every node of the network.
+func (n *Network) Status() []*NodeStatus {
+	n.lock.RLock()
+	nodes, groups := n.nodes, n.groups
+	n.lock.RUnlock()
+
+	status := make([]*NodeStatus, 0, len(nodes))
+	for _, id := range nodes {
+		node := n.GetNode(id)
+		if node == nil {
+			continue
+		}
+		s := &NodeStatus{
+			ID:     id,
+			Name:   node.Config.Name,
+			Up:     node.Up,
+			Signer: crypto.PubkeyToAddress(node.Config.PrivateKey.PublicKey),
+			Group:  groups[id],
+		}
+		if srv, err := n.service(id); err == nil {
+			head := srv.BlockChain().CurrentBlock()
+			s.Sealer, s.Sealing = srv.sealer, srv.IsMining()
+			s.Number, s.Hash = head.NumberU64(), head.Hash()
+		}
+		status = append(status, s)
+	}
+	return status
+}
+
+// Shutdown stops the consensus event watchers and all the nodes.
+func (n *Network) Shutdown() {
+	close(n.quit)
+	n.wg.Wait()
+	n.Network.Shutdown()
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package aliensim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// post sends a JSON request to the given endpoint of the test server, and
+// decodes the returned node status.
+func post(t *testing.T, srv *httptest.Server, path string, body interface{}) []*NodeStatus {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to encode request: %v", err)
+	}
+	res, err := http.Post(srv.URL+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("POST %s: unexpected status %s", path, res.Status)
+	}
+	var status []*NodeStatus
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+		t.Fatalf("POST %s: failed to decode status: %v", path, err)
+	}
+	return status
+}
+
+// waitEvent waits for a consensus event matching the given filter.
+func waitEvent(t *testing.T, events chan *Event, timeout time.Duration, match func(*Event) bool) *Event {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case event := <-events:
+			if match(event) {
+				return event
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for consensus event")
+		}
+	}
+}
+
+// Tests launching an alien network over the HTTP API, injecting a signer
+// outage and a partition, and observing the resulting consensus events.
+func TestAlienNetwork(t *testing.T) {
+	if testing.Short() {
+		t.Skip("alien network simulation runs in real time")
+	}
+	network := NewNetwork()
+	defer network.Shutdown()
+	srv := httptest.NewServer(NewServer(network))
+	defer srv.Close()
+
+	events := make(chan *Event, 256)
+	sub := network.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	status := post(t, srv, "/alien/launch", &Config{Sealers: 3, Followers: 1, Period: 1})
+	if len(status) != 4 {
+		t.Fatalf("node count mismatch: have %d, want %d", len(status), 4)
+	}
+	for i, node := range status {
+		if node.Sealer != (i < 3) || node.Sealing != (i < 3) {
+			t.Fatalf("node %s: sealer %v, sealing %v", node.Name, node.Sealer, node.Sealing)
+		}
+	}
+	waitEvent(t, events, 30*time.Second, func(event *Event) bool {
+		return event.Type == EventNewLoop && len(event.Signers) == 3
+	})
+
+	// Take a sealer down and wait for it to be punished
+	outage := status[2]
+	post(t, srv, fmt.Sprintf("/alien/nodes/%s/outage", outage.Name), nil)
+	waitEvent(t, events, 30*time.Second, func(event *Event) bool {
+		return event.Type == EventPunish && *event.Signer == outage.Signer
+	})
+	post(t, srv, fmt.Sprintf("/alien/nodes/%s/recover", outage.Name), nil)
+
+	// Split the follower away, then heal and wait for it to catch up
+	status = post(t, srv, "/alien/partition", &Partition{Groups: [][]string{{"sealer-0", "sealer-1", "sealer-2"}}})
+	if status[3].Group != 1 {
+		t.Fatalf("follower group mismatch: have %d, want %d", status[3].Group, 1)
+	}
+	stalled := status[3].Number
+	time.Sleep(3 * time.Second)
+	if status = network.Status(); status[3].Number != stalled {
+		t.Fatalf("partitioned follower advanced: have %d, want %d", status[3].Number, stalled)
+	}
+	post(t, srv, "/alien/heal", nil)
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		status = network.Status()
+		if status[3].Number > stalled && status[3].Hash == status[0].Hash {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("follower did not catch up: have #%d, sealer at #%d", status[3].Number, status[0].Number)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"encoding/binary"
+
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/crypto"
+)
+
+// AlienCheckpoint is a trusted block of an alien chain along with the hash of
+// the alien snapshot after it. Syncing nodes start from the snapshot and refuse
+// any chain without the block.
+type AlienCheckpoint struct {
+	Number       uint64          `json:"number"`
+	Hash         common.Hash     `json:"hash"`
+	SnapshotHash common.Hash     `json:"snapshotHash"`
+	Signatures   []hexutil.Bytes `json:"signatures,omitempty"` // Signatures of the checkpoint keys over SigHash
+}
+
+// SigHash returns the hash the checkpoint keys sign, covering every field but
+// the signatures.
+func (c *AlienCheckpoint) SigHash() common.Hash {
+	var number [8]byte
+	binary.BigEndian.PutUint64(number[:], c.Number)
+	return crypto.Keccak256Hash(number[:], c.Hash[:], c.SnapshotHash[:])
+}
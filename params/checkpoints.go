@@ -0,0 +1,42 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import "github.com/TTCECO/gttc/common"
+
+// The alien checkpoints shipped with the release. They are data only, updated
+// once the signers of a network publish a checkpoint signed by its checkpoint
+// keys; the format and its verification live in checkpoint.go and the alien
+// engine.
+//
+// None is published yet: the main and test networks configure no checkpoint
+// keys, so their nodes sync without a checkpoint unless one is given with
+// --alien.checkpoint on a chain configuring the keys.
+var (
+	// MainnetAlienCheckpoint is the alien checkpoint of the main network.
+	MainnetAlienCheckpoint *AlienCheckpoint
+
+	// TestnetAlienCheckpoint is the alien checkpoint of the test network.
+	TestnetAlienCheckpoint *AlienCheckpoint
+)
+
+// TrustedAlienCheckpoints are the alien checkpoints shipped with the release,
+// keyed by the genesis hash of the chain they belong to.
+var TrustedAlienCheckpoints = map[common.Hash]*AlienCheckpoint{
+	MainnetGenesisHash: MainnetAlienCheckpoint,
+	TestnetGenesisHash: TestnetAlienCheckpoint,
+}
@@ -254,9 +254,27 @@ type AlienConfig struct {
 	MCRPCClient      *rpc.Client                // Main chain rpc client for side chain
 	PBFTEnable       bool                       `json:"pbft"` //
 
-	TrantorBlock  *big.Int          `json:"trantorBlock,omitempty"`  // Trantor switch block (nil = no fork)
-	TerminusBlock *big.Int          `json:"terminusBlock,omitempty"` // Terminus switch block (nil = no fork)
-	LightConfig   *AlienLightConfig `json:"lightConfig,omitempty"`
+	TrantorBlock           *big.Int          `json:"trantorBlock,omitempty"`           // Trantor switch block (nil = no fork)
+	TerminusBlock          *big.Int          `json:"terminusBlock,omitempty"`          // Terminus switch block (nil = no fork)
+	SnapshotCommitBlock    *big.Int          `json:"snapshotCommitBlock,omitempty"`    // Header extra commits to the parent snapshot hash from this block (nil = no fork)
+	BackupSealBlock        *big.Int          `json:"backupSealBlock,omitempty"`        // Next signer may seal a slot its in-turn signer missed from this block (nil = no fork)
+	BackupSealDelay        uint64            `json:"backupSealDelay,omitempty"`        // Seconds into a slot before its backup signer may seal (0 = half a period)
+	ProposalLifecycleBlock *big.Int          `json:"proposalLifecycleBlock,omitempty"` // Proposals may be withdrawn and declarations changed from this block (nil = no fork)
+	ProposalQuorumBlock    *big.Int          `json:"proposalQuorumBlock,omitempty"`    // Proposals are decided by quorum, approval and veto thresholds from this block (nil = no fork)
+	LightConfig            *AlienLightConfig `json:"lightConfig,omitempty"`
+
+	CheckpointSigners   []common.Address `json:"checkpointSigners,omitempty"`   // Keys allowed to sign trusted checkpoints (none on the main and test networks yet)
+	CheckpointThreshold uint64           `json:"checkpointThreshold,omitempty"` // Signatures required on a checkpoint (0 = majority of the keys)
+
+	ProposalThresholds map[uint64]*AlienProposalThreshold `json:"proposalThresholds,omitempty"` // Decision rule by proposal type after ProposalQuorum (type 0 = all unlisted types)
+}
+
+// AlienProposalThreshold is the rule deciding an alien proposal at its deadline.
+// All ratios are counted in per thousand of the stake.
+type AlienProposalThreshold struct {
+	Quorum   uint64 `json:"quorum"`   // Declared stake, abstentions included, required out of the stake of all candidates
+	Approval uint64 `json:"approval"` // Yes stake required out of the yes and no stake, exceeded to pass
+	Veto     uint64 `json:"veto"`     // No stake out of the declared stake rejecting the proposal when exceeded (0 = no veto)
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -274,6 +292,26 @@ func (a *AlienConfig) IsTerminus(num *big.Int) bool {
 	return isForked(a.TerminusBlock, num)
 }
 
+// IsSnapshotCommit returns whether num is either equal to the SnapshotCommit block or greater.
+func (a *AlienConfig) IsSnapshotCommit(num *big.Int) bool {
+	return isForked(a.SnapshotCommitBlock, num)
+}
+
+// IsBackupSeal returns whether num is either equal to the BackupSeal block or greater.
+func (a *AlienConfig) IsBackupSeal(num *big.Int) bool {
+	return isForked(a.BackupSealBlock, num)
+}
+
+// IsProposalQuorum returns whether num is either equal to the ProposalQuorum block or greater.
+func (a *AlienConfig) IsProposalQuorum(num *big.Int) bool {
+	return isForked(a.ProposalQuorumBlock, num)
+}
+
+// IsProposalLifecycle returns whether num is either equal to the ProposalLifecycle block or greater.
+func (a *AlienConfig) IsProposalLifecycle(num *big.Int) bool {
+	return isForked(a.ProposalLifecycleBlock, num)
+}
+
 // String implements the fmt.Stringer interface.
 func (c *ChainConfig) String() string {
 	var engine interface{}
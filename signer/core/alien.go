@@ -0,0 +1,243 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/rlp"
+	"github.com/TTCECO/gttc/rpc"
+)
+
+// alienDepositWarnLimit is the proposal deposit above which the signer warns,
+// ten times the default deposit of 10000 TTC.
+var alienDepositWarnLimit = new(big.Int).Mul(big.NewInt(1e+18), big.NewInt(1e+5))
+
+// alienProposalTypes are the human readable names of the alien proposal types.
+var alienProposalTypes = map[uint64]string{
+	1: "add candidate",
+	2: "remove candidate",
+	3: "modify miner reward distribution",
+	4: "add side chain",
+	5: "remove side chain",
+	6: "modify min voter balance",
+	7: "modify proposal deposit",
+	8: "rent side chain",
+}
+
+// AlienBackend provides the alien consensus state to validate custom
+// transactions against, typically the alien API of a node.
+type AlienBackend interface {
+	// Snapshot returns the alien snapshot at the current head.
+	Snapshot() (*alien.Snapshot, error)
+}
+
+// rpcAlienBackend is an AlienBackend retrieving the snapshot over RPC.
+type rpcAlienBackend struct {
+	client *rpc.Client
+}
+
+// NewAlienRPCBackend creates an AlienBackend retrieving the snapshot from the
+// alien API of the node the client is connected to.
+func NewAlienRPCBackend(client *rpc.Client) AlienBackend {
+	return &rpcAlienBackend{client: client}
+}
+
+// Snapshot implements AlienBackend, retrieving the snapshot at the current head.
+func (b *rpcAlienBackend) Snapshot() (*alien.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	snap := new(alien.Snapshot)
+	if err := b.client.CallContext(ctx, snap, "alien_getSnapshot", nil); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// validateAlienTx decodes an alien custom tx into human readable messages, and
+// warns about the suspicious ones. If an alien backend is set, the tx is also
+// checked against the current consensus state.
+func (v *Validator) validateAlienTx(msgs *ValidationMessages, txargs *SendTxArgs, data []byte) {
+	var to *common.Address
+	if txargs.To != nil {
+		address := txargs.To.Address()
+		to = &address
+	}
+	from, value := txargs.From.Address(), txargs.Value.ToInt()
+
+	decoded, err := alien.DecodeCustomTx(data, from, to, value)
+	if err != nil {
+		msgs.crit(fmt.Sprintf("Tx contains invalid alien custom data: %v", err))
+		return
+	}
+	msgs.Alien = decoded
+
+	switch {
+	case decoded.Candidate != nil:
+		msgs.info(fmt.Sprintf("Alien vote for candidate %s", decoded.Candidate.Hex()))
+		if value.Sign() > 0 {
+			msgs.warn(fmt.Sprintf("Alien vote also transfers %v wei to the candidate", value))
+		}
+	case decoded.Proposal != nil:
+		kind, ok := alienProposalTypes[decoded.Proposal.ProposalType]
+		if !ok {
+			kind = fmt.Sprintf("unknown type %d", decoded.Proposal.ProposalType)
+		}
+		msgs.info(fmt.Sprintf("Alien proposal to %s, validated for %d loops, freezing a deposit of %v wei", kind, decoded.Proposal.ValidationLoopCnt, decoded.Deposit))
+		if !ok {
+			msgs.warn(fmt.Sprintf("Alien proposal has unknown type %d", decoded.Proposal.ProposalType))
+		}
+		if decoded.Deposit.Cmp(alienDepositWarnLimit) > 0 {
+			msgs.warn(fmt.Sprintf("Alien proposal freezes a huge deposit of %v wei", decoded.Deposit))
+		}
+	case decoded.Declare != nil:
+		decision := "no"
+		if decoded.Declare.Abstain {
+			decision = "abstain"
+		} else if decoded.Declare.Decision {
+			decision = "yes"
+		}
+		msgs.info(fmt.Sprintf("Alien declaration of %s on proposal %s", decision, decoded.Declare.ProposalHash.Hex()))
+	case decoded.Withdraw != nil:
+		msgs.info(fmt.Sprintf("Alien withdrawal of proposal %s, refunding part of its deposit", decoded.Withdraw.ProposalHash.Hex()))
+	case decoded.Coinbase != nil:
+		msgs.info(fmt.Sprintf("Alien coinbase %s set on side chain %s", decoded.Coinbase.Hex(), decoded.SideChain.Hex()))
+	case decoded.SideChain != nil:
+		msgs.info(fmt.Sprintf("Alien confirmation of side chain %s block #%d", decoded.SideChain.Hex(), *decoded.Number))
+	case decoded.Number != nil:
+		msgs.info(fmt.Sprintf("Alien confirmation of block #%d", *decoded.Number))
+	default:
+		msgs.info(fmt.Sprintf("Alien %s %s tx", decoded.Category, decoded.Action))
+	}
+	if v.alien == nil {
+		return
+	}
+	snap, err := v.alien.Snapshot()
+	if err != nil {
+		msgs.warn(fmt.Sprintf("Failed to retrieve the alien snapshot, tx not checked against the consensus state: %v", err))
+		return
+	}
+	v.checkAlienTx(msgs, snap, from, decoded)
+}
+
+// checkAlienTx checks a decoded alien custom tx against the given snapshot,
+// warning about the txs which would be ignored or look suspicious.
+func (v *Validator) checkAlienTx(msgs *ValidationMessages, snap *alien.Snapshot, from common.Address, decoded *alien.CustomTx) {
+	_, candidate := snap.Candidates[from]
+
+	switch {
+	case decoded.Candidate != nil:
+		if _, ok := snap.Candidates[*decoded.Candidate]; !ok {
+			msgs.warn(fmt.Sprintf("Alien vote for %s, which is not a candidate", decoded.Candidate.Hex()))
+		}
+	case decoded.Proposal != nil:
+		target := decoded.Proposal.TargetAddress
+		_, isCandidate := snap.Candidates[target]
+		switch decoded.Proposal.ProposalType {
+		case 1:
+			if isCandidate {
+				msgs.warn(fmt.Sprintf("Alien proposal to add %s, which is already a candidate", target.Hex()))
+			}
+		case 2:
+			if !isCandidate {
+				msgs.warn(fmt.Sprintf("Alien proposal to remove %s, which is not a candidate", target.Hex()))
+			}
+		case 8:
+			if _, ok := snap.SCRecordMap[decoded.Proposal.SCHash]; !ok {
+				msgs.warn(fmt.Sprintf("Alien proposal to rent side chain %s, which does not exist", decoded.Proposal.SCHash.Hex()))
+			}
+		}
+	case decoded.Declare != nil:
+		if !candidate {
+			msgs.warn("Alien declaration from a non-candidate will be ignored")
+		}
+		if _, ok := snap.Proposals[decoded.Declare.ProposalHash]; !ok {
+			msgs.warn(fmt.Sprintf("Alien declaration on %s, which is not an open proposal", decoded.Declare.ProposalHash.Hex()))
+		}
+	case decoded.Withdraw != nil:
+		proposal, ok := snap.Proposals[decoded.Withdraw.ProposalHash]
+		if !ok {
+			msgs.warn(fmt.Sprintf("Alien withdrawal of %s, which is not an open proposal", decoded.Withdraw.ProposalHash.Hex()))
+		} else if proposal.Proposer != from {
+			msgs.warn(fmt.Sprintf("Alien withdrawal of %s, which was proposed by %s", decoded.Withdraw.ProposalHash.Hex(), proposal.Proposer.Hex()))
+		}
+	case decoded.SideChain != nil:
+		if _, ok := snap.SCRecordMap[*decoded.SideChain]; !ok {
+			msgs.warn(fmt.Sprintf("Alien side chain %s does not exist", decoded.SideChain.Hex()))
+		}
+		if decoded.Coinbase != nil && !candidate {
+			msgs.warn("Alien side chain coinbase set by a non-candidate will be ignored")
+		}
+	case decoded.Number != nil:
+		if !candidate {
+			msgs.warn("Alien confirmation from a non-candidate will be ignored")
+		}
+	}
+}
+
+// SignAlienHeader seals an RLP encoded alien header with the given account,
+// which must be the coinbase of the header. Unlike Sign, the signature is made
+// over the raw seal hash of the header and its V value is left as 0/1, which is
+// what the alien engine expects.
+func (api *SignerAPI) SignAlienHeader(ctx context.Context, addr common.MixedcaseAddress, header hexutil.Bytes) (hexutil.Bytes, error) {
+	h := new(types.Header)
+	if err := rlp.DecodeBytes(header, h); err != nil {
+		return nil, fmt.Errorf("invalid alien header: %v", err)
+	}
+	if h.Number == nil {
+		return nil, fmt.Errorf("invalid alien header: missing number")
+	}
+	if h.Coinbase != addr.Address() {
+		return nil, fmt.Errorf("alien header coinbase %s does not match signer %s", h.Coinbase.Hex(), addr.Address().Hex())
+	}
+	sighash, err := alien.SealHash(h)
+	if err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("Seal alien block #%d (parent %s, time %v) as %s", h.Number, h.ParentHash.Hex(), h.Time, h.Coinbase.Hex())
+
+	// We make the request prior to looking up if we actually have the account, to prevent
+	// account-enumeration via the API
+	req := &SignDataRequest{Address: addr, Rawdata: header, Message: msg, Hash: sighash.Bytes(), Meta: MetadataFromContext(ctx)}
+	res, err := api.UI.ApproveSignData(req)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Approved {
+		return nil, ErrRequestDenied
+	}
+	account := accounts.Account{Address: addr.Address()}
+	wallet, err := api.am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := wallet.SignHashWithPassphrase(account, res.Password, sighash.Bytes())
+	if err != nil {
+		api.UI.ShowError(err.Error())
+		return nil, err
+	}
+	return signature, nil
+}
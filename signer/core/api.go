@@ -30,6 +30,7 @@ import (
 	"github.com/TTCECO/gttc/accounts/usbwallet"
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
 	"github.com/TTCECO/gttc/crypto"
 	"github.com/TTCECO/gttc/internal/ethapi"
 	"github.com/TTCECO/gttc/log"
@@ -46,6 +47,8 @@ type ExternalAPI interface {
 	SignTransaction(ctx context.Context, args SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error)
 	// Sign - request to sign the given data (plus prefix)
 	Sign(ctx context.Context, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error)
+	// SignAlienHeader - request to seal the given RLP encoded alien header
+	SignAlienHeader(ctx context.Context, addr common.MixedcaseAddress, header hexutil.Bytes) (hexutil.Bytes, error)
 	// EcRecover - request to perform ecrecover
 	EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error)
 	// Export - request to export an account
@@ -128,6 +131,7 @@ type (
 		Transaction SendTxArgs       `json:"transaction"`
 		Callinfo    []ValidationInfo `json:"call_info"`
 		Meta        Metadata         `json:"meta"`
+		Alien       *alien.CustomTx  `json:"alien,omitempty"` // Decoded alien custom tx, if the tx is one
 	}
 	// SignTxResponse result from SignTxRequest
 	SignTxResponse struct {
@@ -234,6 +238,11 @@ func NewSignerAPI(chainID int64, ksLocation string, noUSB bool, ui SignerUI, abi
 	return &SignerAPI{big.NewInt(chainID), accounts.NewManager(backends...), ui, NewValidator(abidb)}
 }
 
+// SetAlienBackend sets the consensus state to check alien custom txs against.
+func (api *SignerAPI) SetAlienBackend(backend AlienBackend) {
+	api.validator.SetAlienBackend(backend)
+}
+
 // List returns the set of wallet this signer manages. Each wallet can contain
 // multiple accounts.
 func (api *SignerAPI) List(ctx context.Context) (Accounts, error) {
@@ -335,6 +344,7 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 		Transaction: args,
 		Meta:        MetadataFromContext(ctx),
 		Callinfo:    msgs.Messages,
+		Alien:       msgs.Alien,
 	}
 	// Process approval
 	result, err = api.UI.ApproveTx(&req)
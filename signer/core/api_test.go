@@ -31,7 +31,9 @@ import (
 	"github.com/TTCECO/gttc/cmd/utils"
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
 	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/crypto"
 	"github.com/TTCECO/gttc/internal/ethapi"
 	"github.com/TTCECO/gttc/rlp"
 )
@@ -254,6 +256,53 @@ func TestSignData(t *testing.T) {
 		t.Errorf("Expected 65 byte signature (got %d bytes)", len(h))
 	}
 }
+func TestSignAlienHeader(t *testing.T) {
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control <- "A"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := common.NewMixedcaseAddress(list[0].Address)
+
+	header := &types.Header{
+		Number:     big.NewInt(7),
+		Time:       big.NewInt(1546300800),
+		Difficulty: big.NewInt(1),
+		Coinbase:   common.HexToAddress("0x1337"),
+		Extra:      make([]byte, 32+65),
+	}
+	enc, _ := rlp.EncodeToBytes(header)
+	// Headers mined by somebody else are refused without asking the UI
+	if _, err := api.SignAlienHeader(context.Background(), a, enc); err == nil {
+		t.Errorf("Expected error for foreign coinbase")
+	}
+	header.Coinbase = list[0].Address
+	enc, _ = rlp.EncodeToBytes(header)
+
+	control <- "No way"
+	if _, err := api.SignAlienHeader(context.Background(), a, enc); err != ErrRequestDenied {
+		t.Errorf("Expected ErrRequestDenied! %v", err)
+	}
+	control <- "Y"
+	control <- "apassword"
+	seal, err := api.SignAlienHeader(context.Background(), a, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seal) != 65 || seal[64] > 1 {
+		t.Fatalf("Expected 65 byte seal with V 0/1, got %x", seal)
+	}
+	hash, _ := alien.SealHash(header)
+	pubkey, err := crypto.SigToPub(hash.Bytes(), seal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubkey); signer != list[0].Address {
+		t.Errorf("Seal recovers to %x, expected %x", signer, list[0].Address)
+	}
+}
 func mkTestTx(from common.MixedcaseAddress) SendTxArgs {
 	to := common.NewMixedcaseAddress(common.HexToAddress("0x1337"))
 	gas := hexutil.Uint64(21000)
@@ -72,6 +72,14 @@ func (l *AuditLogger) Sign(ctx context.Context, addr common.MixedcaseAddress, da
 	return b, e
 }
 
+func (l *AuditLogger) SignAlienHeader(ctx context.Context, addr common.MixedcaseAddress, header hexutil.Bytes) (hexutil.Bytes, error) {
+	l.log.Info("SignAlienHeader", "type", "request", "metadata", MetadataFromContext(ctx).String(),
+		"addr", addr.String(), "header", common.Bytes2Hex(header))
+	b, e := l.api.SignAlienHeader(ctx, addr, header)
+	l.log.Info("SignAlienHeader", "type", "response", "data", common.Bytes2Hex(b), "error", e)
+	return b, e
+}
+
 func (l *AuditLogger) EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error) {
 	l.log.Info("EcRecover", "type", "request", "metadata", MetadataFromContext(ctx).String(),
 		"data", common.Bytes2Hex(data))
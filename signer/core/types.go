@@ -25,6 +25,7 @@ import (
 	"github.com/TTCECO/gttc/accounts"
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
 	"github.com/TTCECO/gttc/core/types"
 )
 
@@ -58,6 +59,7 @@ type ValidationInfo struct {
 }
 type ValidationMessages struct {
 	Messages []ValidationInfo
+	Alien    *alien.CustomTx // Decoded alien custom tx, if the tx is one
 }
 
 // SendTxArgs represents the arguments to submit a transaction
@@ -23,6 +23,7 @@ import (
 	"math/big"
 
 	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/consensus/alien"
 )
 
 // The validation package contains validation checks for transactions
@@ -41,11 +42,17 @@ func (vs *ValidationMessages) info(msg string) {
 }
 
 type Validator struct {
-	db *AbiDb
+	db    *AbiDb
+	alien AlienBackend // Consensus state to check alien custom txs against, if any
 }
 
 func NewValidator(db *AbiDb) *Validator {
-	return &Validator{db}
+	return &Validator{db: db}
+}
+
+// SetAlienBackend sets the consensus state to check alien custom txs against.
+func (v *Validator) SetAlienBackend(backend AlienBackend) {
+	v.alien = backend
 }
 func testSelector(selector string, data []byte) (*decodedCallData, error) {
 	if selector == "" {
@@ -139,6 +146,9 @@ func (v *Validator) validate(msgs *ValidationMessages, txargs *SendTxArgs, metho
 		if methodSelector != nil {
 			msgs.warn("Tx will create contract, but method selector supplied; indicating intent to call a method.")
 		}
+		if alien.IsCustomTx(data) {
+			v.validateAlienTx(msgs, txargs, data)
+		}
 
 	} else {
 		if !txargs.To.ValidChecksum() {
@@ -149,8 +159,12 @@ func (v *Validator) validate(msgs *ValidationMessages, txargs *SendTxArgs, metho
 			// Sending to 0
 			msgs.crit("Tx destination is the zero address!")
 		}
-		// Validate calldata
-		v.validateCallData(msgs, data, methodSelector)
+		// Validate calldata, alien custom txs are plain text instead of ABI
+		if alien.IsCustomTx(data) {
+			v.validateAlienTx(msgs, txargs, data)
+		} else {
+			v.validateCallData(msgs, data, methodSelector)
+		}
 	}
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/TTCECO/gttc/common"
 	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
 )
 
 func hexAddr(a string) common.Address { return common.BytesToAddress(common.FromHex(a)) }
@@ -137,3 +138,73 @@ func TestValidator(t *testing.T) {
 		}
 	}
 }
+
+// fakeAlienBackend is an AlienBackend serving a fixed snapshot.
+type fakeAlienBackend struct {
+	snap *alien.Snapshot
+}
+
+func (b *fakeAlienBackend) Snapshot() (*alien.Snapshot, error) { return b.snap, nil }
+
+func TestValidatorAlienTx(t *testing.T) {
+	var (
+		db, _     = NewEmptyAbiDB()
+		v         = NewValidator(db)
+		candidate = "0x000000000000000000000000000000000000dEaD"
+		other     = "0x0000000000000000000000000000000000001337"
+	)
+	v.SetAlienBackend(&fakeAlienBackend{&alien.Snapshot{
+		Candidates: map[common.Address]uint64{hexAddr(candidate): 1},
+		Proposals:  map[common.Hash]*alien.Proposal{},
+	}})
+	testcases := []struct {
+		to, value, data string
+		action          string
+		crit, warn      int
+	}{
+		// Vote for a candidate
+		{to: candidate, value: "0x00", data: "ufo:1:event:vote", action: "vote"},
+		// Vote for a non-candidate, transferring value
+		{to: other, value: "0x01", data: "ufo:1:event:vote", action: "vote", warn: 2},
+		// Confirmation from a candidate
+		{to: candidate, value: "0x00", data: "ufo:1:event:confirm:100", action: "confirm"},
+		// Malformed confirmation
+		{to: candidate, value: "0x00", data: "ufo:1:event:confirm:abc", crit: 1},
+		// Proposal adding an existing candidate
+		{to: candidate, value: "0x00", data: "ufo:1:event:proposal:proposal_type:1:candidate:" + candidate, action: "proposal", warn: 1},
+		// Proposal renting an unknown side chain, freezing a huge deposit
+		{to: candidate, value: "0x00", data: "ufo:1:event:proposal:proposal_type:8:schash:" + common.HexToHash("0x01").Hex() + ":scrt:" + other + ":scrf:1000000",
+			action: "proposal", warn: 2},
+		// Declaration on an unknown proposal
+		{to: candidate, value: "0x00", data: "ufo:1:event:declare:hash:0x01:decision:yes", action: "declare", warn: 1},
+		// Unknown action
+		{to: candidate, value: "0x00", data: "ufo:1:event:unknown", crit: 1},
+	}
+	for i, test := range testcases {
+		args := dummyTxArgs(txtestcase{from: candidate, to: test.to, n: "0x01", g: "0x20", gp: "0x40", value: test.value,
+			d: hexutil.Encode([]byte(test.data))})
+		msgs, err := v.ValidateTransaction(args, nil)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		crit, warn := 0, 0
+		for _, msg := range msgs.Messages {
+			switch msg.Typ {
+			case "CRITICAL":
+				crit++
+			case "WARNING":
+				warn++
+			}
+		}
+		if crit != test.crit || warn != test.warn {
+			t.Errorf("test %d: messages mismatch: have %d/%d critical/warning, want %d/%d: %v", i, crit, warn, test.crit, test.warn, msgs.Messages)
+		}
+		if test.action == "" {
+			if msgs.Alien != nil {
+				t.Errorf("test %d: decoded invalid tx: %+v", i, msgs.Alien)
+			}
+		} else if msgs.Alien == nil || msgs.Alien.Action != test.action {
+			t.Errorf("test %d: decoded action mismatch: have %+v, want %s", i, msgs.Alien, test.action)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package remote implements a client of the external API of clef, which lets a
+// node sign alien blocks and confirmation transactions with keys held by clef.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/rlp"
+	"github.com/TTCECO/gttc/rpc"
+)
+
+// MinTimeout is the lower bound of the time a signing request may take, leaving
+// a human operator of clef some time to approve it.
+const MinTimeout = 3 * time.Second
+
+// sendTxArgs are the arguments of account_signTransaction, mirroring the
+// SendTxArgs of clef.
+type sendTxArgs struct {
+	From     common.MixedcaseAddress  `json:"from"`
+	To       *common.MixedcaseAddress `json:"to"`
+	Gas      hexutil.Uint64           `json:"gas"`
+	GasPrice hexutil.Big              `json:"gasPrice"`
+	Value    hexutil.Big              `json:"value"`
+	Nonce    hexutil.Uint64           `json:"nonce"`
+	Data     *hexutil.Bytes           `json:"data"`
+}
+
+// Signer signs alien headers and transactions through the external API of clef.
+type Signer struct {
+	client  *rpc.Client
+	timeout time.Duration
+}
+
+// Dial connects to the external API of clef at the given endpoint, which is
+// either the path of an IPC file or an HTTP URL.
+func Dial(endpoint string, timeout time.Duration) (*Signer, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(client, timeout), nil
+}
+
+// NewSigner creates a signer on top of an RPC client connected to clef. Each
+// signing request is aborted after the given timeout, at least MinTimeout.
+func NewSigner(client *rpc.Client, timeout time.Duration) *Signer {
+	if timeout < MinTimeout {
+		timeout = MinTimeout
+	}
+	return &Signer{client: client, timeout: timeout}
+}
+
+// Timeout returns the period after which a signing request is aborted.
+func (s *Signer) Timeout() time.Duration {
+	return s.timeout
+}
+
+// Close closes the connection to clef.
+func (s *Signer) Close() {
+	s.client.Close()
+}
+
+// Accounts returns the accounts clef exposes to this node.
+func (s *Signer) Accounts() ([]common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var res []common.Address
+	if err := s.client.CallContext(ctx, &res, "account_list"); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SignHeader seals an alien header with the given account. It implements the
+// alien.HeaderSignerFn callback.
+func (s *Signer) SignHeader(account accounts.Account, header *types.Header) ([]byte, error) {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var seal hexutil.Bytes
+	if err := s.client.CallContext(ctx, &seal, "account_signAlienHeader", common.NewMixedcaseAddress(account.Address), hexutil.Bytes(data)); err != nil {
+		return nil, err
+	}
+	if len(seal) != 65 {
+		return nil, fmt.Errorf("invalid seal length %d", len(seal))
+	}
+	return seal, nil
+}
+
+// SignTx signs a transaction with the given account. It implements the
+// alien.SignTxFn callback. Clef signs with the chain id it was started with, so
+// transactions for another chain, like the confirmations of a side chain sent
+// to the main chain, need a clef instance configured for that chain.
+func (s *Signer) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := sendTxArgs{
+		From:     common.NewMixedcaseAddress(account.Address),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+	}
+	if to := tx.To(); to != nil {
+		address := common.NewMixedcaseAddress(*to)
+		args.To = &address
+	}
+	if data := tx.Data(); len(data) > 0 {
+		input := hexutil.Bytes(data)
+		args.Data = &input
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var res struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := s.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(res.Raw, signed); err != nil {
+		return nil, err
+	}
+	// Make sure clef signed what we asked for, on the chain we asked for
+	if !sameTx(tx, signed) {
+		return nil, fmt.Errorf("remote signer returned a different transaction")
+	}
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer signed for another chain than %v: %v", chainID, err)
+	}
+	if sender != account.Address {
+		return nil, fmt.Errorf("remote signer signed with %s instead of %s", sender.Hex(), account.Address.Hex())
+	}
+	return signed, nil
+}
+
+// sameTx reports whether the two transactions carry the same payload, ignoring
+// their signatures.
+func sameTx(a, b *types.Transaction) bool {
+	if a.Nonce() != b.Nonce() || a.Gas() != b.Gas() || a.GasPrice().Cmp(b.GasPrice()) != 0 || a.Value().Cmp(b.Value()) != 0 {
+		return false
+	}
+	if (a.To() == nil) != (b.To() == nil) || (a.To() != nil && *a.To() != *b.To()) {
+		return false
+	}
+	return bytes.Equal(a.Data(), b.Data())
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 The gttc Authors
+// This file is part of the gttc library.
+//
+// The gttc library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gttc library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gttc library. If not, see <http://www.gnu.org/licenses/>.
+
+package remote
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/TTCECO/gttc/accounts"
+	"github.com/TTCECO/gttc/common"
+	"github.com/TTCECO/gttc/common/hexutil"
+	"github.com/TTCECO/gttc/consensus/alien"
+	"github.com/TTCECO/gttc/core/types"
+	"github.com/TTCECO/gttc/crypto"
+	"github.com/TTCECO/gttc/rlp"
+	"github.com/TTCECO/gttc/rpc"
+)
+
+// TxArgs exports the signing arguments to the RPC server of the tests.
+type TxArgs sendTxArgs
+
+// TestClef is a minimal external API signing everything with a single key.
+type TestClef struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+	gas     uint64 // Overrides the gas of the signed transactions if non zero
+}
+
+func (c *TestClef) SignAlienHeader(ctx context.Context, addr common.MixedcaseAddress, header hexutil.Bytes) (hexutil.Bytes, error) {
+	h := new(types.Header)
+	if err := rlp.DecodeBytes(header, h); err != nil {
+		return nil, err
+	}
+	hash, err := alien.SealHash(h)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash.Bytes(), c.key)
+}
+
+func (c *TestClef) SignTransaction(ctx context.Context, args TxArgs) (map[string]interface{}, error) {
+	gas := uint64(args.Gas)
+	if c.gas != 0 {
+		gas = c.gas
+	}
+	var data []byte
+	if args.Data != nil {
+		data = *args.Data
+	}
+	tx := types.NewTransaction(uint64(args.Nonce), args.To.Address(), (*big.Int)(&args.Value), gas, (*big.Int)(&args.GasPrice), data)
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(c.chainID), c.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := rlp.EncodeToBytes(signed)
+	return map[string]interface{}{"raw": hexutil.Bytes(raw)}, nil
+}
+
+func newTestSigner(t *testing.T, clef *TestClef) *Signer {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", clef); err != nil {
+		t.Fatal(err)
+	}
+	return NewSigner(rpc.DialInProc(server), time.Second)
+}
+
+func TestSignHeader(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	signer := newTestSigner(t, &TestClef{key: key, chainID: big.NewInt(1)})
+	defer signer.Close()
+
+	if signer.Timeout() != MinTimeout {
+		t.Errorf("timeout mismatch: have %v, want %v", signer.Timeout(), MinTimeout)
+	}
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       big.NewInt(1546300800),
+		Difficulty: big.NewInt(1),
+		Coinbase:   addr,
+		Extra:      make([]byte, 32+65),
+	}
+	seal, err := signer.SignHeader(accounts.Account{Address: addr}, header)
+	if err != nil {
+		t.Fatalf("failed to seal header: %v", err)
+	}
+	hash, _ := alien.SealHash(header)
+	pubkey, err := crypto.SigToPub(hash.Bytes(), seal)
+	if err != nil {
+		t.Fatalf("failed to recover sealer: %v", err)
+	}
+	if sealer := crypto.PubkeyToAddress(*pubkey); sealer != addr {
+		t.Errorf("sealer mismatch: have %x, want %x", sealer, addr)
+	}
+}
+
+func TestSignTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	tx := types.NewTransaction(3, addr, big.NewInt(0), 100000, big.NewInt(10000), []byte("ufo:1:event:confirm:12"))
+
+	clef := &TestClef{key: key, chainID: big.NewInt(8848)}
+	signer := newTestSigner(t, clef)
+	defer signer.Close()
+
+	signed, err := signer.SignTx(accounts.Account{Address: addr}, tx, big.NewInt(8848))
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	if !sameTx(tx, signed) {
+		t.Errorf("signed tx differs from the requested one")
+	}
+	// Transactions signed for another chain must be rejected
+	if _, err := signer.SignTx(accounts.Account{Address: addr}, tx, big.NewInt(1)); err == nil {
+		t.Errorf("accepted tx signed for another chain")
+	}
+	// Transactions signed by another account must be rejected
+	if _, err := signer.SignTx(accounts.Account{Address: common.HexToAddress("0x1337")}, tx, big.NewInt(8848)); err == nil {
+		t.Errorf("accepted tx signed by another account")
+	}
+	// Tampered transactions must be rejected
+	clef.gas = 21000
+	if _, err := signer.SignTx(accounts.Account{Address: addr}, tx, big.NewInt(8848)); err == nil {
+		t.Errorf("accepted tampered tx")
+	}
+}
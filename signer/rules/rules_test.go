@@ -196,6 +196,52 @@ func TestSignTxRequest(t *testing.T) {
 	}
 }
 
+// Tests that the decoded alien custom tx is available to the rules, allowing to
+// auto-approve block confirmations only.
+func TestAlienSignTxRequest(t *testing.T) {
+	js := `
+	function ApproveTx(r){
+		if(r.alien && r.alien.category == "event" && r.alien.action == "confirm"){ return "Approve"}
+		return "Reject"
+	}`
+
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	from, _ := mixAddr("0000000000000000000000000000000000001337")
+	for _, test := range []struct {
+		data     string
+		approved bool
+	}{
+		{"ufo:1:event:confirm:100", true},
+		{"ufo:1:event:vote", false},
+		{"", false},
+	} {
+		var (
+			db, _ = core.NewEmptyAbiDB()
+			data  = hexutil.Bytes(test.data)
+			args  = core.SendTxArgs{From: *from, To: from, Data: &data}
+		)
+		msgs, err := core.NewValidator(db).ValidateTransaction(&args, nil)
+		if err != nil {
+			t.Fatalf("%q: validation failed: %v", test.data, err)
+		}
+		resp, err := r.ApproveTx(&core.SignTxRequest{
+			Transaction: args,
+			Callinfo:    msgs.Messages,
+			Meta:        core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+			Alien:       msgs.Alien,
+		})
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", test.data, err)
+		}
+		if resp.Approved != test.approved {
+			t.Errorf("%q: approval mismatch: have %v, want %v", test.data, resp.Approved, test.approved)
+		}
+	}
+}
+
 type dummyUI struct {
 	calls []string
 }